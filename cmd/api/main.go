@@ -23,6 +23,7 @@ import (
 
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/database"
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 	"github.com/xyz-asif/gotodo/internal/routes"
@@ -33,6 +34,9 @@ import (
 	docs "github.com/xyz-asif/gotodo/docs"
 )
 
+// jobsShutdownTimeout bounds how long shutdown waits for background jobs to stop
+const jobsShutdownTimeout = 10 * time.Second
+
 func main() {
 	// Load config
 	cfg := config.Load()
@@ -86,11 +90,20 @@ func main() {
 	// Register all routes
 	routes.SetupRoutes(router, db.Database, cfg)
 
+	// Start background jobs, cancelled on shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs := routes.StartBackgroundJobs(jobsCtx, db.Database, cfg)
+
 	// config server
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
 		Handler: router,
 	}
+	// End open notification streams on shutdown; Shutdown does not wait for hijacked
+	// WebSocket connections and would otherwise wait out its timeout on SSE ones
+	srv.RegisterOnShutdown(notifications.DefaultHub().Close)
+
 	// start the server
 	go func() {
 		log.Printf("Server starting on port %s", cfg.Port)
@@ -106,12 +119,26 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	// if it takes less than 5 sec clear all the things so that we dont use or holding onto resources unnecessarily.
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+		log.Println("Server forced to shutdown:", err)
+	}
+
+	// Let background jobs finish what they are doing before the database disconnects
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-time.After(jobsShutdownTimeout):
+		log.Println("Background jobs did not stop in time")
 	}
 
 	log.Println("Server exited")
@@ -25,6 +25,44 @@ type Config struct {
 	CloudinaryUploadFolder     string
 	FrontendURL                string
 	DevMode                    bool
+
+	// Account deletion
+	AccountDeletionGraceDays    int // Days a deleted account can still be restored
+	AccountPurgeIntervalMinutes int // How often the purge job looks for accounts due for deletion
+
+	// Data export
+	ExportDir               string // Where export archives are written
+	ExportRetentionHours    int    // How long a finished export stays downloadable
+	ExportLinkExpiryMinutes int    // Lifetime of a signed download link
+
+	// Comments
+	CommentEditWindowMinutes int // How long after posting authors can edit a comment; 0 allows edits forever
+
+	// Notifications
+	PushEnabled               bool // Send new notifications to registered devices through FCM
+	NotificationRetentionDays int  // Days read notifications are kept; 0 keeps them forever
+
+	// Email
+	PublicAPIURL string // Absolute base URL of this API, for links in emails
+	MailDriver   string // "smtp", or "file" to write .eml files to MailDir
+	MailFrom     string
+	MailDir      string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// Email digest
+	DigestEnabled         bool
+	DigestIntervalMinutes int // How often the digest job looks for users due a digest
+
+	// Job queue
+	JobWorkers int // Workers processing queued background jobs (notification fan-out)
+
+	// Discover ranking
+	HotScoreGravity         float64 // How fast hot scores decay with the age of an anchor's latest content
+	HotScoreWindowHours     int     // Only engagement within this window counts towards hot scores
+	HotScoreIntervalMinutes int     // How often hot scores are recomputed
 }
 
 func Load() *Config {
@@ -34,6 +72,18 @@ func Load() *Config {
 
 	jwtExpireHours, _ := strconv.Atoi(getEnv("JWT_EXPIRE_HOURS", "72"))
 	refreshTokenExpireHours, _ := strconv.Atoi(getEnv("REFRESH_TOKEN_EXPIRE_HOURS", "168")) // 7 days default
+	accountDeletionGraceDays, _ := strconv.Atoi(getEnv("ACCOUNT_DELETION_GRACE_DAYS", "30"))
+	accountPurgeIntervalMinutes, _ := strconv.Atoi(getEnv("ACCOUNT_PURGE_INTERVAL_MINUTES", "60"))
+	exportRetentionHours, _ := strconv.Atoi(getEnv("EXPORT_RETENTION_HOURS", "168")) // 7 days default
+	exportLinkExpiryMinutes, _ := strconv.Atoi(getEnv("EXPORT_LINK_EXPIRY_MINUTES", "15"))
+	commentEditWindowMinutes, _ := strconv.Atoi(getEnv("COMMENT_EDIT_WINDOW_MINUTES", "60"))
+	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	digestIntervalMinutes, _ := strconv.Atoi(getEnv("DIGEST_INTERVAL_MINUTES", "60"))
+	notificationRetentionDays, _ := strconv.Atoi(getEnv("NOTIFICATION_RETENTION_DAYS", "90"))
+	jobWorkers, _ := strconv.Atoi(getEnv("JOB_WORKERS", "4"))
+	hotScoreGravity, _ := strconv.ParseFloat(getEnv("HOT_SCORE_GRAVITY", "1.8"), 64)
+	hotScoreWindowHours, _ := strconv.Atoi(getEnv("HOT_SCORE_WINDOW_HOURS", "72"))
+	hotScoreIntervalMinutes, _ := strconv.Atoi(getEnv("HOT_SCORE_INTERVAL_MINUTES", "10"))
 
 	return &Config{
 		Port:                       getEnv("PORT", "8080"),
@@ -52,6 +102,36 @@ func Load() *Config {
 		CloudinaryUploadFolder:     getEnv("CLOUDINARY_UPLOAD_FOLDER", "anchor"),
 		FrontendURL:                getEnv("FRONTEND_URL", "http://localhost:3000"),
 		DevMode:                    getEnv("DEV_MODE", "false") == "true",
+
+		AccountDeletionGraceDays:    accountDeletionGraceDays,
+		AccountPurgeIntervalMinutes: accountPurgeIntervalMinutes,
+
+		ExportDir:               getEnv("EXPORT_DIR", "./exports"),
+		ExportRetentionHours:    exportRetentionHours,
+		ExportLinkExpiryMinutes: exportLinkExpiryMinutes,
+
+		CommentEditWindowMinutes: commentEditWindowMinutes,
+
+		PushEnabled:               getEnv("PUSH_ENABLED", "false") == "true",
+		NotificationRetentionDays: notificationRetentionDays,
+
+		PublicAPIURL: getEnv("PUBLIC_API_URL", "http://localhost:8080"),
+		MailDriver:   getEnv("MAIL_DRIVER", "file"),
+		MailFrom:     getEnv("MAIL_FROM", "Anchor <no-reply@localhost>"),
+		MailDir:      getEnv("MAIL_DIR", "./mail"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     smtpPort,
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		DigestEnabled:         getEnv("DIGEST_ENABLED", "false") == "true",
+		DigestIntervalMinutes: digestIntervalMinutes,
+
+		JobWorkers: jobWorkers,
+
+		HotScoreGravity:         hotScoreGravity,
+		HotScoreWindowHours:     hotScoreWindowHours,
+		HotScoreIntervalMinutes: hotScoreIntervalMinutes,
 	}
 }
 
@@ -0,0 +1,85 @@
+package account_deletion
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Purge job status constants
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusFailed    = "failed"    // Retried after a backoff until the attempt limit is reached
+	StatusCompleted = "completed" // Account and data removed
+)
+
+// Purge steps. Each step is idempotent and recorded on the job once done,
+// so an interrupted purge resumes where it stopped.
+const (
+	StepCollect       = "collect"        // Remember whose counters need recounting
+	StepLikes         = "likes"          // Anchor and comment likes by the user
+	StepComments      = "comments"       // Anonymise the user's comments
+	StepFollows       = "follows"        // User follows in both directions
+	StepAnchorFollows = "anchor_follows" // Anchors the user follows
+	StepTagFollows    = "tag_follows"    // Tags the user follows
+	StepLists         = "lists"          // The user's lists and their membership of other lists
+	StepMutes         = "mutes"          // Mutes by the user and of the user
+	StepAnchors       = "anchors"        // The user's anchors with their likes, comments, follows and assets
+	StepNotifications = "notifications"  // Notifications received or triggered by the user
+	StepWebhooks      = "webhooks"       // The user's webhooks and their delivery log
+	StepFeed          = "feed"           // Anchors served to the user in the for_you feed
+	StepExports       = "exports"        // The user's data export jobs and archives
+	StepDigest        = "digest"         // The user's email digest state
+	StepSessions      = "sessions"       // Refresh tokens and personal access tokens
+	StepCounters      = "counters"       // Recount affected anchors, comments and users
+	StepUser          = "user"           // Profile media and the user document
+)
+
+// Steps lists the purge steps in execution order
+var Steps = []string{
+	StepCollect,
+	StepLikes,
+	StepComments,
+	StepFollows,
+	StepAnchorFollows,
+	StepTagFollows,
+	StepLists,
+	StepMutes,
+	StepAnchors,
+	StepNotifications,
+	StepWebhooks,
+	StepFeed,
+	StepExports,
+	StepDigest,
+	StepSessions,
+	StepCounters,
+	StepUser,
+}
+
+// PurgeJob tracks the permanent deletion of one account
+type PurgeJob struct {
+	ID                 primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	UserID             primitive.ObjectID   `bson:"userId" json:"userId"`
+	Status             string               `bson:"status" json:"status"`
+	CompletedSteps     []string             `bson:"completedSteps" json:"completedSteps"`
+	AffectedAnchorIDs  []primitive.ObjectID `bson:"affectedAnchorIds" json:"affectedAnchorIds"`
+	AffectedCommentIDs []primitive.ObjectID `bson:"affectedCommentIds" json:"affectedCommentIds"`
+	AffectedUserIDs    []primitive.ObjectID `bson:"affectedUserIds" json:"affectedUserIds"`
+	Attempts           int                  `bson:"attempts" json:"attempts"`
+	LastError          string               `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	LockedUntil        *time.Time           `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"` // Lease held by the worker running the job
+	CreatedAt          time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt          time.Time            `bson:"updatedAt" json:"updatedAt"`
+	CompletedAt        *time.Time           `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
+
+// IsStepDone reports whether the given step has already been completed
+func (j *PurgeJob) IsStepDone(step string) bool {
+	for _, s := range j.CompletedSteps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package account_deletion
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository handles database interactions for account purge jobs
+type Repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates repository and ensures indexes
+func NewRepository(db *mongo.Database) *Repository {
+	collection := db.Collection("account_purges")
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// One purge job per account
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Claim runnable jobs
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "lockedUntil", Value: 1}},
+		},
+	})
+
+	return &Repository{
+		collection: collection,
+	}
+}
+
+// EnqueueJob creates a pending purge job for the user unless one already exists
+func (r *Repository) EnqueueJob(ctx context.Context, userID primitive.ObjectID) error {
+	now := time.Now()
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"_id":                primitive.NewObjectID(),
+			"userId":             userID,
+			"status":             StatusPending,
+			"completedSteps":     []string{},
+			"affectedAnchorIds":  []primitive.ObjectID{},
+			"affectedCommentIds": []primitive.ObjectID{},
+			"affectedUserIds":    []primitive.ObjectID{},
+			"attempts":           0,
+			"createdAt":          now,
+			"updatedAt":          now,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"userId": userID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// ClaimJob leases the next runnable job: pending, failed and due for retry, or
+// running with an expired lease (its worker stopped). Returns nil when none is runnable.
+func (r *Repository) ClaimJob(ctx context.Context, lease time.Duration, maxAttempts int) (*PurgeJob, error) {
+	now := time.Now()
+	filter := bson.M{
+		"status":   bson.M{"$in": []string{StatusPending, StatusFailed, StatusRunning}},
+		"attempts": bson.M{"$lt": maxAttempts},
+		"$or": []bson.M{
+			{"lockedUntil": nil},
+			{"lockedUntil": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":      StatusRunning,
+			"lockedUntil": now.Add(lease),
+			"updatedAt":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job PurgeJob
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// SaveAffected records the anchors, comments and users whose counters change with the purge
+func (r *Repository) SaveAffected(ctx context.Context, jobID primitive.ObjectID, anchorIDs, commentIDs, userIDs []primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": jobID},
+		bson.M{"$set": bson.M{
+			"affectedAnchorIds":  anchorIDs,
+			"affectedCommentIds": commentIDs,
+			"affectedUserIds":    userIDs,
+			"updatedAt":          time.Now(),
+		}},
+	)
+	return err
+}
+
+// CompleteStep records a finished step and renews the worker's lease
+func (r *Repository) CompleteStep(ctx context.Context, jobID primitive.ObjectID, step string, lease time.Duration) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": jobID},
+		bson.M{
+			"$addToSet": bson.M{"completedSteps": step},
+			"$set":      bson.M{"lockedUntil": now.Add(lease), "updatedAt": now},
+		},
+	)
+	return err
+}
+
+// MarkCompleted marks the job as finished
+func (r *Repository) MarkCompleted(ctx context.Context, jobID primitive.ObjectID) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": jobID},
+		bson.M{
+			"$set":   bson.M{"status": StatusCompleted, "completedAt": now, "updatedAt": now},
+			"$unset": bson.M{"lockedUntil": "", "lastError": ""},
+		},
+	)
+	return err
+}
+
+// DeleteJob removes a job without purging, used when the account was restored
+// so that a later deletion request starts from scratch
+func (r *Repository) DeleteJob(ctx context.Context, jobID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": jobID})
+	return err
+}
+
+// MarkFailed records the error and keeps the job locked until retryAt
+func (r *Repository) MarkFailed(ctx context.Context, jobID primitive.ObjectID, lastError string, retryAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": jobID},
+		bson.M{"$set": bson.M{
+			"status":      StatusFailed,
+			"lastError":   lastError,
+			"lockedUntil": retryAt,
+			"updatedAt":   time.Now(),
+		}},
+	)
+	return err
+}
@@ -0,0 +1,382 @@
+package account_deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/anchor_follows"
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/comments"
+	"github.com/xyz-asif/gotodo/internal/features/data_export"
+	"github.com/xyz-asif/gotodo/internal/features/digest"
+	"github.com/xyz-asif/gotodo/internal/features/feed"
+	"github.com/xyz-asif/gotodo/internal/features/follows"
+	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/lists"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/tag_follows"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
+	"github.com/xyz-asif/gotodo/internal/pkg/cloudinary"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	enqueueBatchSize = 100              // Accounts picked up per scan
+	jobLease         = 10 * time.Minute // How long a worker owns a job between steps
+	maxAttempts      = 10               // Failed jobs are retried up to this many times
+	maxRetryBackoff  = time.Hour
+)
+
+// AnchorDeleter removes a user's anchors, items and uploaded assets
+type AnchorDeleter interface {
+	DeleteAllByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// Purger permanently deletes accounts whose restore window has passed
+type Purger struct {
+	repo              *Repository
+	authRepo          *auth.Repository
+	anchorsRepo       *anchors.Repository
+	likesRepo         *likes.Repository
+	commentsRepo      *comments.Repository
+	followsRepo       *follows.Repository
+	anchorFollowsRepo *anchor_follows.Repository
+	tagFollowsRepo    *tag_follows.Repository
+	listsRepo         *lists.Repository
+	mutesRepo         *mutes.Repository
+	notificationsRepo *notifications.Repository
+	webhooksRepo      *webhooks.Repository
+	feedRepo          *feed.Repository
+	exportService     *data_export.Service
+	digestRepo        *digest.Repository
+	anchorDeleter     AnchorDeleter
+	cloudinary        *cloudinary.Service
+	interval          time.Duration
+}
+
+// NewPurger creates the account purge job
+func NewPurger(db *mongo.Database, cfg *config.Config, anchorDeleter AnchorDeleter, cld *cloudinary.Service) *Purger {
+	interval := time.Duration(cfg.AccountPurgeIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &Purger{
+		repo:              NewRepository(db),
+		authRepo:          auth.NewRepository(db),
+		anchorsRepo:       anchors.NewRepository(db),
+		likesRepo:         likes.NewRepository(db),
+		commentsRepo:      comments.NewRepository(db),
+		followsRepo:       follows.NewRepository(db),
+		anchorFollowsRepo: anchor_follows.NewRepository(db),
+		tagFollowsRepo:    tag_follows.NewRepository(db),
+		listsRepo:         lists.NewRepository(db),
+		mutesRepo:         mutes.NewRepository(db),
+		notificationsRepo: notifications.NewRepository(db),
+		webhooksRepo:      webhooks.NewRepository(db),
+		feedRepo:          feed.NewRepository(db),
+		exportService:     data_export.NewService(db, cfg),
+		digestRepo:        digest.NewRepository(db),
+		anchorDeleter:     anchorDeleter,
+		cloudinary:        cld,
+		interval:          interval,
+	}
+}
+
+// Run purges due accounts on every tick until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce enqueues accounts that are due and works through all runnable jobs
+func (p *Purger) RunOnce(ctx context.Context) {
+	dueIDs, err := p.authRepo.GetUserIDsDueForDeletion(ctx, time.Now(), enqueueBatchSize)
+	if err != nil {
+		log.Printf("Account purge: failed to list due accounts: %v", err)
+	}
+	for _, userID := range dueIDs {
+		if err := p.repo.EnqueueJob(ctx, userID); err != nil {
+			log.Printf("Account purge: failed to enqueue user %s: %v", userID.Hex(), err)
+		}
+	}
+
+	for ctx.Err() == nil {
+		job, err := p.repo.ClaimJob(ctx, jobLease, maxAttempts)
+		if err != nil {
+			log.Printf("Account purge: failed to claim job: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		p.process(ctx, job)
+	}
+}
+
+// process runs a claimed job and records the outcome
+func (p *Purger) process(ctx context.Context, job *PurgeJob) {
+	err := p.runSteps(ctx, job)
+	if err == nil {
+		_ = p.repo.MarkCompleted(ctx, job.ID)
+		return
+	}
+
+	if errors.Is(err, errAccountRestored) {
+		_ = p.repo.DeleteJob(ctx, job.ID)
+		return
+	}
+
+	// Interrupted by shutdown: leave the job running, it resumes once the lease expires
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Back off exponentially between failed attempts
+	backoff := time.Duration(1<<uint(job.Attempts)) * time.Minute
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	log.Printf("Account purge: user %s failed (attempt %d): %v", job.UserID.Hex(), job.Attempts, err)
+	_ = p.repo.MarkFailed(ctx, job.ID, err.Error(), time.Now().Add(backoff))
+}
+
+var errAccountRestored = errors.New("account was restored")
+
+// runSteps executes every step that has not completed yet
+func (p *Purger) runSteps(ctx context.Context, job *PurgeJob) error {
+	var user *auth.User
+	if !job.IsStepDone(StepUser) {
+		// The user is missing only if a previous run got as far as deleting it. Any other
+		// error fails the job, so a restored account is never purged unchecked.
+		u, err := p.authRepo.GetUserByObjectID(ctx, job.UserID)
+		switch {
+		case err == nil:
+			if !u.IsDeletionDue(time.Now()) {
+				return errAccountRestored
+			}
+			user = u
+		case !errors.Is(err, auth.ErrUserNotFound):
+			return err
+		}
+	}
+
+	for _, step := range Steps {
+		if job.IsStepDone(step) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.runStep(ctx, job, user, step); err != nil {
+			return fmt.Errorf("%s: %w", step, err)
+		}
+		if err := p.repo.CompleteStep(ctx, job.ID, step, jobLease); err != nil {
+			return err
+		}
+		job.CompletedSteps = append(job.CompletedSteps, step)
+	}
+
+	return nil
+}
+
+// runStep executes a single idempotent purge step
+func (p *Purger) runStep(ctx context.Context, job *PurgeJob, user *auth.User, step string) error {
+	userID := job.UserID
+
+	switch step {
+	case StepCollect:
+		return p.collectAffected(ctx, job)
+
+	case StepLikes:
+		if err := p.likesRepo.DeleteAllByUser(ctx, userID); err != nil {
+			return err
+		}
+		return p.commentsRepo.DeleteCommentLikesByUser(ctx, userID)
+
+	case StepComments:
+		return p.commentsRepo.AnonymizeUserComments(ctx, userID)
+
+	case StepFollows:
+		return p.followsRepo.DeleteAllForUser(ctx, userID)
+
+	case StepAnchorFollows:
+		return p.anchorFollowsRepo.DeleteAllByUser(ctx, userID)
+
+	case StepTagFollows:
+		return p.tagFollowsRepo.DeleteAllForUser(ctx, userID)
+
+	case StepLists:
+		return p.listsRepo.DeleteAllForUser(ctx, userID)
+
+	case StepMutes:
+		return p.mutesRepo.DeleteAllForUser(ctx, userID)
+
+	case StepAnchors:
+		owned, err := p.anchorsRepo.GetAllUserAnchors(ctx, userID)
+		if err != nil {
+			return err
+		}
+		anchorIDs := make([]primitive.ObjectID, len(owned))
+		for i, a := range owned {
+			anchorIDs[i] = a.ID
+		}
+
+		if err := p.likesRepo.DeleteByAnchors(ctx, anchorIDs); err != nil {
+			return err
+		}
+		if err := p.commentsRepo.DeleteByAnchors(ctx, anchorIDs); err != nil {
+			return err
+		}
+		if err := p.anchorFollowsRepo.DeleteByAnchors(ctx, anchorIDs); err != nil {
+			return err
+		}
+		return p.anchorDeleter.DeleteAllByUser(ctx, userID)
+
+	case StepNotifications:
+		return p.notificationsRepo.DeleteAllForUser(ctx, userID)
+
+	case StepWebhooks:
+		return p.webhooksRepo.DeleteAllForUser(ctx, userID)
+
+	case StepFeed:
+		return p.feedRepo.DeleteImpressionsByUser(ctx, userID)
+
+	case StepExports:
+		return p.exportService.DeleteAllForUser(ctx, userID)
+
+	case StepDigest:
+		return p.digestRepo.DeleteState(ctx, userID)
+
+	case StepSessions:
+		if err := p.authRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+			return err
+		}
+		return p.authRepo.RevokeAllPersonalAccessTokens(ctx, userID)
+
+	case StepCounters:
+		return p.recountAffected(ctx, job)
+
+	case StepUser:
+		if user != nil && p.cloudinary != nil {
+			if user.ProfilePicturePublicID != "" {
+				_ = p.cloudinary.Delete(ctx, user.ProfilePicturePublicID, "image")
+			}
+			if user.CoverImagePublicID != "" {
+				_ = p.cloudinary.Delete(ctx, user.CoverImagePublicID, "image")
+			}
+		}
+		return p.authRepo.DeleteUser(ctx, userID)
+	}
+
+	return fmt.Errorf("unknown step %q", step)
+}
+
+// collectAffected records everything whose counters the purge will change,
+// before the relationships that identify them are removed
+func (p *Purger) collectAffected(ctx context.Context, job *PurgeJob) error {
+	likedAnchorIDs, err := p.likesRepo.GetLikedAnchorIDs(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	followedAnchorIDs, err := p.anchorFollowsRepo.GetFollowedAnchorIDs(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	likedCommentIDs, err := p.commentsRepo.GetLikedCommentIDs(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	followingIDs, err := p.followsRepo.GetAllFollowingIDs(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	followerIDs, err := p.followsRepo.GetAllFollowerIDs(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+
+	job.AffectedAnchorIDs = uniqueIDs(append(likedAnchorIDs, followedAnchorIDs...))
+	job.AffectedCommentIDs = uniqueIDs(likedCommentIDs)
+	job.AffectedUserIDs = uniqueIDs(append(followingIDs, followerIDs...))
+
+	return p.repo.SaveAffected(ctx, job.ID, job.AffectedAnchorIDs, job.AffectedCommentIDs, job.AffectedUserIDs)
+}
+
+// recountAffected recomputes counters from source collections, so reruns are safe
+func (p *Purger) recountAffected(ctx context.Context, job *PurgeJob) error {
+	if err := p.authRepo.RemoveFromBlockLists(ctx, job.UserID); err != nil {
+		return err
+	}
+
+	// Skip anchors that were deleted along with the account
+	remaining, err := p.anchorsRepo.GetAnchorsByIDs(ctx, job.AffectedAnchorIDs)
+	if err != nil {
+		return err
+	}
+	for _, anchor := range remaining {
+		likeCount, err := p.likesRepo.CountLikes(ctx, anchor.ID)
+		if err != nil {
+			return err
+		}
+		followerCount, err := p.anchorFollowsRepo.CountFollowers(ctx, anchor.ID)
+		if err != nil {
+			return err
+		}
+		if err := p.anchorsRepo.SetEngagementCounts(ctx, anchor.ID, likeCount, followerCount); err != nil {
+			return err
+		}
+	}
+
+	for _, commentID := range job.AffectedCommentIDs {
+		if err := p.commentsRepo.RecountCommentLikes(ctx, commentID); err != nil {
+			return err
+		}
+	}
+
+	for _, userID := range job.AffectedUserIDs {
+		followers, err := p.followsRepo.CountFollowers(ctx, userID)
+		if err != nil {
+			return err
+		}
+		following, err := p.followsRepo.CountFollowing(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := p.authRepo.SetFollowCounts(ctx, userID, followers, following); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uniqueIDs removes duplicate IDs while keeping order
+func uniqueIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	result := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
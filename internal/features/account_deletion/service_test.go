@@ -0,0 +1,42 @@
+package account_deletion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/features/comments"
+	"github.com/xyz-asif/gotodo/internal/pkg/testdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPurgeKeepsAnonymisedCommentsVisible(t *testing.T) {
+	ctx := context.Background()
+	commentsRepo := comments.NewRepository(testdb.New(t))
+	p := &Purger{commentsRepo: commentsRepo}
+
+	// Scheduling the deletion deactivated the account and hid its comments
+	userID := primitive.NewObjectID()
+	anchorID := primitive.NewObjectID()
+	comment := &comments.Comment{
+		AnchorID:          anchorID,
+		UserID:            userID,
+		Content:           "Great list",
+		Mentions:          []primitive.ObjectID{},
+		Status:            comments.StatusPublished,
+		AuthorDeactivated: true,
+	}
+	require.NoError(t, commentsRepo.CreateComment(ctx, comment))
+
+	job := &PurgeJob{ID: primitive.NewObjectID(), UserID: userID}
+	require.NoError(t, p.runStep(ctx, job, nil, StepComments))
+
+	viewerID := primitive.NewObjectID()
+	listed, total, err := commentsRepo.GetCommentsByAnchor(ctx, anchorID, nil, "", 1, 20, nil, comments.ModerationScope{ViewerID: &viewerID})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Len(t, listed, 1)
+	require.Equal(t, comment.ID, listed[0].ID)
+	require.True(t, listed[0].UserID.IsZero(), "shown as Deleted User")
+	require.False(t, listed[0].AuthorDeactivated)
+}
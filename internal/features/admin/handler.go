@@ -0,0 +1,514 @@
+package admin
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/comments"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler handles admin and moderation HTTP requests
+type Handler struct {
+	repo         *Repository
+	authRepo     *auth.Repository
+	anchorsRepo  *anchors.Repository
+	commentsRepo *comments.Repository
+	safetyRepo   *safety.Repository
+	config       *config.Config
+}
+
+// NewHandler creates a new admin handler
+func NewHandler(repo *Repository, authRepo *auth.Repository, anchorsRepo *anchors.Repository, commentsRepo *comments.Repository, safetyRepo *safety.Repository, cfg *config.Config) *Handler {
+	return &Handler{
+		repo:         repo,
+		authRepo:     authRepo,
+		anchorsRepo:  anchorsRepo,
+		commentsRepo: commentsRepo,
+		safetyRepo:   safetyRepo,
+		config:       cfg,
+	}
+}
+
+// audit writes an audit log entry for an admin action
+func (h *Handler) audit(c *gin.Context, actor *auth.User, action, targetType string, targetID *primitive.ObjectID, reason string, metadata map[string]interface{}) {
+	entry := &AuditLog{
+		ActorID:    actor.ID,
+		ActorRole:  actor.EffectiveRole(),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Metadata:   metadata,
+		IPAddress:  c.ClientIP(),
+	}
+
+	if err := h.repo.CreateAuditLog(c.Request.Context(), entry); err != nil {
+		log.Printf("Failed to write audit log (%s by %s): %v", action, actor.ID.Hex(), err)
+	}
+}
+
+// LookupUser godoc
+// @Summary Look up a user
+// @Description Find a user by ID, email or username (moderator or admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "User ID, email or username"
+// @Success 200 {object} response.APIResponse{data=AdminUserResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /admin/users/lookup [get]
+func (h *Handler) LookupUser(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	actor := val.(*auth.User)
+
+	var query UserLookupQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "Query parameter q is required", "INVALID_QUERY")
+		return
+	}
+
+	ctx := c.Request.Context()
+	q := strings.TrimSpace(query.Q)
+
+	var user *auth.User
+	var err error
+	if oid, parseErr := primitive.ObjectIDFromHex(q); parseErr == nil {
+		user, err = h.authRepo.GetUserByObjectID(ctx, oid)
+	} else if strings.Contains(q, "@") {
+		user, err = h.authRepo.GetUserByEmail(ctx, strings.ToLower(q))
+	} else {
+		user, err = h.authRepo.GetUserByUsername(ctx, strings.ToLower(strings.TrimPrefix(q, "@")))
+	}
+
+	if err != nil || user == nil {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	h.audit(c, actor, ActionUserLookup, TargetUser, &user.ID, "", map[string]interface{}{"query": q})
+
+	response.Success(c, AdminUserResponse{
+		User:             user,
+		Role:             user.EffectiveRole(),
+		SuspensionReason: user.SuspensionReason,
+	})
+}
+
+// SuspendUser godoc
+// @Summary Suspend a user
+// @Description Suspend an account and revoke its sessions (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body SuspendUserRequest true "Suspension reason"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /admin/users/{id}/suspend [post]
+func (h *Handler) SuspendUser(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	actor := val.(*auth.User)
+
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+
+	var req SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "A reason of 3-500 characters is required", "INVALID_REQUEST")
+		return
+	}
+
+	if userID == actor.ID {
+		response.BadRequest(c, "You cannot suspend yourself", "CANNOT_SUSPEND_SELF")
+		return
+	}
+
+	ctx := c.Request.Context()
+	target, err := h.authRepo.GetUserByObjectID(ctx, userID)
+	if err != nil {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	if target.HasRole(auth.RoleAdmin) {
+		response.Forbidden(c, "Admins cannot be suspended", "CANNOT_SUSPEND_ADMIN")
+		return
+	}
+
+	updates := map[string]interface{}{
+		"suspendedAt":      time.Now(),
+		"suspensionReason": req.Reason,
+	}
+	if err := h.authRepo.UpdateUser(ctx, userID.Hex(), updates); err != nil {
+		response.InternalServerError(c, "Failed to suspend user", "DATABASE_ERROR")
+		return
+	}
+
+	// End all existing sessions
+	_ = h.authRepo.RevokeAllUserTokens(ctx, userID)
+
+	h.audit(c, actor, ActionUserSuspend, TargetUser, &userID, req.Reason, nil)
+
+	response.Success(c, "User suspended")
+}
+
+// UnsuspendUser godoc
+// @Summary Unsuspend a user
+// @Description Lift an account suspension (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /admin/users/{id}/unsuspend [post]
+func (h *Handler) UnsuspendUser(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	actor := val.(*auth.User)
+
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+
+	ctx := c.Request.Context()
+	target, err := h.authRepo.GetUserByObjectID(ctx, userID)
+	if err != nil {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	if !target.IsSuspended() {
+		response.BadRequest(c, "User is not suspended", "NOT_SUSPENDED")
+		return
+	}
+
+	updates := map[string]interface{}{
+		"suspendedAt":      nil,
+		"suspensionReason": "",
+	}
+	if err := h.authRepo.UpdateUser(ctx, userID.Hex(), updates); err != nil {
+		response.InternalServerError(c, "Failed to unsuspend user", "DATABASE_ERROR")
+		return
+	}
+
+	h.audit(c, actor, ActionUserUnsuspend, TargetUser, &userID, "", nil)
+
+	response.Success(c, "User unsuspended")
+}
+
+// SetUserRole godoc
+// @Summary Change a user's role
+// @Description Set a user's role to user, moderator or admin (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body SetRoleRequest true "New role"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /admin/users/{id}/role [patch]
+func (h *Handler) SetUserRole(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	actor := val.(*auth.User)
+
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !auth.IsValidRole(req.Role) {
+		response.BadRequest(c, "Role must be one of: user, moderator, admin", "INVALID_ROLE")
+		return
+	}
+
+	// Prevent admins from locking themselves out
+	if userID == actor.ID {
+		response.BadRequest(c, "You cannot change your own role", "CANNOT_CHANGE_OWN_ROLE")
+		return
+	}
+
+	ctx := c.Request.Context()
+	target, err := h.authRepo.GetUserByObjectID(ctx, userID)
+	if err != nil {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	previousRole := target.EffectiveRole()
+	if err := h.authRepo.UpdateUser(ctx, userID.Hex(), map[string]interface{}{"role": req.Role}); err != nil {
+		response.InternalServerError(c, "Failed to update role", "DATABASE_ERROR")
+		return
+	}
+
+	h.audit(c, actor, ActionUserSetRole, TargetUser, &userID, "", map[string]interface{}{
+		"from": previousRole,
+		"to":   req.Role,
+	})
+
+	response.Success(c, gin.H{"id": userID, "role": req.Role})
+}
+
+// DeleteAnchor godoc
+// @Summary Force-delete an anchor
+// @Description Delete any anchor regardless of ownership (moderator or admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Anchor ID"
+// @Param reason query string false "Reason for deletion"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /admin/anchors/{id} [delete]
+func (h *Handler) DeleteAnchor(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	actor := val.(*auth.User)
+
+	anchorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid anchor ID", "INVALID_ID")
+		return
+	}
+
+	ctx := c.Request.Context()
+	anchor, err := h.anchorsRepo.GetAnchorByID(ctx, anchorID)
+	if err != nil || anchor.DeletedAt != nil {
+		response.NotFound(c, "Anchor not found", "ANCHOR_NOT_FOUND")
+		return
+	}
+
+	if err := h.anchorsRepo.SoftDeleteAnchor(ctx, anchorID); err != nil {
+		response.InternalServerError(c, "Failed to delete anchor", "DATABASE_ERROR")
+		return
+	}
+
+	if err := h.authRepo.IncrementAnchorCount(ctx, anchor.UserID, -1); err != nil {
+		log.Printf("Failed to decrement anchor count for user %s: %v", anchor.UserID.Hex(), err)
+	}
+
+	h.audit(c, actor, ActionAnchorDelete, TargetAnchor, &anchorID, c.Query("reason"), map[string]interface{}{
+		"ownerId": anchor.UserID,
+		"title":   anchor.Title,
+	})
+
+	response.Success(c, "Anchor deleted")
+}
+
+// DeleteComment godoc
+// @Summary Force-delete a comment
+// @Description Delete any comment regardless of ownership (moderator or admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Param reason query string false "Reason for deletion"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /admin/comments/{id} [delete]
+func (h *Handler) DeleteComment(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	actor := val.(*auth.User)
+
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid comment ID", "INVALID_ID")
+		return
+	}
+
+	ctx := c.Request.Context()
+	comment, err := h.commentsRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		response.NotFound(c, "Comment not found", "COMMENT_NOT_FOUND")
+		return
+	}
+
+	if err := h.commentsRepo.SoftDeleteComment(ctx, commentID); err != nil {
+		response.InternalServerError(c, "Failed to delete comment", "DATABASE_ERROR")
+		return
+	}
+
+	if comment.IsPublished() {
+		_ = h.anchorsRepo.IncrementCommentCount(ctx, comment.AnchorID, -1)
+		if comment.ItemID != nil {
+			_ = h.anchorsRepo.IncrementItemCommentCount(ctx, *comment.ItemID, -1)
+		}
+	}
+
+	// Update engagement score (async)
+	go func() {
+		_ = h.anchorsRepo.UpdateEngagementScore(context.Background(), comment.AnchorID)
+	}()
+
+	h.audit(c, actor, ActionCommentDelete, TargetComment, &commentID, c.Query("reason"), map[string]interface{}{
+		"authorId": comment.UserID,
+		"anchorId": comment.AnchorID,
+		"content":  comment.Content,
+	})
+
+	response.Success(c, "Comment deleted")
+}
+
+// ListReports godoc
+// @Summary List reports
+// @Description List user-submitted reports, newest first (moderator or admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (pending, reviewed)"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Success 200 {object} response.APIResponse{data=PaginatedResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /admin/reports [get]
+func (h *Handler) ListReports(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	actor := val.(*auth.User)
+
+	var query ReportListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error(), "INVALID_QUERY")
+		return
+	}
+
+	reports, total, err := h.safetyRepo.ListReports(c.Request.Context(), query.Status, query.Page, query.Limit)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch reports", "DATABASE_ERROR")
+		return
+	}
+
+	h.audit(c, actor, ActionReportsView, TargetReport, nil, "", map[string]interface{}{
+		"status": query.Status,
+		"page":   query.Page,
+	})
+
+	response.Success(c, PaginatedResponse{
+		Data:       reports,
+		Pagination: buildPagination(query.Page, query.Limit, total),
+	})
+}
+
+// ListAuditLogs godoc
+// @Summary List audit log
+// @Description Browse admin actions, newest first (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param actorId query string false "Filter by acting admin"
+// @Param targetId query string false "Filter by target"
+// @Param action query string false "Filter by action"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 50, max 100)"
+// @Success 200 {object} response.APIResponse{data=PaginatedResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /admin/audit-logs [get]
+func (h *Handler) ListAuditLogs(c *gin.Context) {
+	var query AuditLogListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, err.Error(), "INVALID_QUERY")
+		return
+	}
+
+	filter := bson.M{}
+	if query.ActorID != "" {
+		actorID, err := primitive.ObjectIDFromHex(query.ActorID)
+		if err != nil {
+			response.BadRequest(c, "Invalid actor ID", "INVALID_ID")
+			return
+		}
+		filter["actorId"] = actorID
+	}
+	if query.TargetID != "" {
+		targetID, err := primitive.ObjectIDFromHex(query.TargetID)
+		if err != nil {
+			response.BadRequest(c, "Invalid target ID", "INVALID_ID")
+			return
+		}
+		filter["targetId"] = targetID
+	}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+
+	logs, total, err := h.repo.ListAuditLogs(c.Request.Context(), filter, query.Page, query.Limit)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch audit log", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, PaginatedResponse{
+		Data:       logs,
+		Pagination: buildPagination(query.Page, query.Limit, total),
+	})
+}
+
+func buildPagination(page, limit int, total int64) Pagination {
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
+	}
+}
@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audit log action constants
+const (
+	ActionUserLookup    = "user.lookup"
+	ActionUserSuspend   = "user.suspend"
+	ActionUserUnsuspend = "user.unsuspend"
+	ActionUserSetRole   = "user.set_role"
+	ActionAnchorDelete  = "anchor.delete"
+	ActionCommentDelete = "comment.delete"
+	ActionReportsView   = "reports.view"
+)
+
+// Audit log target type constants
+const (
+	TargetUser    = "user"
+	TargetAnchor  = "anchor"
+	TargetComment = "comment"
+	TargetReport  = "report"
+)
+
+// AuditLog records an action taken through the admin API
+type AuditLog struct {
+	ID         primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	ActorID    primitive.ObjectID     `bson:"actorId" json:"actorId"`
+	ActorRole  string                 `bson:"actorRole" json:"actorRole"`
+	Action     string                 `bson:"action" json:"action"`
+	TargetType string                 `bson:"targetType" json:"targetType"`
+	TargetID   *primitive.ObjectID    `bson:"targetId,omitempty" json:"targetId,omitempty"`
+	Reason     string                 `bson:"reason,omitempty" json:"reason,omitempty"`
+	Metadata   map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	IPAddress  string                 `bson:"ipAddress" json:"ipAddress"`
+	CreatedAt  time.Time              `bson:"createdAt" json:"createdAt"`
+}
+
+// UserLookupQuery for GET /admin/users/lookup
+type UserLookupQuery struct {
+	Q string `form:"q" binding:"required"` // User ID, email or username
+}
+
+// AdminUserResponse is the moderator view of a user, including fields hidden from public JSON
+type AdminUserResponse struct {
+	*auth.User
+	Role             string `json:"role"`
+	SuspensionReason string `json:"suspensionReason,omitempty"`
+}
+
+// SuspendUserRequest for POST /admin/users/:id/suspend
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// SetRoleRequest for PATCH /admin/users/:id/role
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+// ReportListQuery for GET /admin/reports
+type ReportListQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending reviewed"`
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// AuditLogListQuery for GET /admin/audit-logs
+type AuditLogListQuery struct {
+	ActorID  string `form:"actorId"`
+	TargetID string `form:"targetId"`
+	Action   string `form:"action"`
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	Limit    int    `form:"limit,default=50" binding:"min=1,max=100"`
+}
+
+// Pagination metadata for admin list responses
+type Pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"totalPages"`
+	HasMore    bool  `json:"hasMore"`
+}
+
+// PaginatedResponse wraps admin list results
+type PaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository handles database interactions for the admin feature
+type Repository struct {
+	auditLogsCollection *mongo.Collection
+}
+
+// NewRepository creates repository and ensures indexes
+func NewRepository(db *mongo.Database) *Repository {
+	collection := db.Collection("audit_logs")
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// Browse the full log, newest first
+			Keys: bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			// Actions performed by a given admin
+			Keys: bson.D{{Key: "actorId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			// History of a given user/anchor/comment
+			Keys: bson.D{{Key: "targetId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+	})
+
+	return &Repository{
+		auditLogsCollection: collection,
+	}
+}
+
+// CreateAuditLog appends an entry to the audit log
+func (r *Repository) CreateAuditLog(ctx context.Context, entry *AuditLog) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+
+	_, err := r.auditLogsCollection.InsertOne(ctx, entry)
+	return err
+}
+
+// ListAuditLogs returns audit log entries newest first
+func (r *Repository) ListAuditLogs(ctx context.Context, filter bson.M, page, limit int) ([]AuditLog, int64, error) {
+	total, err := r.auditLogsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.auditLogsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	logs := []AuditLog{}
+	if err = cursor.All(ctx, &logs); err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/comments"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RegisterRoutes registers the admin API routes.
+// The whole group requires at least the moderator role; account-level actions require admin.
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+	// Initialize repositories
+	repo := NewRepository(db)
+	authRepo := auth.NewRepository(db)
+	anchorsRepo := anchors.NewRepository(db)
+	commentsRepo := comments.NewRepository(db)
+	safetyRepo := safety.NewRepository(db)
+
+	// Initialize handler
+	handler := NewHandler(repo, authRepo, anchorsRepo, commentsRepo, safetyRepo, cfg)
+
+	// Initialize middlewares
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+	requireAdmin := middleware.RequireRole(auth.RoleAdmin)
+
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware, middleware.RequireRole(auth.RoleModerator))
+	{
+		// Moderation
+		admin.GET("/users/lookup", handler.LookupUser)
+		admin.DELETE("/anchors/:id", handler.DeleteAnchor)
+		admin.DELETE("/comments/:id", handler.DeleteComment)
+		admin.GET("/reports", handler.ListReports)
+
+		// Account administration
+		admin.POST("/users/:id/suspend", requireAdmin, handler.SuspendUser)
+		admin.POST("/users/:id/unsuspend", requireAdmin, handler.UnsuspendUser)
+		admin.PATCH("/users/:id/role", requireAdmin, handler.SetUserRole)
+		admin.GET("/audit-logs", requireAdmin, handler.ListAuditLogs)
+	}
+}
@@ -32,6 +32,10 @@ func NewRepository(db *mongo.Database) *Repository {
 		{
 			Keys: bson.D{{Key: "anchorId", Value: 1}, {Key: "notifyOnUpdate", Value: 1}},
 		},
+		{
+			// Paging through followers to notify
+			Keys: bson.D{{Key: "anchorId", Value: 1}, {Key: "notifyOnUpdate", Value: 1}, {Key: "_id", Value: 1}},
+		},
 	}
 
 	collection.Indexes().CreateMany(context.Background(), indexes)
@@ -139,34 +143,101 @@ func (r *Repository) GetUserFollowingAnchors(ctx context.Context, userID primiti
 	return follows, total, nil
 }
 
-// GetFollowersWithNotifications gets IDs of followers who want update notifications
-func (r *Repository) GetFollowersWithNotifications(ctx context.Context, anchorID primitive.ObjectID) ([]primitive.ObjectID, error) {
+// GetFollowersWithNotificationsAfter returns up to limit IDs of followers who want update
+// notifications, in follow order, starting after the follow with ID afterID (zero for the
+// start). It also returns the ID of the last follow read, to continue from.
+func (r *Repository) GetFollowersWithNotificationsAfter(ctx context.Context, anchorID, afterID primitive.ObjectID, limit int) ([]primitive.ObjectID, primitive.ObjectID, error) {
 	filter := bson.M{
 		"anchorId":       anchorID,
 		"notifyOnUpdate": true,
 	}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
 
-	projection := bson.M{"userId": 1}
-	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(projection))
+	opts := options.Find().
+		SetProjection(bson.M{"userId": 1}).
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, afterID, err
 	}
 	defer cursor.Close(ctx)
 
-	var ids []primitive.ObjectID
+	ids := make([]primitive.ObjectID, 0, limit)
+	lastID := afterID
 	for cursor.Next(ctx) {
 		var doc struct {
+			ID     primitive.ObjectID `bson:"_id"`
 			UserID primitive.ObjectID `bson:"userId"`
 		}
-		if err := cursor.Decode(&doc); err == nil {
-			ids = append(ids, doc.UserID)
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, afterID, err
 		}
+		ids = append(ids, doc.UserID)
+		lastID = doc.ID
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, afterID, err
 	}
 
-	return ids, nil
+	return ids, lastID, nil
 }
 
 // CountFollowers counts followers for an anchor
 func (r *Repository) CountFollowers(ctx context.Context, anchorID primitive.ObjectID) (int64, error) {
 	return r.collection.CountDocuments(ctx, bson.M{"anchorId": anchorID})
 }
+
+// GetFollowedAnchorIDs returns the IDs of every anchor a user follows
+func (r *Repository) GetFollowedAnchorIDs(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, options.Find().SetProjection(bson.M{"anchorId": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var doc struct {
+			AnchorID primitive.ObjectID `bson:"anchorId"`
+		}
+		if err := cursor.Decode(&doc); err == nil {
+			ids = append(ids, doc.AnchorID)
+		}
+	}
+
+	return ids, nil
+}
+
+// DeleteAllByUser removes every anchor follow made by a user
+func (r *Repository) DeleteAllByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
+
+// DeleteByAnchors removes every follow of the given anchors
+func (r *Repository) DeleteByAnchors(ctx context.Context, anchorIDs []primitive.ObjectID) error {
+	if len(anchorIDs) == 0 {
+		return nil
+	}
+	_, err := r.collection.DeleteMany(ctx, bson.M{"anchorId": bson.M{"$in": anchorIDs}})
+	return err
+}
+
+// GetAllByUser returns every anchor follow made by a user (for data export)
+func (r *Repository) GetAllByUser(ctx context.Context, userID primitive.ObjectID) ([]AnchorFollow, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	follows := []AnchorFollow{}
+	if err = cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/pkg/cloudinary"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -22,29 +24,38 @@ type AnchorFollowService interface {
 	UpdateLastSeenVersion(ctx context.Context, userID, anchorID primitive.ObjectID, version int) error
 }
 
+// FollowChecker defines the interface for checking follow relationships
+type FollowChecker interface {
+	ExistsFollow(ctx context.Context, followerID, followingID primitive.ObjectID) (bool, error)
+}
+
 // Handler handles HTTP requests for anchor feature
 type Handler struct {
 	repo                *Repository
 	authRepo            *auth.Repository
 	notificationService *notifications.Service
+	webhookService      *webhooks.Service
 	config              *config.Config
 	cloudinary          *cloudinary.Service
 	likesRepo           interface{}         // Using interface to avoid cycle
-	followsRepo         interface{}         // Using interface to avoid cycle
+	followsRepo         FollowChecker       // Interface to avoid cycle
 	anchorFollowService AnchorFollowService // Interface to avoid cycle
+	blockPolicy         *safety.BlockPolicy
 }
 
 // NewHandler creates a new anchor handler
-func NewHandler(repo *Repository, authRepo *auth.Repository, notificationService *notifications.Service, cfg *config.Config, cld *cloudinary.Service, likesRepo interface{}, followsRepo interface{}, anchorFollowService AnchorFollowService) *Handler {
+func NewHandler(repo *Repository, authRepo *auth.Repository, notificationService *notifications.Service, webhookService *webhooks.Service, cfg *config.Config, cld *cloudinary.Service, likesRepo interface{}, followsRepo FollowChecker, anchorFollowService AnchorFollowService, blockPolicy *safety.BlockPolicy) *Handler {
 	return &Handler{
 		repo:                repo,
 		authRepo:            authRepo,
 		notificationService: notificationService,
+		webhookService:      webhookService,
 		config:              cfg,
 		cloudinary:          cld,
 		likesRepo:           likesRepo,
 		followsRepo:         followsRepo,
 		anchorFollowService: anchorFollowService,
+		blockPolicy:         blockPolicy,
 	}
 }
 
@@ -118,6 +129,7 @@ func (h *Handler) CreateAnchor(c *gin.Context) {
 		LikeCount:       0,
 		CloneCount:      0,
 		IsPinned:        false,
+		AuthorPrivate:   user.IsPrivate,
 		CreatedAt:       time.Now(), // Ensure created time
 		UpdatedAt:       time.Now(),
 	}
@@ -344,6 +356,19 @@ func (h *Handler) GetAnchor(c *gin.Context) {
 		}
 	}
 
+	// Anchors of deactivated accounts are hidden from everyone but the owner,
+	// and anchors are hidden between users who blocked each other
+	if h.isBlockedWith(c, anchor.UserID) || (!h.isViewer(c, anchor.UserID) && h.isDeactivated(c.Request.Context(), anchor.UserID)) {
+		response.NotFound(c, "Anchor not found", "ANCHOR_NOT_FOUND")
+		return
+	}
+
+	// Anchors of private accounts are visible to approved followers only
+	if !h.canViewAccount(c, anchor.UserID) {
+		response.Forbidden(c, "This account is private", "PRIVATE_ACCOUNT")
+		return
+	}
+
 	// Get items
 	items, err := h.repo.GetAnchorItems(c.Request.Context(), anchorID)
 	if err != nil {
@@ -411,6 +436,16 @@ func (h *Handler) ListUserAnchors(c *gin.Context) {
 		}
 	}
 
+	if !isOwner && (h.isDeactivated(c.Request.Context(), userID) || h.isBlockedWith(c, userID)) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	if !isOwner && !h.canViewAccount(c, userID) {
+		response.Forbidden(c, "This account is private", "PRIVATE_ACCOUNT")
+		return
+	}
+
 	if isOwner {
 		anchors, total, err = h.repo.GetUserAnchors(c.Request.Context(), userID, page, limit)
 	} else {
@@ -473,6 +508,16 @@ func (h *Handler) ListAnchorItems(c *gin.Context) {
 		}
 	}
 
+	if h.isBlockedWith(c, anchor.UserID) {
+		response.NotFound(c, "Anchor not found", "ANCHOR_NOT_FOUND")
+		return
+	}
+
+	if !h.canViewAccount(c, anchor.UserID) {
+		response.Forbidden(c, "This account is private", "PRIVATE_ACCOUNT")
+		return
+	}
+
 	items, total, err := h.repo.GetAnchorItemsPaginated(c.Request.Context(), anchorID, page, limit)
 	if err != nil {
 		response.InternalServerError(c, "Failed to fetch items", "DATABASE_ERROR")
@@ -564,22 +609,21 @@ func (h *Handler) AddItem(c *gin.Context) {
 		"$inc": map[string]interface{}{"itemCount": 1},
 	})
 
-	// Increment anchor version
-	_ = h.repo.IncrementVersion(c.Request.Context(), anchorID)
-
-	// Send notifications to followers (async)
-	go func(aid primitive.ObjectID, title string, actorID primitive.ObjectID) {
-		notificationService := notifications.GetService(h.repo.db) // Need to check if repo has db
-		err := notificationService.CreateAnchorUpdateNotifications(
-			context.Background(),
-			aid,
-			title,
-			actorID,
-		)
-		if err != nil {
-			log.Printf("Failed to create anchor update notifications: %v", err)
-		}
-	}(anchorID, anchor.Title, user.ID)
+	// Increment anchor version and notify followers through the job queue
+	version, err := h.repo.IncrementVersion(c.Request.Context(), anchorID)
+	if err != nil {
+		log.Printf("Failed to increment anchor version: %v", err)
+	} else if err := h.notificationService.EnqueueAnchorUpdateNotifications(c.Request.Context(), anchorID, version, anchor.Title, user.ID); err != nil {
+		log.Printf("Failed to queue anchor update notifications: %v", err)
+	}
+
+	_ = h.webhookService.Publish(c.Request.Context(), anchor.UserID, webhooks.EventItemAdded, webhooks.ItemAddedData{
+		AnchorID:    anchorID,
+		AnchorTitle: anchor.Title,
+		ItemID:      item.ID,
+		ItemType:    item.Type,
+		Actor:       webhooks.Actor{ID: user.ID, Username: user.Username},
+	})
 
 	response.Created(c, item)
 }
@@ -852,6 +896,11 @@ func (h *Handler) GetAnchorClones(c *gin.Context) {
 		}
 	}
 
+	if h.isBlockedWith(c, anchor.UserID) {
+		response.NotFound(c, "Anchor not found", "ANCHOR_NOT_FOUND")
+		return
+	}
+
 	clones, total, err := h.repo.GetAnchorClones(c.Request.Context(), anchorID, page, limit)
 	if err != nil {
 		response.InternalServerError(c, "Failed to fetch clones", "DATABASE_ERROR")
@@ -964,3 +1013,59 @@ func (h *Handler) TogglePin(c *gin.Context) {
 
 	response.Success(c, gin.H{"isPinned": newStatus})
 }
+
+// isViewer reports whether the authenticated user (if any) is userID
+func (h *Handler) isViewer(c *gin.Context, userID primitive.ObjectID) bool {
+	if val, exists := c.Get("user"); exists {
+		if user, ok := val.(*auth.User); ok {
+			return user.ID == userID
+		}
+	}
+	return false
+}
+
+// isBlockedWith reports whether the authenticated user (if any) and userID have blocked each other
+func (h *Handler) isBlockedWith(c *gin.Context, userID primitive.ObjectID) bool {
+	if h.blockPolicy == nil {
+		return false
+	}
+	if val, exists := c.Get("user"); exists {
+		if user, ok := val.(*auth.User); ok {
+			return h.blockPolicy.IsBlocked(c.Request.Context(), user.ID, userID)
+		}
+	}
+	return false
+}
+
+// isDeactivated reports whether the given user has deactivated their account
+func (h *Handler) isDeactivated(ctx context.Context, userID primitive.ObjectID) bool {
+	owner, err := h.authRepo.GetUserByObjectID(ctx, userID)
+	return err == nil && owner.IsDeactivated()
+}
+
+// canViewAccount reports whether the viewer may see the content of userID's account.
+// Private accounts are visible to themselves and their approved followers only.
+func (h *Handler) canViewAccount(c *gin.Context, userID primitive.ObjectID) bool {
+	owner, err := h.authRepo.GetUserByObjectID(c.Request.Context(), userID)
+	if err != nil || !owner.IsPrivate {
+		return true
+	}
+
+	val, exists := c.Get("user")
+	if !exists {
+		return false
+	}
+	viewer, ok := val.(*auth.User)
+	if !ok {
+		return false
+	}
+	if viewer.ID == userID {
+		return true
+	}
+	if h.followsRepo == nil {
+		return false
+	}
+
+	isFollowing, err := h.followsRepo.ExistsFollow(c.Request.Context(), viewer.ID, userID)
+	return err == nil && isFollowing
+}
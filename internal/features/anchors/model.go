@@ -35,14 +35,22 @@ type Anchor struct {
 	Tags               []string            `bson:"tags" json:"tags"`
 	ClonedFromAnchorID *primitive.ObjectID `bson:"clonedFromAnchorId,omitempty" json:"clonedFromAnchorId,omitempty"`
 	ClonedFromUserID   *string             `bson:"clonedFromUserId,omitempty" json:"clonedFromUserId,omitempty"`
+	AuthorDeactivated  bool                `bson:"authorDeactivated,omitempty" json:"-"` // Copied from the author's account, see auth.Repository.SyncContentVisibility
+	AuthorPrivate      bool                `bson:"authorPrivate,omitempty" json:"-"`     // Copied from the author's account
 	LikeCount          int                 `bson:"likeCount" json:"likeCount"`
 	CloneCount         int                 `bson:"cloneCount" json:"cloneCount"`
 	CommentCount       int                 `bson:"commentCount" json:"commentCount"`
 	ViewCount          int                 `bson:"viewCount" json:"viewCount"`
 	ItemCount          int                 `bson:"itemCount" json:"itemCount"`
 	EngagementScore    int                 `bson:"engagementScore" json:"engagementScore"`
-	Version            int                 `bson:"version" json:"version"`             // Increments when items added
-	FollowerCount      int                 `bson:"followerCount" json:"followerCount"` // How many users follow this anchor
+	HotScore           float64             `bson:"hotScore" json:"hotScore"` // Time-decayed recent engagement, recomputed by the feed hot scorer
+	HotScoredAt        *time.Time          `bson:"hotScoredAt,omitempty" json:"-"`
+	PrevHotScore       float64             `bson:"prevHotScore,omitempty" json:"-"` // Previous run's score, kept for clients still paging through it
+	PrevHotScoredAt    *time.Time          `bson:"prevHotScoredAt,omitempty" json:"-"`
+	Version            int                 `bson:"version" json:"version"`                // Increments when items added
+	FollowerCount      int                 `bson:"followerCount" json:"followerCount"`    // How many users follow this anchor
+	CommentsLocked     bool                `bson:"commentsLocked" json:"commentsLocked"`  // Only the owner can comment
+	CommentFilterWords []string            `bson:"commentFilterWords,omitempty" json:"-"` // Comments containing these are held for owner review
 
 	CreatedAt       time.Time  `bson:"createdAt" json:"createdAt"`
 	UpdatedAt       time.Time  `bson:"updatedAt" json:"updatedAt"`
@@ -52,17 +60,18 @@ type Anchor struct {
 
 // Item represents a single content item within an anchor
 type Item struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	AnchorID  primitive.ObjectID `bson:"anchorId" json:"anchorId"`
-	Type      string             `bson:"type" json:"type"` // "url", "image", "audio", "file", "text"
-	Position  int                `bson:"position" json:"position"`
-	URLData   *URLData           `bson:"urlData,omitempty" json:"urlData,omitempty"`
-	ImageData *ImageData         `bson:"imageData,omitempty" json:"imageData,omitempty"`
-	AudioData *AudioData         `bson:"audioData,omitempty" json:"audioData,omitempty"`
-	FileData  *FileData          `bson:"fileData,omitempty" json:"fileData,omitempty"`
-	TextData  *TextData          `bson:"textData,omitempty" json:"textData,omitempty"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AnchorID     primitive.ObjectID `bson:"anchorId" json:"anchorId"`
+	Type         string             `bson:"type" json:"type"` // "url", "image", "audio", "file", "text"
+	Position     int                `bson:"position" json:"position"`
+	URLData      *URLData           `bson:"urlData,omitempty" json:"urlData,omitempty"`
+	ImageData    *ImageData         `bson:"imageData,omitempty" json:"imageData,omitempty"`
+	AudioData    *AudioData         `bson:"audioData,omitempty" json:"audioData,omitempty"`
+	FileData     *FileData          `bson:"fileData,omitempty" json:"fileData,omitempty"`
+	TextData     *TextData          `bson:"textData,omitempty" json:"textData,omitempty"`
+	CommentCount int                `bson:"commentCount" json:"commentCount"` // Comments targeting this item
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 // URLData contains metadata for URL items
@@ -169,6 +178,7 @@ func (a *Anchor) ToPublicAnchor() map[string]interface{} {
 		"likeCount":       a.LikeCount,
 		"cloneCount":      a.CloneCount,
 		"commentCount":    a.CommentCount,
+		"commentsLocked":  a.CommentsLocked,
 		"viewCount":       a.ViewCount,
 		"itemCount":       a.ItemCount,
 		"createdAt":       a.CreatedAt,
@@ -515,6 +515,27 @@ func (r *Repository) IncrementCommentCount(ctx context.Context, anchorID primiti
 	return nil
 }
 
+// IncrementItemCommentCount increments/decrements an item's comment count
+func (r *Repository) IncrementItemCommentCount(ctx context.Context, itemID primitive.ObjectID, delta int) error {
+	_, err := r.itemsCollection.UpdateOne(ctx,
+		bson.M{"_id": itemID},
+		bson.M{"$inc": bson.M{"commentCount": delta}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Ensure count doesn't go negative
+	if delta < 0 {
+		_, _ = r.itemsCollection.UpdateOne(ctx,
+			bson.M{"_id": itemID, "commentCount": bson.M{"$lt": 0}},
+			bson.M{"$set": bson.M{"commentCount": 0}},
+		)
+	}
+
+	return nil
+}
+
 // GetAnchorTitles batch fetches titles for a list of anchor IDs
 func (r *Repository) GetAnchorTitles(ctx context.Context, anchorIDs []primitive.ObjectID) (map[primitive.ObjectID]string, error) {
 	if len(anchorIDs) == 0 {
@@ -664,6 +685,45 @@ func (r *Repository) GetAnchorsByIDs(ctx context.Context, anchorIDs []primitive.
 	return anchors, nil
 }
 
+// GetTrendingByTags returns the most engaging public anchors created since the given time
+// that carry any of the tags, excluding anchors by the given users
+func (r *Repository) GetTrendingByTags(ctx context.Context, tags []string, since time.Time, excludeUserIDs []primitive.ObjectID, limit int) ([]Anchor, error) {
+	if len(tags) == 0 {
+		return []Anchor{}, nil
+	}
+
+	filter := bson.M{
+		"tags":              bson.M{"$in": tags},
+		"visibility":        VisibilityPublic,
+		"deletedAt":         nil,
+		"createdAt":         bson.M{"$gte": since},
+		"authorDeactivated": bson.M{"$ne": true},
+		"authorPrivate":     bson.M{"$ne": true},
+	}
+	if len(excludeUserIDs) > 0 {
+		filter["userId"] = bson.M{"$nin": excludeUserIDs}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{
+			{Key: "engagementScore", Value: -1},
+			{Key: "createdAt", Value: -1},
+		}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.anchorsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	anchors := []Anchor{}
+	if err = cursor.All(ctx, &anchors); err != nil {
+		return nil, err
+	}
+	return anchors, nil
+}
+
 // IncrementFollowerCount increments or decrements the anchor's follower count
 func (r *Repository) IncrementFollowerCount(ctx context.Context, anchorID primitive.ObjectID, delta int) error {
 	filter := bson.M{"_id": anchorID}
@@ -676,8 +736,8 @@ func (r *Repository) IncrementFollowerCount(ctx context.Context, anchorID primit
 	return err
 }
 
-// IncrementVersion increments the anchor version (called when items added)
-func (r *Repository) IncrementVersion(ctx context.Context, anchorID primitive.ObjectID) error {
+// IncrementVersion increments the anchor version (called when items added) and returns the new version
+func (r *Repository) IncrementVersion(ctx context.Context, anchorID primitive.ObjectID) (int, error) {
 	now := time.Now()
 	filter := bson.M{"_id": anchorID}
 	update := bson.M{
@@ -687,9 +747,17 @@ func (r *Repository) IncrementVersion(ctx context.Context, anchorID primitive.Ob
 			"updatedAt":       now,
 		},
 	}
+	opts := options.FindOneAndUpdate().
+		SetProjection(bson.M{"version": 1}).
+		SetReturnDocument(options.After)
 
-	_, err := r.anchorsCollection.UpdateOne(ctx, filter, update)
-	return err
+	var updated struct {
+		Version int `bson:"version"`
+	}
+	if err := r.anchorsCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated); err != nil {
+		return 0, err
+	}
+	return updated.Version, nil
 }
 
 type TagCount struct {
@@ -735,3 +803,106 @@ func (r *Repository) GetPopularTags(ctx context.Context, limit int) ([]TagCount,
 
 	return results, nil
 }
+
+// SetEngagementCounts overwrites an anchor's like and follower counters with
+// recounted values and refreshes its engagement score
+func (r *Repository) SetEngagementCounts(ctx context.Context, anchorID primitive.ObjectID, likeCount, followerCount int64) error {
+	if err := r.UpdateAnchor(ctx, anchorID, bson.M{
+		"likeCount":     likeCount,
+		"followerCount": followerCount,
+	}); err != nil {
+		return err
+	}
+
+	return r.UpdateEngagementScore(ctx, anchorID)
+}
+
+// AuthorTagMatch summarises a user's public anchors that carry some of the given tags
+type AuthorTagMatch struct {
+	UserID      primitive.ObjectID `bson:"_id"`
+	MatchedTags [][]string         `bson:"matchedTags"` // Matching tags per anchor
+	Engagement  int                `bson:"engagement"`  // Sum of the matching anchors' engagement scores
+}
+
+// GetAuthorsByTags ranks users publishing public anchors tagged with any of tags by their engagement,
+// skipping excludeIDs. With no tags every public anchor counts.
+func (r *Repository) GetAuthorsByTags(ctx context.Context, tags []string, excludeIDs []primitive.ObjectID, limit int) ([]AuthorTagMatch, error) {
+	if excludeIDs == nil {
+		excludeIDs = []primitive.ObjectID{}
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	match := bson.M{
+		"visibility": VisibilityPublic,
+		"deletedAt":  nil,
+		"userId":     bson.M{"$nin": excludeIDs},
+	}
+	if len(tags) > 0 {
+		match["tags"] = bson.M{"$in": tags}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$userId",
+			"matchedTags": bson.M{"$push": bson.M{"$setIntersection": bson.A{"$tags", tags}}},
+			"engagement":  bson.M{"$sum": "$engagementScore"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "engagement", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.anchorsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []AuthorTagMatch{}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetAuthorEngagement sums the engagement scores of each user's public anchors
+func (r *Repository) GetAuthorEngagement(ctx context.Context, userIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	result := make(map[primitive.ObjectID]int)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"userId":     bson.M{"$in": userIDs},
+			"visibility": VisibilityPublic,
+			"deletedAt":  nil,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$userId",
+			"engagement": bson.M{"$sum": "$engagementScore"},
+		}}},
+	}
+
+	cursor, err := r.anchorsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		UserID     primitive.ObjectID `bson:"_id"`
+		Engagement int                `bson:"engagement"`
+	}
+	if err = cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.UserID] = row.Engagement
+	}
+	return result, nil
+}
@@ -6,7 +6,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/follows"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"github.com/xyz-asif/gotodo/internal/pkg/cloudinary"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -25,12 +28,12 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 		log.Printf("Failed to initialize cloudinary service: %v", err)
 	}
 
-	// Initialize handler (repos passed as nil to avoid import cycles)
-	handler := NewHandler(repo, authRepo, notificationService, cfg, cloudinarySvc, nil, nil, anchorFollowService)
+	// Initialize handler (likes repo passed as nil to avoid import cycles)
+	handler := NewHandler(repo, authRepo, notificationService, webhooks.NewService(db, cfg), cfg, cloudinarySvc, nil, follows.NewRepository(db), anchorFollowService, safety.NewBlockPolicy(db))
 
-	// Initialize auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
-	optionalAuth := middleware.OptionalAuthMiddleware(authRepo, cfg)
+	// Initialize auth middleware (personal access tokens need anchors:read / anchors:write)
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg, auth.ScopeAnchorsWrite)
+	optionalAuth := middleware.OptionalAuthMiddleware(authRepo, cfg, auth.ScopeAnchorsRead)
 
 	// Anchor routes group
 	anchors := router.Group("/anchors")
@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -15,7 +17,9 @@ import (
 	"github.com/xyz-asif/gotodo/internal/pkg/cloudinary"
 	idToken "github.com/xyz-asif/gotodo/internal/pkg/jwt"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"github.com/xyz-asif/gotodo/internal/pkg/totp"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // FollowService defines the interface for follow operations to avoid import cycle
@@ -26,7 +30,11 @@ type FollowService interface {
 // AnchorService defines the interface for anchor operations to avoid import cycle
 type AnchorService interface {
 	GetPinnedAnchors(ctx context.Context, userID primitive.ObjectID, includePrivate bool) ([]PinnedAnchorData, error)
-	DeleteAllByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// BlockChecker defines the interface for the block policy to avoid import cycle
+type BlockChecker interface {
+	IsBlocked(ctx context.Context, userA, userB primitive.ObjectID) bool
 }
 
 // PinnedAnchorData represents anchor data returned from anchor service
@@ -50,9 +58,10 @@ type Handler struct {
 	cloudinary     *cloudinary.Service
 	followService  FollowService
 	anchorService  AnchorService
+	blockChecker   BlockChecker
 }
 
-func NewHandler(repo *Repository, firebaseClient *auth.Client, cfg *config.Config, cld *cloudinary.Service, followService FollowService, anchorService AnchorService) *Handler {
+func NewHandler(repo *Repository, firebaseClient *auth.Client, cfg *config.Config, cld *cloudinary.Service, followService FollowService, anchorService AnchorService, blockChecker BlockChecker) *Handler {
 	return &Handler{
 		repo:           repo,
 		firebaseClient: firebaseClient,
@@ -60,6 +69,7 @@ func NewHandler(repo *Repository, firebaseClient *auth.Client, cfg *config.Confi
 		cloudinary:     cld,
 		followService:  followService,
 		anchorService:  anchorService,
+		blockChecker:   blockChecker,
 	}
 }
 
@@ -144,6 +154,52 @@ func (h *Handler) getJWTConfig() *idToken.Config {
 	}
 }
 
+// issueTokenPair generates an access/refresh token pair and stores the refresh session
+func (h *Handler) issueTokenPair(c *gin.Context, user *User) (string, string, error) {
+	jwtConfig := h.getJWTConfig()
+	accessToken, refreshToken, err := idToken.GenerateTokenPair(user.ID.Hex(), user.Email, jwtConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Save Refresh Token
+	claims, _ := idToken.GetTokenClaims(refreshToken)
+	if claims != nil {
+		tokenID := claims.ID // JTI
+		session := &RefreshTokenSession{
+			ID:        primitive.NewObjectID(),
+			UserID:    user.ID,
+			TokenID:   tokenID,
+			ExpiresAt: claims.ExpiresAt.Time,
+			CreatedAt: time.Now(),
+			Revoked:   false,
+			UserAgent: c.Request.UserAgent(),
+			IPAddress: c.ClientIP(),
+		}
+		if err := h.repo.SaveRefreshToken(c.Request.Context(), session); err != nil {
+			// Log error but proceed? Or fail? Better fail for consistency.
+			fmt.Printf("Failed to save refresh token: %v\n", err)
+		}
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// respondTwoFactorChallenge answers the first login step for users with two-factor enabled
+func (h *Handler) respondTwoFactorChallenge(c *gin.Context, user *User) {
+	challengeToken, err := GenerateTwoFactorChallenge(user, h.config)
+	if err != nil {
+		response.InternalServerError(c, "Failed to generate challenge", "AUTH_FAILED")
+		return
+	}
+
+	response.Success(c, TwoFactorChallengeResponse{
+		RequiresTwoFactor: true,
+		ChallengeToken:    challengeToken,
+		ExpiresIn:         int(twoFactorChallengeExpiry.Seconds()),
+	}, "Two-factor verification required")
+}
+
 // GoogleLogin handles Google OAuth login/registration
 // @Summary Login with Google
 // @Description Authenticate user using Google ID token
@@ -244,34 +300,31 @@ func (h *Handler) GoogleLogin(c *gin.Context) {
 		}
 	}
 
+	// Suspended accounts cannot sign in
+	if user.IsSuspended() {
+		response.Forbidden(c, "Account suspended", "ACCOUNT_SUSPENDED")
+		return
+	}
+
+	// Accounts past their restore window are awaiting purge
+	if user.IsDeletionDue(time.Now()) {
+		response.Forbidden(c, "Account has been deleted", "ACCOUNT_DELETED")
+		return
+	}
+
+	// Second step required: hand out a challenge instead of tokens
+	if user.TwoFactorEnabled {
+		h.respondTwoFactorChallenge(c, user)
+		return
+	}
+
 	// Generate Token Pair
-	jwtConfig := h.getJWTConfig()
-	accessToken, refreshToken, err := idToken.GenerateTokenPair(user.ID.Hex(), user.Email, jwtConfig)
+	accessToken, refreshToken, err := h.issueTokenPair(c, user)
 	if err != nil {
 		response.BadRequest(c, "Failed to generate tokens", "AUTH_FAILED")
 		return
 	}
 
-	// Save Refresh Token
-	claims, _ := idToken.GetTokenClaims(refreshToken)
-	if claims != nil {
-		tokenID := claims.ID // JTI
-		session := &RefreshTokenSession{
-			ID:        primitive.NewObjectID(),
-			UserID:    user.ID,
-			TokenID:   tokenID,
-			ExpiresAt: claims.ExpiresAt.Time,
-			CreatedAt: time.Now(),
-			Revoked:   false,
-			UserAgent: c.Request.UserAgent(),
-			IPAddress: c.ClientIP(),
-		}
-		if err := h.repo.SaveRefreshToken(c.Request.Context(), session); err != nil {
-			// Log error but proceed? Or fail? Better fail for consistency.
-			fmt.Printf("Failed to save refresh token: %v\n", err)
-		}
-	}
-
 	stcode := 200
 	if isNewUser {
 		stcode = 201
@@ -340,33 +393,31 @@ func (h *Handler) DevLogin(c *gin.Context) {
 		}
 	}
 
+	// Suspended accounts cannot sign in
+	if user.IsSuspended() {
+		response.Forbidden(c, "Account suspended", "ACCOUNT_SUSPENDED")
+		return
+	}
+
+	// Accounts past their restore window are awaiting purge
+	if user.IsDeletionDue(time.Now()) {
+		response.Forbidden(c, "Account has been deleted", "ACCOUNT_DELETED")
+		return
+	}
+
+	// Second step required: hand out a challenge instead of tokens
+	if user.TwoFactorEnabled {
+		h.respondTwoFactorChallenge(c, user)
+		return
+	}
+
 	// Generate Token Pair
-	jwtConfig := h.getJWTConfig()
-	accessToken, refreshToken, err := idToken.GenerateTokenPair(user.ID.Hex(), user.Email, jwtConfig)
+	accessToken, refreshToken, err := h.issueTokenPair(c, user)
 	if err != nil {
 		response.InternalServerError(c, "TOKEN_FAILED", "Failed to generate tokens")
 		return
 	}
 
-	// Save Refresh Token
-	claims, _ := idToken.GetTokenClaims(refreshToken)
-	if claims != nil {
-		tokenID := claims.ID // JTI
-		session := &RefreshTokenSession{
-			ID:        primitive.NewObjectID(),
-			UserID:    user.ID,
-			TokenID:   tokenID,
-			ExpiresAt: claims.ExpiresAt.Time,
-			CreatedAt: time.Now(),
-			Revoked:   false,
-			UserAgent: c.Request.UserAgent(),
-			IPAddress: c.ClientIP(),
-		}
-		if err := h.repo.SaveRefreshToken(c.Request.Context(), session); err != nil {
-			fmt.Printf("Failed to save refresh token: %v\n", err)
-		}
-	}
-
 	// Determine if username setup is needed
 	needsUsername := user.Username == "" || strings.HasPrefix(user.Username, "dev_")
 
@@ -482,6 +533,7 @@ func (h *Handler) Logout(c *gin.Context) {
 // @Tags auth
 // @Produce json
 // @Security BearerAuth
+// @Param X-2FA-Code header string false "TOTP or recovery code (required when two-factor is enabled)"
 // @Success 200 {object} response.APIResponse
 // @Failure 401 {object} response.APIResponse
 // @Router /auth/revoke-all [post]
@@ -543,6 +595,11 @@ func (h *Handler) GetOwnProfile(c *gin.Context) {
 		JoinedAt:          user.JoinedAt,
 		CreatedAt:         user.CreatedAt,
 		UpdatedAt:         user.UpdatedAt,
+		TwoFactorEnabled:  user.TwoFactorEnabled,
+		IsPrivate:         user.IsPrivate,
+
+		DeactivatedAt:        user.DeactivatedAt,
+		DeletionScheduledFor: user.DeletionScheduledFor,
 	}
 
 	response.Success(c, resp)
@@ -572,17 +629,30 @@ func (h *Handler) GetPublicProfile(c *gin.Context) {
 		return
 	}
 
+	var currentUser *User
+	if val, exists := c.Get("user"); exists {
+		currentUser, _ = val.(*User)
+	}
+
+	// Deactivated profiles are only visible to their owner
+	if user.IsDeactivated() && (currentUser == nil || currentUser.ID != user.ID) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	// Profiles are hidden between users who blocked each other
+	if currentUser != nil && h.isBlockedBetween(c.Request.Context(), currentUser.ID, user.ID) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
 	// Check follow status if authenticated
 	var isFollowing, isFollowedBy, isMutual bool
-	if val, exists := c.Get("user"); exists {
-		if currentUser, ok := val.(*User); ok {
-			// Don't check follow status for self
-			if currentUser.ID != userID && h.followService != nil {
-				isFollowing, isFollowedBy, err = h.followService.GetStatus(c.Request.Context(), currentUser.ID, userID)
-				if err == nil {
-					isMutual = isFollowing && isFollowedBy
-				}
-			}
+	// Don't check follow status for self
+	if currentUser != nil && currentUser.ID != userID && h.followService != nil {
+		isFollowing, isFollowedBy, err = h.followService.GetStatus(c.Request.Context(), currentUser.ID, userID)
+		if err == nil {
+			isMutual = isFollowing && isFollowedBy
 		}
 	}
 
@@ -597,6 +667,7 @@ func (h *Handler) GetPublicProfile(c *gin.Context) {
 		FollowingCount:    user.FollowingCount,
 		AnchorCount:       user.AnchorCount,
 		IsVerified:        user.IsVerified,
+		IsPrivate:         user.IsPrivate,
 		JoinedAt:          user.JoinedAt,
 		IsFollowing:       isFollowing,
 		IsFollowedBy:      isFollowedBy,
@@ -651,6 +722,10 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		updates["bio"] = *req.Bio
 	}
 
+	if req.IsPrivate != nil {
+		updates["isPrivate"] = *req.IsPrivate
+	}
+
 	if len(updates) == 0 {
 		// Just return current profile if no changes
 		h.GetOwnProfile(c)
@@ -662,6 +737,13 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	if req.IsPrivate != nil {
+		if err := h.repo.SyncContentVisibility(c.Request.Context(), user.ID); err != nil {
+			response.InternalServerError(c, "Failed to update profile", "DATABASE_ERROR")
+			return
+		}
+	}
+
 	// Fetch updated user
 	updatedUser, err := h.repo.GetUserByID(c.Request.Context(), user.ID.Hex())
 	if err != nil {
@@ -685,6 +767,11 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		JoinedAt:          updatedUser.JoinedAt,
 		CreatedAt:         updatedUser.CreatedAt,
 		UpdatedAt:         updatedUser.UpdatedAt,
+		TwoFactorEnabled:  updatedUser.TwoFactorEnabled,
+		IsPrivate:         updatedUser.IsPrivate,
+
+		DeactivatedAt:        updatedUser.DeactivatedAt,
+		DeletionScheduledFor: updatedUser.DeletionScheduledFor,
 	}
 
 	response.Success(c, resp)
@@ -698,6 +785,7 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body UpdateUsernameRequest true "New username"
+// @Param X-2FA-Code header string false "TOTP or recovery code (required when two-factor is enabled)"
 // @Success 200 {object} response.APIResponse{data=User}
 // @Failure 400 {object} response.APIResponse
 // @Failure 401 {object} response.APIResponse
@@ -1030,21 +1118,33 @@ func (h *Handler) GetPinnedAnchors(c *gin.Context) {
 	}
 
 	// Determine visibility access and user existence
-	_, err = h.repo.GetUserByObjectID(c.Request.Context(), userID)
+	owner, err := h.repo.GetUserByObjectID(c.Request.Context(), userID)
 	if err != nil {
 		response.NotFound(c, "User not found", "USER_NOT_FOUND")
 		return
 	}
 
 	includePrivate := false
+	var currentUser *User
 	if val, exists := c.Get("user"); exists {
-		if currentUser, ok := val.(*User); ok {
-			if currentUser.ID == userID {
-				includePrivate = true
-			}
+		currentUser, _ = val.(*User)
+		if currentUser != nil && currentUser.ID == userID {
+			includePrivate = true
 		}
 	}
 
+	// Deactivated accounts are hidden from everyone but their owner
+	if owner.IsDeactivated() && !includePrivate {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	// Hidden between users who blocked each other
+	if currentUser != nil && h.isBlockedBetween(c.Request.Context(), currentUser.ID, userID) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
 	if h.anchorService == nil {
 		// Should not happen if wired correctly, but fail gracefully
 		response.InternalServerError(c, "Anchor service not available", "INTERNAL_ERROR")
@@ -1078,14 +1178,95 @@ func (h *Handler) GetPinnedAnchors(c *gin.Context) {
 	response.Success(c, pinnedAnchors)
 }
 
-// DeleteAccount deletes the user's account and all associated data
+// DeactivateAccount hides the user's profile and content until they restore it
+// @Summary Deactivate account
+// @Description Hide the authenticated user's profile, anchors and comments from other users. Signing in remains possible so the account can be restored.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=AccountStatusResponse}
+// @Failure 401 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /users/me/deactivate [post]
+func (h *Handler) DeactivateAccount(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	if user.IsDeactivated() {
+		response.Conflict(c, "Account is already deactivated", "ALREADY_DEACTIVATED")
+		return
+	}
+
+	now := time.Now()
+	if err := h.repo.DeactivateUser(c.Request.Context(), user.ID, now); err != nil {
+		response.InternalServerError(c, "Failed to deactivate account", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, AccountStatusResponse{
+		Deactivated:   true,
+		DeactivatedAt: &now,
+	}, "Account deactivated")
+}
+
+// RestoreAccount reactivates a deactivated account and cancels a pending deletion
+// @Summary Restore account
+// @Description Reactivate the authenticated user's account. Cancels a scheduled deletion if the restore window has not passed.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=AccountStatusResponse}
+// @Failure 401 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /users/me/restore [post]
+func (h *Handler) RestoreAccount(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	if !user.IsDeactivated() && !user.IsPendingDeletion() {
+		response.Conflict(c, "Account is not deactivated", "NOT_DEACTIVATED")
+		return
+	}
+
+	restored, err := h.repo.RestoreUser(c.Request.Context(), user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to restore account", "DATABASE_ERROR")
+		return
+	}
+	if !restored {
+		response.Conflict(c, "The restore window for this account has passed", "RESTORE_WINDOW_EXPIRED")
+		return
+	}
+
+	response.Success(c, AccountStatusResponse{Deactivated: false}, "Account restored")
+}
+
+// DeleteAccount schedules the user's account for deletion
 // @Summary Delete account
-// @Description Permanently delete the authenticated user's account and all content
+// @Description Deactivate the authenticated user's account and schedule it for permanent deletion once the restore window (30 days by default) has passed. Signing in and calling /users/me/restore before then cancels the deletion.
 // @Tags users
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} response.APIResponse
+// @Param X-2FA-Code header string false "TOTP or recovery code (required when two-factor is enabled)"
+// @Success 200 {object} response.APIResponse{data=AccountStatusResponse}
 // @Failure 401 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Router /users/me [delete]
 func (h *Handler) DeleteAccount(c *gin.Context) {
@@ -1100,28 +1281,462 @@ func (h *Handler) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	// 1. Delete all user content (Anchors, Items, Assets) via AnchorService
-	if h.anchorService != nil {
-		if err := h.anchorService.DeleteAllByUser(c.Request.Context(), user.ID); err != nil {
-			fmt.Printf("Failed to delete user content: %v\n", err)
-			response.InternalServerError(c, "Failed to delete user content", "DELETE_FAILED")
+	if user.IsPendingDeletion() {
+		response.Conflict(c, "Account deletion is already scheduled", "DELETION_PENDING")
+		return
+	}
+
+	graceDays := h.config.AccountDeletionGraceDays
+	if graceDays < 0 {
+		graceDays = 0
+	}
+	now := time.Now()
+	scheduledFor := now.AddDate(0, 0, graceDays)
+
+	if err := h.repo.ScheduleUserDeletion(c.Request.Context(), user.ID, now, scheduledFor); err != nil {
+		response.InternalServerError(c, "Failed to schedule account deletion", "DATABASE_ERROR")
+		return
+	}
+
+	// Sign out other sessions and API clients; the user signs in again to restore
+	_ = h.repo.RevokeAllUserTokens(c.Request.Context(), user.ID)
+	_ = h.repo.RevokeAllPersonalAccessTokens(c.Request.Context(), user.ID)
+
+	response.Success(c, AccountStatusResponse{
+		Deactivated:          true,
+		DeactivatedAt:        &now,
+		DeletionScheduledFor: &scheduledFor,
+	}, "Account scheduled for deletion")
+}
+
+// CreatePersonalAccessToken mints a new scoped personal access token
+// @Summary Create personal access token
+// @Description Create a named API token with scopes and optional expiry. The token is only shown once.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreatePersonalAccessTokenRequest true "Token details"
+// @Success 201 {object} response.APIResponse{data=CreatePersonalAccessTokenResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /users/me/tokens [post]
+func (h *Handler) CreatePersonalAccessToken(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	var req CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", "VALIDATION_FAILED")
+		return
+	}
+
+	if err := ValidateCreatePersonalAccessTokenRequest(&req); err != nil {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+
+	count, err := h.repo.CountActivePersonalAccessTokens(c.Request.Context(), user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to create token", "DATABASE_ERROR")
+		return
+	}
+	if count >= MaxPersonalAccessTokens {
+		response.BadRequest(c, fmt.Sprintf("Maximum of %d active tokens reached", MaxPersonalAccessTokens), "TOKEN_LIMIT_REACHED")
+		return
+	}
+
+	plaintext, prefix, err := GeneratePersonalAccessToken()
+	if err != nil {
+		response.InternalServerError(c, "Failed to generate token", "TOKEN_GENERATION_FAILED")
+		return
+	}
+
+	token := &PersonalAccessToken{
+		UserID:      user.ID,
+		Name:        req.Name,
+		TokenHash:   HashPersonalAccessToken(plaintext),
+		TokenPrefix: prefix,
+		Scopes:      req.Scopes,
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := h.repo.CreatePersonalAccessToken(c.Request.Context(), token); err != nil {
+		response.InternalServerError(c, "Failed to create token", "DATABASE_ERROR")
+		return
+	}
+
+	response.Created(c, CreatePersonalAccessTokenResponse{
+		Token:               plaintext,
+		PersonalAccessToken: token,
+	})
+}
+
+// ListPersonalAccessTokens returns the user's personal access tokens
+// @Summary List personal access tokens
+// @Description List the authenticated user's API tokens (without the secret values)
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=[]PersonalAccessToken}
+// @Failure 401 {object} response.APIResponse
+// @Router /users/me/tokens [get]
+func (h *Handler) ListPersonalAccessTokens(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	tokens, err := h.repo.ListPersonalAccessTokens(c.Request.Context(), user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch tokens", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, tokens)
+}
+
+// RevokePersonalAccessToken revokes one of the user's personal access tokens
+// @Summary Revoke personal access token
+// @Description Revoke an API token so it can no longer be used
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param tokenId path string true "Token ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /users/me/tokens/{tokenId} [delete]
+func (h *Handler) RevokePersonalAccessToken(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	tokenID, err := primitive.ObjectIDFromHex(c.Param("tokenId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid token ID", "INVALID_ID")
+		return
+	}
+
+	if err := h.repo.RevokePersonalAccessToken(c.Request.Context(), user.ID, tokenID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			response.NotFound(c, "Token not found", "TOKEN_NOT_FOUND")
 			return
 		}
+		response.InternalServerError(c, "Failed to revoke token", "DATABASE_ERROR")
+		return
 	}
 
-	// 2. Delete Profile Picture and Cover Image from Cloudinary
-	if user.ProfilePicturePublicID != "" {
-		_ = h.cloudinary.Delete(c.Request.Context(), user.ProfilePicturePublicID, "image")
+	response.Success(c, "Token revoked")
+}
+
+// SetupTwoFactor starts TOTP enrolment
+// @Summary Start two-factor setup
+// @Description Generate a TOTP secret and provisioning URI (for a QR code). Two-factor is enabled only after verification.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=TwoFactorSetupResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /auth/2fa/setup [post]
+func (h *Handler) SetupTwoFactor(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
 	}
-	if user.CoverImagePublicID != "" {
-		_ = h.cloudinary.Delete(c.Request.Context(), user.CoverImagePublicID, "image")
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		response.BadRequest(c, "Two-factor authentication is already enabled", "TWO_FACTOR_ALREADY_ENABLED")
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		response.InternalServerError(c, "Failed to generate secret", "INTERNAL_ERROR")
+		return
+	}
+
+	updates := map[string]interface{}{
+		"twoFactorPendingSecret": secret,
+	}
+	if err := h.repo.UpdateUser(c.Request.Context(), user.ID.Hex(), updates); err != nil {
+		response.InternalServerError(c, "Failed to start two-factor setup", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, TwoFactorIssuer, user.Email),
+	})
+}
+
+// EnableTwoFactor verifies the first code and enables two-factor authentication
+// @Summary Enable two-factor
+// @Description Verify a code from the authenticator app to finish enrolment. Returns one-time recovery codes.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} response.APIResponse{data=RecoveryCodesResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /auth/2fa/enable [post]
+func (h *Handler) EnableTwoFactor(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format", "INVALID_JSON")
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		response.BadRequest(c, "Two-factor authentication is already enabled", "TWO_FACTOR_ALREADY_ENABLED")
+		return
+	}
+	if user.TwoFactorPendingSecret == "" {
+		response.BadRequest(c, "Two-factor setup has not been started", "TWO_FACTOR_NOT_SETUP")
+		return
+	}
+
+	step, valid := totp.Validate(user.TwoFactorPendingSecret, req.Code, time.Now())
+	if !valid {
+		response.BadRequest(c, "Invalid two-factor code", "INVALID_2FA_CODE")
+		return
+	}
+
+	recoveryCodes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		response.InternalServerError(c, "Failed to generate recovery codes", "INTERNAL_ERROR")
+		return
+	}
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	updates := map[string]interface{}{
+		"twoFactorEnabled":       true,
+		"twoFactorSecret":        user.TwoFactorPendingSecret,
+		"twoFactorPendingSecret": "",
+		"twoFactorLastStep":      step,
+		"recoveryCodeHashes":     hashes,
+	}
+	if err := h.repo.UpdateUser(c.Request.Context(), user.ID.Hex(), updates); err != nil {
+		response.InternalServerError(c, "Failed to enable two-factor authentication", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, "Two-factor authentication enabled")
+}
+
+// DisableTwoFactor turns off two-factor authentication
+// @Summary Disable two-factor
+// @Description Disable two-factor authentication. Requires step-up verification via the X-2FA-Code header.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param X-2FA-Code header string true "TOTP or recovery code"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /auth/2fa/disable [post]
+func (h *Handler) DisableTwoFactor(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		response.BadRequest(c, "Two-factor authentication is not enabled", "TWO_FACTOR_NOT_ENABLED")
+		return
+	}
+
+	updates := map[string]interface{}{
+		"twoFactorEnabled":       false,
+		"twoFactorSecret":        "",
+		"twoFactorPendingSecret": "",
+		"recoveryCodeHashes":     []string{},
+	}
+	if err := h.repo.UpdateUser(c.Request.Context(), user.ID.Hex(), updates); err != nil {
+		response.InternalServerError(c, "Failed to disable two-factor authentication", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, "Two-factor authentication disabled")
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes with a fresh set
+// @Summary Regenerate recovery codes
+// @Description Invalidate existing recovery codes and issue new ones. Requires step-up verification via the X-2FA-Code header.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param X-2FA-Code header string true "TOTP or recovery code"
+// @Success 200 {object} response.APIResponse{data=RecoveryCodesResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /auth/2fa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
 	}
 
-	// 3. Delete User from DB
-	if err := h.repo.DeleteUser(c.Request.Context(), user.ID); err != nil {
-		response.InternalServerError(c, "Failed to delete user", "DATABASE_ERROR")
+	if !user.TwoFactorEnabled {
+		response.BadRequest(c, "Two-factor authentication is not enabled", "TWO_FACTOR_NOT_ENABLED")
 		return
 	}
 
-	response.Success(c, "Account deleted successfully")
+	recoveryCodes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		response.InternalServerError(c, "Failed to generate recovery codes", "INTERNAL_ERROR")
+		return
+	}
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	updates := map[string]interface{}{
+		"recoveryCodeHashes": hashes,
+	}
+	if err := h.repo.UpdateUser(c.Request.Context(), user.ID.Hex(), updates); err != nil {
+		response.InternalServerError(c, "Failed to regenerate recovery codes", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// VerifyTwoFactorLogin completes a two-step login
+// @Summary Verify two-factor login
+// @Description Exchange a login challenge token and a TOTP or recovery code for access and refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} response.APIResponse{data=AuthResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse "Too many failed attempts"
+// @Router /auth/2fa/verify [post]
+func (h *Handler) VerifyTwoFactorLogin(c *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format", "INVALID_JSON")
+		return
+	}
+
+	claims, err := ValidateTwoFactorChallenge(req.ChallengeToken, h.config)
+	if err != nil {
+		response.Unauthorized(c, "Invalid or expired challenge", "INVALID_CHALLENGE")
+		return
+	}
+
+	user, err := h.repo.GetUserByID(c.Request.Context(), claims.UserID)
+	if err != nil {
+		response.Unauthorized(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	if user.IsSuspended() {
+		response.Forbidden(c, "Account suspended", "ACCOUNT_SUSPENDED")
+		return
+	}
+
+	if user.IsDeletionDue(time.Now()) {
+		response.Forbidden(c, "Account has been deleted", "ACCOUNT_DELETED")
+		return
+	}
+
+	valid, err := VerifySecondFactor(c.Request.Context(), h.repo, user, strings.TrimSpace(req.Code))
+	if errors.Is(err, ErrTwoFactorLocked) {
+		response.Error(c, http.StatusTooManyRequests, "Too many failed two-factor attempts, try again later", "TWO_FACTOR_LOCKED")
+		return
+	}
+	if err != nil {
+		response.InternalServerError(c, "Failed to verify two-factor code", "AUTH_FAILED")
+		return
+	}
+	if !valid {
+		response.Unauthorized(c, "Invalid two-factor code", "INVALID_2FA_CODE")
+		return
+	}
+
+	accessToken, refreshToken, err := h.issueTokenPair(c, user)
+	if err != nil {
+		response.InternalServerError(c, "Failed to generate tokens", "AUTH_FAILED")
+		return
+	}
+
+	response.Success(c, AuthResponse{
+		User:         user,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, "Login successful")
+}
+
+// isBlockedBetween reports whether either user has blocked the other
+func (h *Handler) isBlockedBetween(ctx context.Context, userA, userB primitive.ObjectID) bool {
+	if h.blockChecker == nil {
+		return false
+	}
+	return h.blockChecker.IsBlocked(ctx, userA, userB)
 }
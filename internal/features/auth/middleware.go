@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"errors"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -43,7 +45,65 @@ func NewAuthMiddleware(repo *Repository, cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		if user.IsSuspended() {
+			response.Forbidden(c, "Account suspended", "ACCOUNT_SUSPENDED")
+			c.Abort()
+			return
+		}
+
 		c.Set("user", user)
 		c.Next()
 	}
 }
+
+// RequireStepUp demands a fresh second factor for sensitive actions.
+// Users with two-factor enabled must send a TOTP or recovery code in the X-2FA-Code header.
+// Wrong codes count towards the same lockout as login verification.
+// Must run after NewAuthMiddleware.
+func RequireStepUp(repo *Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		val, exists := c.Get("user")
+		if !exists {
+			response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+			c.Abort()
+			return
+		}
+		user, ok := val.(*User)
+		if !ok {
+			response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+			c.Abort()
+			return
+		}
+
+		if !user.TwoFactorEnabled {
+			c.Next()
+			return
+		}
+
+		code := strings.TrimSpace(c.GetHeader(StepUpHeader))
+		if code == "" {
+			response.Forbidden(c, "Two-factor verification required", "STEP_UP_REQUIRED")
+			c.Abort()
+			return
+		}
+
+		valid, err := VerifySecondFactor(c.Request.Context(), repo, user, code)
+		if errors.Is(err, ErrTwoFactorLocked) {
+			response.Error(c, http.StatusTooManyRequests, "Too many failed two-factor attempts, try again later", "TWO_FACTOR_LOCKED")
+			c.Abort()
+			return
+		}
+		if err != nil {
+			response.InternalServerError(c, "Failed to verify two-factor code", "INTERNAL_ERROR")
+			c.Abort()
+			return
+		}
+		if !valid {
+			response.Forbidden(c, "Invalid two-factor code", "INVALID_2FA_CODE")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -29,6 +29,84 @@ type User struct {
 	UpdatedAt              time.Time            `bson:"updatedAt" json:"updatedAt"`
 	Interests              []string             `bson:"interests" json:"interests"`
 	BlockedUsers           []primitive.ObjectID `bson:"blockedUsers" json:"blockedUsers"`
+	IsPrivate              bool                 `bson:"isPrivate" json:"isPrivate"` // Follows need approval; anchors visible to followers only
+
+	// Two-factor authentication (TOTP)
+	TwoFactorEnabled       bool       `bson:"twoFactorEnabled" json:"twoFactorEnabled"`
+	TwoFactorSecret        string     `bson:"twoFactorSecret,omitempty" json:"-"`
+	TwoFactorPendingSecret string     `bson:"twoFactorPendingSecret,omitempty" json:"-"` // Set during enrolment until verified
+	TwoFactorLastStep      int64      `bson:"twoFactorLastStep,omitempty" json:"-"`      // Last accepted time step, prevents code replay
+	RecoveryCodeHashes     []string   `bson:"recoveryCodeHashes,omitempty" json:"-"`
+	TwoFactorFailures      int        `bson:"twoFactorFailures,omitempty" json:"-"`    // Consecutive second-factor attempts without success
+	TwoFactorLockedUntil   *time.Time `bson:"twoFactorLockedUntil,omitempty" json:"-"` // Set after MaxTwoFactorFailures failed attempts
+
+	// Access control and moderation
+	Role             string     `bson:"role,omitempty" json:"role"`
+	SuspendedAt      *time.Time `bson:"suspendedAt,omitempty" json:"suspendedAt,omitempty"`
+	SuspensionReason string     `bson:"suspensionReason,omitempty" json:"-"`
+
+	// Deactivation and scheduled deletion
+	DeactivatedAt        *time.Time `bson:"deactivatedAt,omitempty" json:"-"`
+	DeletionRequestedAt  *time.Time `bson:"deletionRequestedAt,omitempty" json:"-"`
+	DeletionScheduledFor *time.Time `bson:"deletionScheduledFor,omitempty" json:"-"` // Purged by the account deletion job once reached
+}
+
+// User roles, in increasing order of privilege
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// roleRank orders roles so higher roles inherit lower-role permissions
+var roleRank = map[string]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+// IsValidRole reports whether role is a known role
+func IsValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// EffectiveRole returns the user's role, defaulting to RoleUser for accounts created before roles existed
+func (u *User) EffectiveRole() string {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
+}
+
+// HasRole reports whether the user holds the given role or a more privileged one
+func (u *User) HasRole(role string) bool {
+	required, ok := roleRank[role]
+	if !ok {
+		return false
+	}
+	return roleRank[u.EffectiveRole()] >= required
+}
+
+// IsSuspended reports whether the account has been suspended by a moderator
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// IsDeactivated reports whether the account is hidden from other users,
+// either by choice or because deletion has been requested
+func (u *User) IsDeactivated() bool {
+	return u.DeactivatedAt != nil
+}
+
+// IsPendingDeletion reports whether the account is scheduled for deletion
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledFor != nil
+}
+
+// IsDeletionDue reports whether the restore window has passed and the account awaits purging
+func (u *User) IsDeletionDue(now time.Time) bool {
+	return u.DeletionScheduledFor != nil && !now.Before(*u.DeletionScheduledFor)
 }
 
 // GoogleAuthRequest represents the payload for Google OAuth login
@@ -59,6 +137,102 @@ type RefreshTokenSession struct {
 	IPAddress string             `bson:"ipAddress" json:"ipAddress"`
 }
 
+// Personal access token scopes
+const (
+	ScopeAnchorsRead        = "anchors:read"
+	ScopeAnchorsWrite       = "anchors:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+)
+
+// PersonalAccessTokenPrefix marks a bearer token as a personal access token rather than a JWT
+const PersonalAccessTokenPrefix = "anc_pat_"
+
+// MaxPersonalAccessTokens is the maximum number of active tokens a user can hold
+const MaxPersonalAccessTokens = 20
+
+// PersonalAccessToken represents a named, scoped API token minted by a user.
+// Only the SHA-256 hash of the token is stored; the plaintext is returned once on creation.
+type PersonalAccessToken struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Name        string             `bson:"name" json:"name"`
+	TokenHash   string             `bson:"tokenHash" json:"-"`
+	TokenPrefix string             `bson:"tokenPrefix" json:"tokenPrefix"` // First characters, for identification in lists
+	Scopes      []string           `bson:"scopes" json:"scopes"`
+	ExpiresAt   *time.Time         `bson:"expiresAt" json:"expiresAt"`
+	LastUsedAt  *time.Time         `bson:"lastUsedAt" json:"lastUsedAt"`
+	RevokedAt   *time.Time         `bson:"revokedAt" json:"revokedAt"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// IsActive reports whether the token is neither revoked nor expired
+func (t *PersonalAccessToken) IsActive() bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || t.ExpiresAt.After(time.Now())
+}
+
+// HasScopes reports whether the token grants every one of the given scopes
+func (t *PersonalAccessToken) HasScopes(scopes ...string) bool {
+	for _, required := range scopes {
+		granted := false
+		for _, scope := range t.Scopes {
+			if scope == required {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			return false
+		}
+	}
+	return true
+}
+
+// CreatePersonalAccessTokenRequest for POST /users/me/tokens
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,min=1,max=50"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays *int     `json:"expiresInDays" binding:"omitempty,min=1,max=365"`
+}
+
+// CreatePersonalAccessTokenResponse contains the plaintext token, shown only once
+type CreatePersonalAccessTokenResponse struct {
+	Token               string               `json:"token"`
+	PersonalAccessToken *PersonalAccessToken `json:"personalAccessToken"`
+}
+
+// TwoFactorSetupResponse is returned when starting TOTP enrolment
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"` // Render as a QR code
+}
+
+// TwoFactorCodeRequest carries a TOTP code (or a recovery code where allowed)
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse contains one-time recovery codes, shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// TwoFactorChallengeResponse is returned by login when a second factor is required
+type TwoFactorChallengeResponse struct {
+	RequiresTwoFactor bool   `json:"requiresTwoFactor"`
+	ChallengeToken    string `json:"challengeToken"`
+	ExpiresIn         int    `json:"expiresIn"` // Seconds
+}
+
+// TwoFactorLoginRequest completes a login started with a challenge token
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
 // AuthResponse represents the response after successful authentication
 type AuthResponse struct {
 	User         *User  `json:"user"`
@@ -79,6 +253,7 @@ type LoginResponse struct {
 type UpdateProfileRequest struct {
 	DisplayName *string `json:"displayName" binding:"omitempty,min=2,max=50"`
 	Bio         *string `json:"bio" binding:"omitempty,max=200"`
+	IsPrivate   *bool   `json:"isPrivate"`
 }
 
 // PublicProfileResponse represents a user's public profile
@@ -93,6 +268,7 @@ type PublicProfileResponse struct {
 	FollowingCount    int                `json:"followingCount"`
 	AnchorCount       int                `json:"anchorCount"`
 	IsVerified        bool               `json:"isVerified"`
+	IsPrivate         bool               `json:"isPrivate"`
 	JoinedAt          time.Time          `json:"joinedAt"`
 	IsFollowing       bool               `json:"isFollowing"`
 	IsFollowedBy      bool               `json:"isFollowedBy"`
@@ -116,6 +292,18 @@ type OwnProfileResponse struct {
 	JoinedAt          time.Time          `json:"joinedAt"`
 	CreatedAt         time.Time          `json:"createdAt"`
 	UpdatedAt         time.Time          `json:"updatedAt"`
+	TwoFactorEnabled  bool               `json:"twoFactorEnabled"`
+	IsPrivate         bool               `json:"isPrivate"`
+
+	DeactivatedAt        *time.Time `json:"deactivatedAt,omitempty"`
+	DeletionScheduledFor *time.Time `json:"deletionScheduledFor,omitempty"`
+}
+
+// AccountStatusResponse is returned by deactivate, restore and delete account
+type AccountStatusResponse struct {
+	Deactivated          bool       `json:"deactivated"`
+	DeactivatedAt        *time.Time `json:"deactivatedAt,omitempty"`
+	DeletionScheduledFor *time.Time `json:"deletionScheduledFor,omitempty"`
 }
 
 // ProfilePictureResponse represents the response after uploading a profile picture
@@ -13,10 +13,16 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrUserNotFound is returned when no user has the given ID
+var ErrUserNotFound = errors.New("user not found")
+
 // Repository handles database interactions for the auth feature
 type Repository struct {
 	collection              *mongo.Collection
 	refreshTokensCollection *mongo.Collection
+	accessTokensCollection  *mongo.Collection
+	anchorsCollection       *mongo.Collection
+	commentsCollection      *mongo.Collection
 }
 
 // NewRepository initializes the repository and creates necessary indexes
@@ -52,6 +58,25 @@ func NewRepository(db *mongo.Database) *Repository {
 				}).
 				SetName("user_text_search"),
 		},
+		{
+			// Hide deactivated accounts from other users
+			Keys:    bson.D{{Key: "deactivatedAt", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			// Find accounts whose restore window has passed
+			Keys:    bson.D{{Key: "deletionScheduledFor", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			// Restrict private accounts' content to approved followers
+			Keys:    bson.D{{Key: "isPrivate", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"isPrivate": true}),
+		},
+		{
+			// Find who blocked a user (blocks apply both ways)
+			Keys: bson.D{{Key: "blockedUsers", Value: 1}},
+		},
 	})
 
 	refreshTokensCollection := db.Collection("refresh_tokens")
@@ -70,9 +95,26 @@ func NewRepository(db *mongo.Database) *Repository {
 		},
 	})
 
+	accessTokensCollection := db.Collection("personal_access_tokens")
+	// Create indexes for personal access tokens
+	_, _ = accessTokensCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// Lookup by hash on every authenticated request
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// List a user's tokens
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+	})
+
 	return &Repository{
 		collection:              collection,
 		refreshTokensCollection: refreshTokensCollection,
+		accessTokensCollection:  accessTokensCollection,
+		anchorsCollection:       db.Collection("anchors"),
+		commentsCollection:      db.Collection("comments"),
 	}
 }
 
@@ -80,6 +122,9 @@ func NewRepository(db *mongo.Database) *Repository {
 func (r *Repository) CreateUser(ctx context.Context, user *User) error {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
+	if user.Role == "" {
+		user.Role = RoleUser
+	}
 
 	result, err := r.collection.InsertOne(ctx, user)
 	if err != nil {
@@ -273,7 +318,7 @@ func (r *Repository) GetUserByObjectID(ctx context.Context, userID primitive.Obj
 	err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -353,6 +398,28 @@ func (r *Repository) RevokeRefreshToken(ctx context.Context, tokenID string) err
 	return err
 }
 
+// ListActiveRefreshTokens returns the user's unexpired, unrevoked sessions
+func (r *Repository) ListActiveRefreshTokens(ctx context.Context, userID primitive.ObjectID) ([]RefreshTokenSession, error) {
+	filter := bson.M{
+		"userId":    userID,
+		"revoked":   false,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.refreshTokensCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []RefreshTokenSession{}
+	if err = cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
 // RevokeAllUserTokens revokes all refresh tokens for a user (Logout All Devices)
 func (r *Repository) RevokeAllUserTokens(ctx context.Context, userID primitive.ObjectID) error {
 	_, err := r.refreshTokensCollection.DeleteMany(ctx, bson.M{"userId": userID})
@@ -361,8 +428,358 @@ func (r *Repository) RevokeAllUserTokens(ctx context.Context, userID primitive.O
 
 // DeleteUser permanently removes a user from the database
 func (r *Repository) DeleteUser(ctx context.Context, userID primitive.ObjectID) error {
-	// Also delete all refresh tokens
+	// Also delete all refresh tokens and personal access tokens
 	_ = r.RevokeAllUserTokens(ctx, userID)
+	_, _ = r.accessTokensCollection.DeleteMany(ctx, bson.M{"userId": userID})
 	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": userID})
 	return err
 }
+
+// DeactivateUser hides the user's account and content from other users
+func (r *Repository) DeactivateUser(ctx context.Context, userID primitive.ObjectID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"deactivatedAt": at, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return r.SyncContentVisibility(ctx, userID)
+}
+
+// ScheduleUserDeletion deactivates the account and marks it for purging at scheduledFor
+func (r *Repository) ScheduleUserDeletion(ctx context.Context, userID primitive.ObjectID, requestedAt, scheduledFor time.Time) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{
+			"deactivatedAt":        requestedAt,
+			"deletionRequestedAt":  requestedAt,
+			"deletionScheduledFor": scheduledFor,
+			"updatedAt":            time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return r.SyncContentVisibility(ctx, userID)
+}
+
+// RestoreUser reactivates the account and cancels any pending deletion.
+// Accounts whose deletion date has already passed cannot be restored.
+func (r *Repository) RestoreUser(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": userID,
+		"$or": []bson.M{
+			{"deletionScheduledFor": nil},
+			{"deletionScheduledFor": bson.M{"$gt": now}},
+		},
+	}
+	update := bson.M{
+		"$unset": bson.M{"deactivatedAt": "", "deletionRequestedAt": "", "deletionScheduledFor": ""},
+		"$set":   bson.M{"updatedAt": now},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount == 0 {
+		return false, nil
+	}
+	return true, r.SyncContentVisibility(ctx, userID)
+}
+
+// SyncContentVisibility copies the user's deactivated and private state onto their anchors,
+// and the deactivated state onto their comments, so listings hide them with a field filter
+// instead of loading every hidden account's ID. Must be called whenever deactivatedAt or
+// isPrivate changes.
+func (r *Repository) SyncContentVisibility(ctx context.Context, userID primitive.ObjectID) error {
+	user, err := r.GetUserByObjectID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.anchorsCollection.UpdateMany(ctx,
+		bson.M{"userId": userID},
+		bson.M{"$set": bson.M{
+			"authorDeactivated": user.IsDeactivated(),
+			"authorPrivate":     user.IsPrivate,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.commentsCollection.UpdateMany(ctx,
+		bson.M{"userId": userID},
+		bson.M{"$set": bson.M{"authorDeactivated": user.IsDeactivated()}},
+	)
+	return err
+}
+
+// BackfillContentVisibility syncs the content of every deactivated or private account,
+// for content written before authors' state was copied onto it
+func (r *Repository) BackfillContentVisibility(ctx context.Context) error {
+	ids, err := r.findUserIDs(ctx, bson.M{"$or": []bson.M{
+		{"deactivatedAt": bson.M{"$ne": nil}},
+		{"isPrivate": true},
+	}})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := r.SyncContentVisibility(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDeactivatedUserIDs returns the IDs of all deactivated accounts, whose content is hidden from others
+func (r *Repository) GetDeactivatedUserIDs(ctx context.Context) ([]primitive.ObjectID, error) {
+	return r.findUserIDs(ctx, bson.M{"deactivatedAt": bson.M{"$ne": nil}})
+}
+
+func (r *Repository) findUserIDs(ctx context.Context, filter bson.M) ([]primitive.ObjectID, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err == nil {
+			ids = append(ids, doc.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// GetUserIDsDueForDeletion returns accounts whose scheduled deletion date has passed
+func (r *Repository) GetUserIDsDueForDeletion(ctx context.Context, now time.Time, limit int) ([]primitive.ObjectID, error) {
+	filter := bson.M{"deletionScheduledFor": bson.M{"$lte": now}}
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.D{{Key: "deletionScheduledFor", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err == nil {
+			ids = append(ids, doc.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// RemoveFromBlockLists removes a user from every other user's block list
+func (r *Repository) RemoveFromBlockLists(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"blockedUsers": userID},
+		bson.M{"$pull": bson.M{"blockedUsers": userID}},
+	)
+	return err
+}
+
+// SetFollowCounts overwrites a user's follower and following counters with recounted values
+func (r *Repository) SetFollowCounts(ctx context.Context, userID primitive.ObjectID, followers, following int64) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{
+			"followerCount":  followers,
+			"followingCount": following,
+			"updatedAt":      time.Now(),
+		}},
+	)
+	return err
+}
+
+// RevokeAllPersonalAccessTokens revokes every active personal access token of a user
+func (r *Repository) RevokeAllPersonalAccessTokens(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.accessTokensCollection.UpdateMany(ctx,
+		bson.M{"userId": userID, "revokedAt": nil},
+		bson.M{"$set": bson.M{"revokedAt": time.Now()}},
+	)
+	return err
+}
+
+// CreatePersonalAccessToken stores a new personal access token
+func (r *Repository) CreatePersonalAccessToken(ctx context.Context, token *PersonalAccessToken) error {
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := r.accessTokensCollection.InsertOne(ctx, token)
+	return err
+}
+
+// GetPersonalAccessTokenByHash retrieves a personal access token by its hash
+func (r *Repository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error) {
+	var token PersonalAccessToken
+	err := r.accessTokensCollection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListPersonalAccessTokens returns all tokens belonging to a user, newest first
+func (r *Repository) ListPersonalAccessTokens(ctx context.Context, userID primitive.ObjectID) ([]PersonalAccessToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.accessTokensCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []PersonalAccessToken{}
+	if err = cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// CountActivePersonalAccessTokens counts a user's tokens that are neither revoked nor expired
+func (r *Repository) CountActivePersonalAccessTokens(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	filter := bson.M{
+		"userId":    userID,
+		"revokedAt": nil,
+		"$or": []bson.M{
+			{"expiresAt": nil},
+			{"expiresAt": bson.M{"$gt": time.Now()}},
+		},
+	}
+	return r.accessTokensCollection.CountDocuments(ctx, filter)
+}
+
+// RevokePersonalAccessToken marks a user's token as revoked
+func (r *Repository) RevokePersonalAccessToken(ctx context.Context, userID, tokenID primitive.ObjectID) error {
+	filter := bson.M{"_id": tokenID, "userId": userID}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now()}}
+
+	result, err := r.accessTokensCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// TouchPersonalAccessToken records the last time a token was used
+func (r *Repository) TouchPersonalAccessToken(ctx context.Context, tokenID primitive.ObjectID) error {
+	_, err := r.accessTokensCollection.UpdateOne(ctx,
+		bson.M{"_id": tokenID},
+		bson.M{"$set": bson.M{"lastUsedAt": time.Now()}},
+	)
+	return err
+}
+
+// ConsumeRecoveryCode atomically removes a recovery code hash, returning false if it was not present
+func (r *Repository) ConsumeRecoveryCode(ctx context.Context, userID primitive.ObjectID, codeHash string) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": userID, "recoveryCodeHashes": codeHash},
+		bson.M{"$pull": bson.M{"recoveryCodeHashes": codeHash}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// MarkTwoFactorStep records an accepted TOTP time step.
+// It returns false if the step (or a later one) was already used, preventing code replay.
+func (r *Repository) MarkTwoFactorStep(ctx context.Context, userID primitive.ObjectID, step int64) (bool, error) {
+	filter := bson.M{
+		"_id": userID,
+		"$or": []bson.M{
+			{"twoFactorLastStep": bson.M{"$exists": false}},
+			{"twoFactorLastStep": bson.M{"$lt": step}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"twoFactorLastStep": step}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ReserveTwoFactorAttempt counts a second-factor attempt before the code is checked, so
+// concurrent guesses cannot exceed the limit. It returns false while verification is locked,
+// and locks it for lockout once more than maxFailures attempts went without success.
+func (r *Repository) ReserveTwoFactorAttempt(ctx context.Context, userID primitive.ObjectID, maxFailures int, lockout time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": userID,
+		"$or": []bson.M{
+			{"twoFactorLockedUntil": bson.M{"$exists": false}},
+			{"twoFactorLockedUntil": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$inc":   bson.M{"twoFactorFailures": 1},
+		"$unset": bson.M{"twoFactorLockedUntil": ""},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"twoFactorFailures": 1})
+
+	var user User
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if user.TwoFactorFailures <= maxFailures {
+		return true, nil
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{
+			"$set":   bson.M{"twoFactorLockedUntil": now.Add(lockout)},
+			"$unset": bson.M{"twoFactorFailures": ""},
+		},
+	)
+	return false, err
+}
+
+// ResetTwoFactorFailures clears the failed attempt count after a successful verification
+func (r *Repository) ResetTwoFactorFailures(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$unset": bson.M{"twoFactorFailures": "", "twoFactorLockedUntil": ""}},
+	)
+	return err
+}
@@ -11,8 +11,8 @@ import (
 )
 
 // RegisterRoutes registers the auth routes and initializes dependencies
-// We accept followService and anchorService as interfaces because we can't import those packages due to cycle
-func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config, followService FollowService, anchorService AnchorService) {
+// We accept followService, anchorService and blockChecker as interfaces because we can't import those packages due to cycle
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config, followService FollowService, anchorService AnchorService, blockChecker BlockChecker) {
 	// Init Firebase
 	firebaseClient, err := InitFirebase(cfg)
 	if err != nil {
@@ -29,8 +29,9 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	repo := NewRepository(db)
 
 	// Use the passed services
-	handler := NewHandler(repo, firebaseClient, cfg, cloudinarySvc, followService, anchorService)
+	handler := NewHandler(repo, firebaseClient, cfg, cloudinarySvc, followService, anchorService, blockChecker)
 	authMiddleware := NewAuthMiddleware(repo, cfg)
+	stepUp := RequireStepUp(repo)
 
 	// Auth routes
 	auth := router.Group("/auth")
@@ -39,13 +40,20 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 		auth.POST("/dev-login", handler.DevLogin)
 		auth.POST("/refresh", handler.RefreshToken)
 		auth.POST("/logout", handler.Logout)
-		auth.POST("/revoke-all", authMiddleware, handler.RevokeAllTokens)
+		auth.POST("/revoke-all", authMiddleware, stepUp, handler.RevokeAllTokens)
 
 		// Legacy/Compatible routes
 		auth.GET("/me", authMiddleware, handler.GetMe)
 		auth.PATCH("/profile", authMiddleware, handler.UpdateProfile)
 		auth.GET("/username/check", handler.CheckUsernameAvailability)
-		auth.PATCH("/username", authMiddleware, handler.UpdateUsername)
+		auth.PATCH("/username", authMiddleware, stepUp, handler.UpdateUsername)
+
+		// Two-factor authentication
+		auth.POST("/2fa/verify", handler.VerifyTwoFactorLogin)
+		auth.POST("/2fa/setup", authMiddleware, handler.SetupTwoFactor)
+		auth.POST("/2fa/enable", authMiddleware, handler.EnableTwoFactor)
+		auth.POST("/2fa/disable", authMiddleware, stepUp, handler.DisableTwoFactor)
+		auth.POST("/2fa/recovery-codes", authMiddleware, stepUp, handler.RegenerateRecoveryCodes)
 	}
 
 	// User routes
@@ -57,11 +65,18 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 		{
 			me.GET("", handler.GetOwnProfile)
 			me.PATCH("", handler.UpdateProfile)
-			me.DELETE("", handler.DeleteAccount)
+			me.DELETE("", stepUp, handler.DeleteAccount)
+			me.POST("/deactivate", handler.DeactivateAccount)
+			me.POST("/restore", handler.RestoreAccount)
 			me.POST("/profile-picture", handler.UploadProfilePicture)
 			me.POST("/cover-image", handler.UploadCoverImage)
 			me.DELETE("/profile-picture", handler.RemoveProfilePicture)
 			me.DELETE("/cover-image", handler.RemoveCoverImage)
+
+			// Personal access tokens (session auth only - a token cannot mint tokens)
+			me.GET("/tokens", handler.ListPersonalAccessTokens)
+			me.POST("/tokens", handler.CreatePersonalAccessToken)
+			me.DELETE("/tokens/:tokenId", handler.RevokePersonalAccessToken)
 		}
 
 		// Public profile routes (after /me)
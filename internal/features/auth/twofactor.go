@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/xyz-asif/gotodo/internal/config"
+	idToken "github.com/xyz-asif/gotodo/internal/pkg/jwt"
+	"github.com/xyz-asif/gotodo/internal/pkg/totp"
+)
+
+const (
+	// TwoFactorIssuer is shown as the account issuer in authenticator apps
+	TwoFactorIssuer = "Anchor"
+
+	// RecoveryCodeCount is the number of recovery codes issued at a time
+	RecoveryCodeCount = 10
+
+	// StepUpHeader carries a TOTP or recovery code for sensitive actions
+	StepUpHeader = "X-2FA-Code"
+
+	// twoFactorChallengeExpiry is how long a login challenge token stays valid
+	twoFactorChallengeExpiry = 5 * time.Minute
+
+	twoFactorChallengeRole = "2fa_challenge"
+
+	// MaxTwoFactorFailures is how many consecutive wrong codes are accepted before
+	// second-factor verification is locked
+	MaxTwoFactorFailures = 5
+
+	// TwoFactorLockout is how long second-factor verification stays locked
+	TwoFactorLockout = 15 * time.Minute
+)
+
+// ErrTwoFactorLocked is returned while second-factor verification is locked after too many failures
+var ErrTwoFactorLocked = errors.New("too many failed two-factor attempts")
+
+// challengeJWTConfig signs challenge tokens with a derived secret so they can
+// never be accepted as access tokens by the auth middleware
+func challengeJWTConfig(cfg *config.Config) *idToken.Config {
+	return &idToken.Config{
+		Secret:        cfg.JWTSecret + ":" + twoFactorChallengeRole,
+		AccessExpiry:  twoFactorChallengeExpiry,
+		Issuer:        "gotodo-api",
+		Audience:      "gotodo-users",
+		SigningMethod: jwt.SigningMethodHS256,
+	}
+}
+
+// GenerateTwoFactorChallenge issues a short-lived token proving the first login step succeeded
+func GenerateTwoFactorChallenge(user *User, cfg *config.Config) (string, error) {
+	return idToken.GenerateTokenWithRole(user.ID.Hex(), user.Email, twoFactorChallengeRole, challengeJWTConfig(cfg))
+}
+
+// ValidateTwoFactorChallenge verifies a challenge token and returns its claims
+func ValidateTwoFactorChallenge(token string, cfg *config.Config) (*idToken.Claims, error) {
+	return idToken.ValidateTokenWithRole(token, challengeJWTConfig(cfg).Secret, twoFactorChallengeRole)
+}
+
+// VerifySecondFactor checks a TOTP code or, failing that, consumes a recovery code.
+// TOTP codes are single-use: a code for an already accepted time step is rejected.
+// Every attempt counts against the user until one succeeds; after MaxTwoFactorFailures
+// verification is locked for TwoFactorLockout and ErrTwoFactorLocked is returned, whichever
+// login challenge or step-up route the codes arrive through.
+func VerifySecondFactor(ctx context.Context, repo *Repository, user *User, code string) (bool, error) {
+	if !user.TwoFactorEnabled || user.TwoFactorSecret == "" || code == "" {
+		return false, nil
+	}
+
+	allowed, err := repo.ReserveTwoFactorAttempt(ctx, user.ID, MaxTwoFactorFailures, TwoFactorLockout)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		return false, ErrTwoFactorLocked
+	}
+
+	valid, err := checkSecondFactor(ctx, repo, user, code)
+	if err != nil || !valid {
+		return false, err
+	}
+
+	if err := repo.ResetTwoFactorFailures(ctx, user.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkSecondFactor validates a TOTP code, falling back to consuming a recovery code
+func checkSecondFactor(ctx context.Context, repo *Repository, user *User, code string) (bool, error) {
+	if step, ok := totp.Validate(user.TwoFactorSecret, code, time.Now()); ok {
+		return repo.MarkTwoFactorStep(ctx, user.ID, step)
+	}
+	return repo.ConsumeRecoveryCode(ctx, user.ID, HashRecoveryCode(code))
+}
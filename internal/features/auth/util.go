@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"regexp"
 	"strings"
 )
@@ -34,3 +37,52 @@ func GenerateUniqueUsername(displayName string) string {
 
 	return username
 }
+
+// GeneratePersonalAccessToken creates a new random personal access token.
+// It returns the plaintext token and a short display prefix.
+func GeneratePersonalAccessToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := PersonalAccessTokenPrefix + hex.EncodeToString(b)
+	return token, token[:len(PersonalAccessTokenPrefix)+6], nil
+}
+
+// HashPersonalAccessToken returns the hex-encoded SHA-256 hash used to store and look up a token
+func HashPersonalAccessToken(token string) string {
+	return sha256Hex(token)
+}
+
+// IsPersonalAccessToken reports whether a bearer token looks like a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// GenerateRecoveryCodes creates n one-time recovery codes in the form "xxxxx-xxxxx"
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(b)
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode normalizes a recovery code (case, dashes, spaces) and hashes it
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return sha256Hex(code)
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
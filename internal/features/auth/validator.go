@@ -100,3 +100,34 @@ func ValidateCoverImage(file *multipart.FileHeader) error {
 
 	return nil
 }
+
+// ValidateCreatePersonalAccessTokenRequest validates the token name and requested scopes
+func ValidateCreatePersonalAccessTokenRequest(req *CreatePersonalAccessTokenRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("token name is required")
+	}
+
+	validScopes := map[string]bool{
+		ScopeAnchorsRead:        true,
+		ScopeAnchorsWrite:       true,
+		ScopeNotificationsRead:  true,
+		ScopeNotificationsWrite: true,
+	}
+
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, scope := range req.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !validScopes[scope] {
+			return errors.New("invalid scope: " + scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	req.Scopes = scopes
+
+	return nil
+}
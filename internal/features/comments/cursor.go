@@ -0,0 +1,42 @@
+package comments
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EncodeReplyCursor creates a base64 encoded cursor string from a reply's timestamp and ID
+func EncodeReplyCursor(timestamp time.Time, commentID primitive.ObjectID) string {
+	jsonBytes, _ := json.Marshal(ReplyCursor{
+		Timestamp: timestamp,
+		CommentID: commentID,
+	})
+	return base64.StdEncoding.EncodeToString(jsonBytes)
+}
+
+// DecodeReplyCursor decodes a base64 encoded cursor string into a ReplyCursor struct
+func DecodeReplyCursor(cursor string) (*ReplyCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	jsonBytes, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor format: not base64")
+	}
+
+	var cursorData ReplyCursor
+	if err := json.Unmarshal(jsonBytes, &cursorData); err != nil {
+		return nil, errors.New("invalid cursor format: invalid json")
+	}
+
+	if cursorData.Timestamp.IsZero() || cursorData.CommentID.IsZero() {
+		return nil, errors.New("invalid cursor: missing fields")
+	}
+
+	return &cursorData, nil
+}
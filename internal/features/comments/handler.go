@@ -2,13 +2,17 @@ package comments
 
 import (
 	"context"
+	"fmt"
 	"math"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/anchors"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -18,15 +22,19 @@ type Handler struct {
 	authRepo            *auth.Repository
 	anchorsRepo         *anchors.Repository
 	notificationService *notifications.Service
+	webhookService      *webhooks.Service
+	blockPolicy         *safety.BlockPolicy
 	config              *config.Config
 }
 
-func NewHandler(repo *Repository, authRepo *auth.Repository, anchorsRepo *anchors.Repository, notificationService *notifications.Service, cfg *config.Config) *Handler {
+func NewHandler(repo *Repository, authRepo *auth.Repository, anchorsRepo *anchors.Repository, notificationService *notifications.Service, webhookService *webhooks.Service, blockPolicy *safety.BlockPolicy, cfg *config.Config) *Handler {
 	return &Handler{
 		repo:                repo,
 		authRepo:            authRepo,
 		anchorsRepo:         anchorsRepo,
 		notificationService: notificationService,
+		webhookService:      webhookService,
+		blockPolicy:         blockPolicy,
 		config:              cfg,
 	}
 }
@@ -39,7 +47,7 @@ func NewHandler(repo *Repository, authRepo *auth.Repository, anchorsRepo *anchor
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Anchor ID"
-// @Param request body CreateCommentRequest true "Comment content"
+// @Param request body CreateCommentRequest true "Comment content, with parentId when replying and itemId to discuss a single item"
 // @Success 201 {object} response.APIResponse{data=CommentResponse}
 // @Failure 400 {object} response.APIResponse
 // @Failure 401 {object} response.APIResponse
@@ -96,6 +104,64 @@ func (h *Handler) AddComment(c *gin.Context) {
 		}
 	}
 
+	// Users who blocked each other cannot comment on each other's anchors
+	if h.blockPolicy.IsBlocked(c.Request.Context(), currentUser.ID, anchor.UserID) {
+		response.Forbidden(c, "BLOCKED", "You cannot comment on this anchor")
+		return
+	}
+
+	// Owners can lock the discussion on their anchors
+	if anchor.CommentsLocked && !anchor.IsOwnedBy(currentUser.ID) {
+		response.Forbidden(c, "COMMENTS_LOCKED", "Comments are locked on this anchor")
+		return
+	}
+
+	// Resolve the comment being replied to
+	var parent *Comment
+	if req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARENT_ID", "Invalid parent comment ID")
+			return
+		}
+
+		parent, err = h.repo.GetCommentByID(c.Request.Context(), parentID)
+		if err != nil || parent.AnchorID != anchorID || !canSeeComment(parent, anchor, &currentUser.ID) {
+			response.NotFound(c, "PARENT_NOT_FOUND", "Parent comment not found")
+			return
+		}
+
+		if h.blockPolicy.IsBlocked(c.Request.Context(), currentUser.ID, parent.UserID) {
+			response.Forbidden(c, "BLOCKED", "You cannot reply to this comment")
+			return
+		}
+	}
+
+	// Resolve the item being discussed; replies stay on their parent's item
+	var itemID *primitive.ObjectID
+	if parent != nil {
+		itemID = parent.ItemID
+	}
+	if req.ItemID != "" {
+		id, err := primitive.ObjectIDFromHex(req.ItemID)
+		if err != nil {
+			response.BadRequest(c, "INVALID_ITEM_ID", "Invalid item ID")
+			return
+		}
+
+		if parent != nil && (parent.ItemID == nil || *parent.ItemID != id) {
+			response.BadRequest(c, "ITEM_MISMATCH", "Replies must target the same item as their parent")
+			return
+		}
+
+		item, err := h.anchorsRepo.GetItemByID(c.Request.Context(), id)
+		if err != nil || item.AnchorID != anchorID {
+			response.NotFound(c, "ITEM_NOT_FOUND", "Item not found")
+			return
+		}
+		itemID = &item.ID
+	}
+
 	// Extract mentions
 	mentionUsernames := ExtractMentions(req.Content)
 
@@ -112,12 +178,27 @@ func (h *Handler) AddComment(c *gin.Context) {
 		}
 	}
 
+	// Blocked users cannot be mentioned
+	mentionIDs = h.blockPolicy.FilterBlocked(c.Request.Context(), currentUser.ID, mentionIDs)
+
 	// Create comment
 	comment := &Comment{
 		AnchorID: anchorID,
+		ItemID:   itemID,
 		UserID:   currentUser.ID,
 		Content:  req.Content,
 		Mentions: mentionIDs,
+
+		AuthorDeactivated: currentUser.IsDeactivated(),
+	}
+	if parent != nil {
+		comment.ParentID, comment.Depth = threadPosition(parent)
+	}
+
+	// Comments matching the owner's keyword filter wait for review
+	comment.Status = StatusPublished
+	if !anchor.IsOwnedBy(currentUser.ID) && matchesKeywordFilter(comment.Content, anchor.CommentFilterWords) {
+		comment.Status = StatusHeld
 	}
 
 	if err := h.repo.CreateComment(c.Request.Context(), comment); err != nil {
@@ -125,34 +206,31 @@ func (h *Handler) AddComment(c *gin.Context) {
 		return
 	}
 
-	// Increment anchor comment count
-	_ = h.anchorsRepo.IncrementCommentCount(c.Request.Context(), anchorID, 1)
+	// Build response
+	commentResponse := h.buildCommentResponse(comment, currentUser, false)
 
-	// Update engagement score (async)
-	go func() {
-		_ = h.anchorsRepo.UpdateEngagementScore(context.Background(), anchorID)
-	}()
+	// Held comments stay out of counts and notifications until the owner approves them
+	if !comment.IsPublished() {
+		response.Created(c, commentResponse, "Comment held for review by the anchor owner")
+		return
+	}
 
-	// Create notifications (async)
-	go func() {
-		commentData := &notifications.CommentData{
-			ID:       comment.ID,
-			AnchorID: comment.AnchorID,
-			Content:  comment.Content,
-			Mentions: comment.Mentions,
-		}
-		_ = h.notificationService.CreateCommentNotifications(context.Background(), commentData, anchor.ID, anchor.UserID, currentUser)
-	}()
+	// Increment anchor and item comment counts
+	h.adjustCommentCounts(c.Request.Context(), comment, 1)
 
-	// Build response
-	commentResponse := h.buildCommentResponse(comment, currentUser, false)
+	// Create notifications (async)
+	var parentAuthorID *primitive.ObjectID
+	if parent != nil {
+		parentAuthorID = &parent.UserID
+	}
+	go h.notifyNewComment(comment, anchor, parentAuthorID, currentUser)
 
 	response.Created(c, commentResponse)
 }
 
 // ListComments godoc
 // @Summary List comments for anchor
-// @Description Get paginated list of comments
+// @Description Get paginated list of top-level comments; replies are listed per comment
 // @Tags comments
 // @Produce json
 // @Param id path string true "Anchor ID"
@@ -173,6 +251,42 @@ func (h *Handler) ListComments(c *gin.Context) {
 		return
 	}
 
+	h.listComments(c, anchorID, nil)
+}
+
+// ListItemComments godoc
+// @Summary List comments for an item
+// @Description Get paginated list of top-level comments on a single item of an anchor
+// @Tags comments
+// @Produce json
+// @Param id path string true "Anchor ID"
+// @Param itemId path string true "Item ID"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 20, max 50)"
+// @Param sort query string false "Sort: newest, oldest, top (default newest)"
+// @Success 200 {object} response.APIResponse{data=PaginatedCommentsResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /anchors/{id}/items/{itemId}/comments [get]
+func (h *Handler) ListItemComments(c *gin.Context) {
+	anchorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "Invalid anchor ID")
+		return
+	}
+
+	itemID, err := primitive.ObjectIDFromHex(c.Param("itemId"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ITEM_ID", "Invalid item ID")
+		return
+	}
+
+	h.listComments(c, anchorID, &itemID)
+}
+
+// listComments writes a page of top-level comments on an anchor, or on one of its items when itemID is set
+func (h *Handler) listComments(c *gin.Context, anchorID primitive.ObjectID, itemID *primitive.ObjectID) {
 	// Get anchor and check access
 	anchor, err := h.anchorsRepo.GetAnchorByID(c.Request.Context(), anchorID)
 	if err != nil {
@@ -201,6 +315,25 @@ func (h *Handler) ListComments(c *gin.Context) {
 		}
 	}
 
+	// Comments are hidden between users who blocked each other
+	var hiddenUserIDs []primitive.ObjectID
+	if currentUserID != nil {
+		if h.blockPolicy.IsBlocked(c.Request.Context(), *currentUserID, anchor.UserID) {
+			response.NotFound(c, "ANCHOR_NOT_FOUND", "Anchor not found")
+			return
+		}
+		hiddenUserIDs = h.blockPolicy.BlockedUserIDs(c.Request.Context(), *currentUserID)
+	}
+
+	// The item must belong to this anchor
+	if itemID != nil {
+		item, err := h.anchorsRepo.GetItemByID(c.Request.Context(), *itemID)
+		if err != nil || item.AnchorID != anchorID {
+			response.NotFound(c, "ITEM_NOT_FOUND", "Item not found")
+			return
+		}
+	}
+
 	// Bind and validate query
 	var query CommentListQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
@@ -214,7 +347,7 @@ func (h *Handler) ListComments(c *gin.Context) {
 	}
 
 	// Get comments
-	comments, total, err := h.repo.GetCommentsByAnchor(c.Request.Context(), anchorID, query.Sort, query.Page, query.Limit)
+	comments, total, err := h.repo.GetCommentsByAnchor(c.Request.Context(), anchorID, itemID, query.Sort, query.Page, query.Limit, hiddenUserIDs, moderationScope(anchor, currentUserID))
 	if err != nil {
 		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch comments")
 		return
@@ -230,6 +363,7 @@ func (h *Handler) ListComments(c *gin.Context) {
 		Meta: CommentListMeta{
 			Sort:     query.Sort,
 			AnchorID: anchorID,
+			ItemID:   itemID,
 		},
 	}
 	resp.Pagination.Page = query.Page
@@ -287,6 +421,17 @@ func (h *Handler) GetComment(c *gin.Context) {
 		}
 	}
 
+	if currentUserID != nil && (h.blockPolicy.IsBlocked(c.Request.Context(), *currentUserID, anchor.UserID) ||
+		h.blockPolicy.IsBlocked(c.Request.Context(), *currentUserID, comment.UserID)) {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
+	if !canSeeComment(comment, anchor, currentUserID) {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
 	// Get author
 	author, _ := h.authRepo.GetUserByObjectID(c.Request.Context(), comment.UserID)
 
@@ -301,9 +446,115 @@ func (h *Handler) GetComment(c *gin.Context) {
 	response.Success(c, commentResponse)
 }
 
+// ListReplies godoc
+// @Summary List replies to a comment
+// @Description Get direct replies of a comment, oldest first, with cursor pagination
+// @Tags comments
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Items per page (default 20, max 50)"
+// @Success 200 {object} response.APIResponse{data=RepliesResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /comments/{id}/replies [get]
+func (h *Handler) ListReplies(c *gin.Context) {
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "Invalid comment ID")
+		return
+	}
+
+	var query ReplyListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "INVALID_QUERY", "Invalid query parameters")
+		return
+	}
+	_ = ValidateReplyListQuery(&query)
+
+	cursor, err := DecodeReplyCursor(query.Cursor)
+	if err != nil {
+		response.BadRequest(c, "INVALID_CURSOR", err.Error())
+		return
+	}
+
+	// Deleted comments that still have replies can be browsed as placeholders
+	parent, err := h.repo.GetThreadComment(c.Request.Context(), commentID)
+	if err != nil {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
+	anchor, err := h.anchorsRepo.GetAnchorByID(c.Request.Context(), parent.AnchorID)
+	if err != nil || anchor.DeletedAt != nil {
+		response.NotFound(c, "ANCHOR_NOT_FOUND", "Anchor not found")
+		return
+	}
+
+	var currentUserID *primitive.ObjectID
+	if usr, exists := c.Get("user"); exists {
+		if user, ok := usr.(*auth.User); ok {
+			currentUserID = &user.ID
+		}
+	}
+
+	if anchor.Visibility == anchors.VisibilityPrivate {
+		if currentUserID == nil || !anchor.IsOwnedBy(*currentUserID) {
+			response.Forbidden(c, "ACCESS_DENIED", "Cannot view comments on private anchor")
+			return
+		}
+	}
+
+	// Replies are hidden between users who blocked each other
+	var hiddenUserIDs []primitive.ObjectID
+	if currentUserID != nil {
+		if h.blockPolicy.IsBlocked(c.Request.Context(), *currentUserID, anchor.UserID) ||
+			(!parent.IsDeleted() && h.blockPolicy.IsBlocked(c.Request.Context(), *currentUserID, parent.UserID)) {
+			response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+			return
+		}
+		hiddenUserIDs = h.blockPolicy.BlockedUserIDs(c.Request.Context(), *currentUserID)
+	}
+
+	if !canSeeComment(parent, anchor, currentUserID) {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
+	// Fetch one extra to detect another page
+	replies, err := h.repo.GetReplies(c.Request.Context(), commentID, cursor, query.Limit+1, hiddenUserIDs, moderationScope(anchor, currentUserID))
+	if err != nil {
+		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch replies")
+		return
+	}
+
+	hasMore := len(replies) > query.Limit
+	if hasMore {
+		replies = replies[:query.Limit]
+	}
+
+	var nextCursor *string
+	if hasMore {
+		last := replies[len(replies)-1]
+		encoded := EncodeReplyCursor(last.CreatedAt, last.ID)
+		nextCursor = &encoded
+	}
+
+	response.Success(c, RepliesResponse{
+		Replies: h.buildCommentListResponse(c.Request.Context(), replies, currentUserID),
+		Pagination: ReplyPagination{
+			Limit:      query.Limit,
+			HasMore:    hasMore,
+			NextCursor: nextCursor,
+		},
+		ParentID: commentID,
+	})
+}
+
 // EditComment godoc
 // @Summary Edit comment
-// @Description Edit own comment
+// @Description Edit own comment within the edit window; moderators hide or remove comments rather than edit them. The previous version is kept in the comment's history
 // @Tags comments
 // @Accept json
 // @Produce json
@@ -349,12 +600,20 @@ func (h *Handler) EditComment(c *gin.Context) {
 		return
 	}
 
-	// Only author can edit
+	// Only the author can edit, within the edit window
 	if comment.UserID != currentUser.ID {
 		response.Forbidden(c, "FORBIDDEN", "Cannot edit others' comments")
 		return
 	}
 
+	if h.config.CommentEditWindowMinutes > 0 {
+		window := time.Duration(h.config.CommentEditWindowMinutes) * time.Minute
+		if time.Since(comment.CreatedAt) > window {
+			response.Forbidden(c, "EDIT_WINDOW_EXPIRED", fmt.Sprintf("Comments can only be edited within %d minutes of posting", h.config.CommentEditWindowMinutes))
+			return
+		}
+	}
+
 	// Store old mentions for comparison
 	oldMentions := comment.Mentions
 
@@ -372,39 +631,155 @@ func (h *Handler) EditComment(c *gin.Context) {
 		}
 	}
 
-	// Update comment
-	err = h.repo.UpdateComment(c.Request.Context(), commentID, map[string]interface{}{
-		"content":  req.Content,
-		"mentions": newMentionIDs,
-		"isEdited": true,
-	})
-	if err != nil {
+	// Users who blocked the author cannot be mentioned
+	newMentionIDs = h.blockPolicy.FilterBlocked(c.Request.Context(), comment.UserID, newMentionIDs)
+
+	// Update comment, keeping the previous version in its history
+	if err := h.repo.EditComment(c.Request.Context(), comment, req.Content, newMentionIDs, currentUser.ID); err != nil {
 		response.InternalServerError(c, "UPDATE_FAILED", "Failed to update comment")
 		return
 	}
 
-	// Create notifications for NEW mentions only (async)
-	go func() {
-		commentData := &notifications.CommentData{
-			ID:       commentID,
-			AnchorID: comment.AnchorID,
-			Content:  req.Content,
-			Mentions: newMentionIDs,
+	// Edits go through the owner's keyword filter like new comments
+	published := comment.IsPublished()
+	if published {
+		anchor, err := h.anchorsRepo.GetAnchorByID(c.Request.Context(), comment.AnchorID)
+		if err == nil && !anchor.IsOwnedBy(currentUser.ID) && matchesKeywordFilter(req.Content, anchor.CommentFilterWords) {
+			if err := h.repo.SetCommentStatus(c.Request.Context(), commentID, StatusHeld); err == nil {
+				h.adjustCommentCounts(c.Request.Context(), comment, -1)
+				published = false
+			}
 		}
-		_ = h.notificationService.CreateEditCommentNotifications(context.Background(), commentData, oldMentions, currentUser)
-	}()
+	}
+
+	// Create notifications for NEW mentions only (async)
+	if published {
+		go func() {
+			commentData := &notifications.CommentData{
+				ID:       commentID,
+				AnchorID: comment.AnchorID,
+				ItemID:   comment.ItemID,
+				Content:  req.Content,
+				Mentions: newMentionIDs,
+			}
+			_ = h.notificationService.CreateEditCommentNotifications(context.Background(), commentData, oldMentions, currentUser)
+		}()
+	}
 
 	// Get updated comment
 	updatedComment, _ := h.repo.GetCommentByID(c.Request.Context(), commentID)
 
-	commentResponse := h.buildCommentResponse(updatedComment, currentUser, false)
+	commentResponse := h.buildCommentResponseWithAuthor(updatedComment, currentUser, false)
 
 	response.Success(c, commentResponse)
 }
 
+// GetCommentHistory godoc
+// @Summary Get comment edit history
+// @Description Get the current version of a comment and every earlier version, newest first
+// @Tags comments
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.APIResponse{data=CommentHistoryResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /comments/{id}/history [get]
+func (h *Handler) GetCommentHistory(c *gin.Context) {
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "Invalid comment ID")
+		return
+	}
+
+	comment, err := h.repo.GetCommentByID(c.Request.Context(), commentID)
+	if err != nil {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
+	anchor, err := h.anchorsRepo.GetAnchorByID(c.Request.Context(), comment.AnchorID)
+	if err != nil {
+		response.NotFound(c, "ANCHOR_NOT_FOUND", "Anchor not found")
+		return
+	}
+
+	var currentUserID *primitive.ObjectID
+	if usr, exists := c.Get("user"); exists {
+		if user, ok := usr.(*auth.User); ok {
+			currentUserID = &user.ID
+		}
+	}
+
+	if anchor.Visibility == anchors.VisibilityPrivate {
+		if currentUserID == nil || !anchor.IsOwnedBy(*currentUserID) {
+			response.Forbidden(c, "ACCESS_DENIED", "Cannot view comment on private anchor")
+			return
+		}
+	}
+
+	if currentUserID != nil && (h.blockPolicy.IsBlocked(c.Request.Context(), *currentUserID, anchor.UserID) ||
+		h.blockPolicy.IsBlocked(c.Request.Context(), *currentUserID, comment.UserID)) {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
+	if !canSeeComment(comment, anchor, currentUserID) {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
+	revisions, err := h.repo.GetRevisions(c.Request.Context(), commentID)
+	if err != nil {
+		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch comment history")
+		return
+	}
+
+	// Each revision records who replaced it, so a version's writer is the editor of the revision after it
+	writers := make([]primitive.ObjectID, len(revisions)+1)
+	writers[len(revisions)] = comment.UserID // The oldest version was posted by the author
+	for i, rev := range revisions {
+		writers[i] = rev.EditedBy
+	}
+
+	current := CommentRevisionResponse{
+		Content:   comment.Content,
+		Mentions:  comment.Mentions,
+		WrittenAt: comment.CreatedAt,
+		WrittenBy: writers[0],
+		ByAuthor:  writers[0] == comment.UserID,
+	}
+	if comment.EditedAt != nil {
+		current.WrittenAt = *comment.EditedAt
+	}
+	if current.Mentions == nil {
+		current.Mentions = []primitive.ObjectID{}
+	}
+
+	history := make([]CommentRevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		replacedAt := rev.ReplacedAt
+		history[i] = CommentRevisionResponse{
+			Content:    rev.Content,
+			Mentions:   rev.Mentions,
+			WrittenAt:  rev.WrittenAt,
+			WrittenBy:  writers[i+1],
+			ByAuthor:   writers[i+1] == comment.UserID,
+			ReplacedAt: &replacedAt,
+		}
+	}
+
+	response.Success(c, CommentHistoryResponse{
+		CommentID: comment.ID,
+		Current:   current,
+		Revisions: history,
+		EditCount: comment.EditCount,
+	})
+}
+
 // DeleteComment godoc
 // @Summary Delete comment
-// @Description Delete own comment or any comment on own anchor
+// @Description Delete own comment or any comment on own anchor. A comment with replies is kept as a "deleted" placeholder
 // @Tags comments
 // @Produce json
 // @Security BearerAuth
@@ -456,13 +831,10 @@ func (h *Handler) DeleteComment(c *gin.Context) {
 		return
 	}
 
-	// Decrement comment count
-	_ = h.anchorsRepo.IncrementCommentCount(c.Request.Context(), comment.AnchorID, -1)
-
-	// Update engagement score (async)
-	go func() {
-		_ = h.anchorsRepo.UpdateEngagementScore(context.Background(), comment.AnchorID)
-	}()
+	// Decrement comment counts (hidden and held comments are not counted)
+	if comment.IsPublished() {
+		h.adjustCommentCounts(c.Request.Context(), comment, -1)
+	}
 
 	response.Success(c, gin.H{"message": "Comment deleted successfully"})
 }
@@ -527,6 +899,17 @@ func (h *Handler) LikeComment(c *gin.Context) {
 		return
 	}
 
+	if req.Action == "like" && !comment.IsPublished() {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return
+	}
+
+	if req.Action == "like" && (h.blockPolicy.IsBlocked(c.Request.Context(), currentUser.ID, anchor.UserID) ||
+		h.blockPolicy.IsBlocked(c.Request.Context(), currentUser.ID, comment.UserID)) {
+		response.Forbidden(c, "BLOCKED", "You cannot like this comment")
+		return
+	}
+
 	var hasLiked bool
 
 	if req.Action == "like" {
@@ -618,15 +1001,23 @@ func (h *Handler) buildCommentResponse(comment *Comment, author *auth.User, hasL
 		profilePic = &author.ProfilePictureURL
 	}
 
-	return CommentResponse{
-		ID:        comment.ID,
-		AnchorID:  comment.AnchorID,
-		Content:   comment.Content,
-		Mentions:  comment.Mentions,
-		LikeCount: comment.LikeCount,
-		IsEdited:  comment.IsEdited,
-		CreatedAt: comment.CreatedAt,
-		UpdatedAt: comment.UpdatedAt,
+	resp := CommentResponse{
+		ID:         comment.ID,
+		AnchorID:   comment.AnchorID,
+		ItemID:     comment.ItemID,
+		Content:    comment.Content,
+		Mentions:   comment.Mentions,
+		ParentID:   comment.ParentID,
+		Depth:      comment.Depth,
+		ReplyCount: comment.ReplyCount,
+		LikeCount:  comment.LikeCount,
+		IsEdited:   comment.IsEdited,
+		EditCount:  comment.EditCount,
+		EditedAt:   comment.EditedAt,
+		Status:     statusOf(comment),
+		IsPinned:   comment.IsPinned,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
 		Author: CommentAuthor{
 			ID:             author.ID,
 			Username:       author.Username,
@@ -638,10 +1029,21 @@ func (h *Handler) buildCommentResponse(comment *Comment, author *auth.User, hasL
 			HasLiked: hasLiked,
 		},
 	}
+
+	// Placeholders keep their place in the thread but reveal nothing
+	if comment.IsDeleted() {
+		resp.Content = ""
+		resp.Mentions = []primitive.ObjectID{}
+		resp.IsDeleted = true
+	}
+
+	return resp
 }
 
 func (h *Handler) buildCommentResponseWithAuthor(comment *Comment, author *auth.User, hasLiked bool) CommentResponse {
-	if author == nil {
+	if comment.IsDeleted() {
+		author = &auth.User{Username: "deleted", DisplayName: "Deleted"}
+	} else if author == nil {
 		author = &auth.User{
 			ID:          comment.UserID,
 			Username:    "deleted",
@@ -690,3 +1092,13 @@ func (h *Handler) buildCommentListResponse(ctx context.Context, comments []Comme
 
 	return responses
 }
+
+// threadPosition places a reply under parent. Replies to the deepest level join the parent's
+// own thread so nesting never exceeds MaxReplyDepth.
+func threadPosition(parent *Comment) (*primitive.ObjectID, int) {
+	if parent.Depth < MaxReplyDepth || parent.ParentID == nil {
+		parentID := parent.ID
+		return &parentID, parent.Depth + 1
+	}
+	return parent.ParentID, parent.Depth
+}
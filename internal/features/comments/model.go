@@ -13,18 +13,69 @@ const (
 	SortTop    = "top"
 )
 
+// MaxReplyDepth is the deepest a reply can nest below a top-level comment (depth 0)
+const MaxReplyDepth = 2
+
+// Moderation status constants
+const (
+	StatusPublished = "published"
+	StatusHidden    = "hidden" // Hidden by the anchor owner
+	StatusHeld      = "held"   // Matched the anchor's keyword filter, awaiting owner review
+)
+
+// Comment moderation limits
+const (
+	MaxFilterKeywords = 50
+)
+
 // Comment represents a comment on an anchor
 type Comment struct {
-	ID        primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
-	AnchorID  primitive.ObjectID   `bson:"anchorId" json:"anchorId"`
-	UserID    primitive.ObjectID   `bson:"userId" json:"userId"`
-	Content   string               `bson:"content" json:"content"`
-	Mentions  []primitive.ObjectID `bson:"mentions" json:"mentions"`
-	LikeCount int                  `bson:"likeCount" json:"likeCount"`
-	IsEdited  bool                 `bson:"isEdited" json:"isEdited"`
-	CreatedAt time.Time            `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time            `bson:"updatedAt" json:"updatedAt"`
-	DeletedAt *time.Time           `bson:"deletedAt,omitempty" json:"-"`
+	ID                primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	AnchorID          primitive.ObjectID   `bson:"anchorId" json:"anchorId"`
+	ItemID            *primitive.ObjectID  `bson:"itemId,omitempty" json:"itemId,omitempty"` // Nil for comments on the anchor as a whole
+	UserID            primitive.ObjectID   `bson:"userId" json:"userId"`
+	Content           string               `bson:"content" json:"content"`
+	Mentions          []primitive.ObjectID `bson:"mentions" json:"mentions"`
+	ParentID          *primitive.ObjectID  `bson:"parentId,omitempty" json:"parentId,omitempty"` // Nil for top-level comments
+	Depth             int                  `bson:"depth" json:"depth"`                           // 0 for top-level, up to MaxReplyDepth
+	ReplyCount        int                  `bson:"replyCount" json:"replyCount"`                 // Direct published replies still visible in the thread
+	LikeCount         int                  `bson:"likeCount" json:"likeCount"`
+	IsEdited          bool                 `bson:"isEdited" json:"isEdited"`
+	EditCount         int                  `bson:"editCount" json:"editCount"`
+	EditedAt          *time.Time           `bson:"editedAt,omitempty" json:"editedAt,omitempty"` // When the current content was written by an edit
+	Status            string               `bson:"status,omitempty" json:"status"`               // Empty on comments created before moderation, treated as published
+	IsPinned          bool                 `bson:"isPinned" json:"isPinned"`                     // Pinned by the anchor owner, at most one per anchor
+	AuthorDeactivated bool                 `bson:"authorDeactivated,omitempty" json:"-"`         // Copied from the author's account, see auth.Repository.SyncContentVisibility
+	CreatedAt         time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt         time.Time            `bson:"updatedAt" json:"updatedAt"`
+	DeletedAt         *time.Time           `bson:"deletedAt,omitempty" json:"-"`
+}
+
+// IsDeleted reports whether the comment was deleted; deleted comments with replies remain as placeholders
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// IsPublished reports whether the comment is visible to everyone, i.e. neither hidden nor held
+func (c *Comment) IsPublished() bool {
+	return c.Status == "" || c.Status == StatusPublished
+}
+
+// ModerationScope decides which hidden and held comments a listing includes
+type ModerationScope struct {
+	ShowAll  bool                // The anchor owner sees every comment
+	ViewerID *primitive.ObjectID // Authors keep seeing their own hidden and held comments
+}
+
+// CommentRevision is a previous version of a comment, saved each time the comment is edited
+type CommentRevision struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	CommentID  primitive.ObjectID   `bson:"commentId" json:"commentId"`
+	Content    string               `bson:"content" json:"content"`
+	Mentions   []primitive.ObjectID `bson:"mentions" json:"mentions"`
+	WrittenAt  time.Time            `bson:"writtenAt" json:"writtenAt"`   // When this version was posted or edited in
+	ReplacedAt time.Time            `bson:"replacedAt" json:"replacedAt"` // When the next edit replaced it
+	EditedBy   primitive.ObjectID   `bson:"editedBy" json:"editedBy"`     // Who made the replacing edit
 }
 
 // CommentLike represents a like on a comment
@@ -38,13 +89,26 @@ type CommentLike struct {
 // Request DTOs
 
 type CreateCommentRequest struct {
-	Content string `json:"content" binding:"required,min=1,max=1000"`
+	Content  string `json:"content" binding:"required,min=1,max=1000"`
+	ParentID string `json:"parentId"` // Optional: comment being replied to
+	ItemID   string `json:"itemId"`   // Optional: item within the anchor being discussed
 }
 
 type UpdateCommentRequest struct {
 	Content string `json:"content" binding:"required,min=1,max=1000"`
 }
 
+type UpdateCommentSettingsRequest struct {
+	CommentsLocked *bool     `json:"commentsLocked"`
+	FilterKeywords *[]string `json:"filterKeywords"` // Replaces the whole list when present
+}
+
+type ModerationListQuery struct {
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=50"`
+	Status string `form:"status,default=held"`
+}
+
 type CommentLikeActionRequest struct {
 	Action string `json:"action" binding:"required,oneof=like unlike"`
 }
@@ -55,6 +119,17 @@ type CommentListQuery struct {
 	Sort  string `form:"sort,default=newest"`
 }
 
+type ReplyListQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// ReplyCursor represents the decoded reply cursor (replies are listed oldest first)
+type ReplyCursor struct {
+	Timestamp time.Time          `json:"t"`
+	CommentID primitive.ObjectID `json:"i"`
+}
+
 // Response DTOs
 
 type CommentAuthor struct {
@@ -72,10 +147,19 @@ type CommentEngagement struct {
 type CommentResponse struct {
 	ID         primitive.ObjectID   `json:"id"`
 	AnchorID   primitive.ObjectID   `json:"anchorId"`
+	ItemID     *primitive.ObjectID  `json:"itemId"`
 	Content    string               `json:"content"`
 	Mentions   []primitive.ObjectID `json:"mentions"`
+	ParentID   *primitive.ObjectID  `json:"parentId"`
+	Depth      int                  `json:"depth"`
+	ReplyCount int                  `json:"replyCount"`
 	LikeCount  int                  `json:"likeCount"`
 	IsEdited   bool                 `json:"isEdited"`
+	EditCount  int                  `json:"editCount"`
+	EditedAt   *time.Time           `json:"editedAt"`
+	IsDeleted  bool                 `json:"isDeleted"` // Placeholder kept so replies are not orphaned
+	Status     string               `json:"status"`
+	IsPinned   bool                 `json:"isPinned"`
 	CreatedAt  time.Time            `json:"createdAt"`
 	UpdatedAt  time.Time            `json:"updatedAt"`
 	Author     CommentAuthor        `json:"author"`
@@ -88,8 +172,9 @@ type CommentLikeResponse struct {
 }
 
 type CommentListMeta struct {
-	Sort     string             `json:"sort"`
-	AnchorID primitive.ObjectID `json:"anchorId"`
+	Sort     string              `json:"sort"`
+	AnchorID primitive.ObjectID  `json:"anchorId"`
+	ItemID   *primitive.ObjectID `json:"itemId,omitempty"`
 }
 
 type PaginatedCommentsResponse struct {
@@ -103,3 +188,43 @@ type PaginatedCommentsResponse struct {
 	} `json:"pagination"`
 	Meta CommentListMeta `json:"meta"`
 }
+
+type ReplyPagination struct {
+	Limit      int     `json:"limit"`
+	HasMore    bool    `json:"hasMore"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+type RepliesResponse struct {
+	Replies    []CommentResponse  `json:"replies"`
+	Pagination ReplyPagination    `json:"pagination"`
+	ParentID   primitive.ObjectID `json:"parentId"`
+}
+
+type CommentModerationResponse struct {
+	ID       primitive.ObjectID `json:"id"`
+	Status   string             `json:"status"`
+	IsPinned bool               `json:"isPinned"`
+}
+
+type CommentSettingsResponse struct {
+	AnchorID       primitive.ObjectID `json:"anchorId"`
+	CommentsLocked bool               `json:"commentsLocked"`
+	FilterKeywords []string           `json:"filterKeywords"`
+}
+
+type CommentRevisionResponse struct {
+	Content    string               `json:"content"`
+	Mentions   []primitive.ObjectID `json:"mentions"`
+	WrittenAt  time.Time            `json:"writtenAt"`
+	WrittenBy  primitive.ObjectID   `json:"writtenBy"`
+	ByAuthor   bool                 `json:"byAuthor"`   // False when a moderator wrote this version
+	ReplacedAt *time.Time           `json:"replacedAt"` // Nil for the current version
+}
+
+type CommentHistoryResponse struct {
+	CommentID primitive.ObjectID        `json:"commentId"`
+	Current   CommentRevisionResponse   `json:"current"`
+	Revisions []CommentRevisionResponse `json:"revisions"` // Newest first
+	EditCount int                       `json:"editCount"`
+}
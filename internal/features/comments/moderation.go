@@ -0,0 +1,461 @@
+package comments
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PinComment godoc
+// @Summary Pin comment
+// @Description Pin a top-level comment to the top of the anchor's discussion, replacing any pinned comment (anchor owner only)
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.APIResponse{data=CommentModerationResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /comments/{id}/pin [post]
+func (h *Handler) PinComment(c *gin.Context) {
+	comment, anchor, ok := h.moderatedComment(c)
+	if !ok {
+		return
+	}
+
+	if comment.ParentID != nil {
+		response.BadRequest(c, "NOT_TOP_LEVEL", "Only top-level comments can be pinned")
+		return
+	}
+	if !comment.IsPublished() {
+		response.BadRequest(c, "NOT_PUBLISHED", "Hidden or held comments cannot be pinned")
+		return
+	}
+
+	if err := h.repo.PinComment(c.Request.Context(), anchor.ID, comment.ID); err != nil {
+		response.InternalServerError(c, "PIN_FAILED", "Failed to pin comment")
+		return
+	}
+
+	response.Success(c, CommentModerationResponse{ID: comment.ID, Status: StatusPublished, IsPinned: true}, "Comment pinned")
+}
+
+// UnpinComment godoc
+// @Summary Unpin comment
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.APIResponse{data=CommentModerationResponse}
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /comments/{id}/pin [delete]
+func (h *Handler) UnpinComment(c *gin.Context) {
+	comment, _, ok := h.moderatedComment(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.UnpinComment(c.Request.Context(), comment.ID); err != nil {
+		response.InternalServerError(c, "UNPIN_FAILED", "Failed to unpin comment")
+		return
+	}
+
+	response.Success(c, CommentModerationResponse{ID: comment.ID, Status: statusOf(comment), IsPinned: false}, "Comment unpinned")
+}
+
+// HideComment godoc
+// @Summary Hide comment
+// @Description Hide a comment from everyone but its author without deleting it (anchor owner only)
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.APIResponse{data=CommentModerationResponse}
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /comments/{id}/hide [post]
+func (h *Handler) HideComment(c *gin.Context) {
+	comment, _, ok := h.moderatedComment(c)
+	if !ok {
+		return
+	}
+
+	if comment.Status != StatusHidden {
+		if err := h.repo.SetCommentStatus(c.Request.Context(), comment.ID, StatusHidden); err != nil {
+			response.InternalServerError(c, "HIDE_FAILED", "Failed to hide comment")
+			return
+		}
+		if comment.IsPublished() {
+			h.adjustCommentCounts(c.Request.Context(), comment, -1)
+		}
+	}
+
+	response.Success(c, CommentModerationResponse{ID: comment.ID, Status: StatusHidden}, "Comment hidden")
+}
+
+// UnhideComment godoc
+// @Summary Unhide comment
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.APIResponse{data=CommentModerationResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /comments/{id}/hide [delete]
+func (h *Handler) UnhideComment(c *gin.Context) {
+	comment, _, ok := h.moderatedComment(c)
+	if !ok {
+		return
+	}
+
+	if comment.Status != StatusHidden {
+		response.BadRequest(c, "NOT_HIDDEN", "Comment is not hidden")
+		return
+	}
+
+	if err := h.repo.SetCommentStatus(c.Request.Context(), comment.ID, StatusPublished); err != nil {
+		response.InternalServerError(c, "UNHIDE_FAILED", "Failed to unhide comment")
+		return
+	}
+	h.adjustCommentCounts(c.Request.Context(), comment, 1)
+
+	response.Success(c, CommentModerationResponse{ID: comment.ID, Status: StatusPublished}, "Comment visible again")
+}
+
+// ApproveComment godoc
+// @Summary Approve held comment
+// @Description Publish a comment held by the anchor's keyword filter and send its notifications (anchor owner only)
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} response.APIResponse{data=CommentModerationResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /comments/{id}/approve [post]
+func (h *Handler) ApproveComment(c *gin.Context) {
+	comment, anchor, ok := h.moderatedComment(c)
+	if !ok {
+		return
+	}
+
+	if comment.Status != StatusHeld {
+		response.BadRequest(c, "NOT_HELD", "Comment is not held for review")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repo.SetCommentStatus(ctx, comment.ID, StatusPublished); err != nil {
+		response.InternalServerError(c, "APPROVE_FAILED", "Failed to approve comment")
+		return
+	}
+	h.adjustCommentCounts(ctx, comment, 1)
+
+	// Notifications were held back with the comment
+	if author, err := h.authRepo.GetUserByObjectID(ctx, comment.UserID); err == nil {
+		var parentAuthorID *primitive.ObjectID
+		if comment.ParentID != nil {
+			if parent, err := h.repo.GetThreadComment(ctx, *comment.ParentID); err == nil {
+				parentAuthorID = &parent.UserID
+			}
+		}
+		go h.notifyNewComment(comment, anchor, parentAuthorID, author)
+	}
+
+	response.Success(c, CommentModerationResponse{ID: comment.ID, Status: StatusPublished}, "Comment approved")
+}
+
+// ListModerationQueue godoc
+// @Summary List held or hidden comments
+// @Description Review queue of an anchor's held (default) or hidden comments, oldest first (anchor owner only)
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Anchor ID"
+// @Param status query string false "held or hidden (default held)"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 20, max 50)"
+// @Success 200 {object} response.APIResponse{data=PaginatedCommentsResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /anchors/{id}/comments/moderation [get]
+func (h *Handler) ListModerationQueue(c *gin.Context) {
+	anchor, user, ok := h.moderatedAnchor(c)
+	if !ok {
+		return
+	}
+
+	var query ModerationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "INVALID_QUERY", "Invalid query parameters")
+		return
+	}
+	if err := ValidateModerationListQuery(&query); err != nil {
+		response.BadRequest(c, "INVALID_QUERY", err.Error())
+		return
+	}
+
+	comments, total, err := h.repo.GetCommentsByStatus(c.Request.Context(), anchor.ID, query.Status, query.Page, query.Limit)
+	if err != nil {
+		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch comments")
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(query.Limit)))
+
+	resp := PaginatedCommentsResponse{
+		Comments: h.buildCommentListResponse(c.Request.Context(), comments, &user.ID),
+		Meta: CommentListMeta{
+			Sort:     SortOldest,
+			AnchorID: anchor.ID,
+		},
+	}
+	resp.Pagination.Page = query.Page
+	resp.Pagination.Limit = query.Limit
+	resp.Pagination.Total = total
+	resp.Pagination.TotalPages = totalPages
+	resp.Pagination.HasMore = query.Page < totalPages
+
+	response.Success(c, resp)
+}
+
+// GetCommentSettings godoc
+// @Summary Get comment settings
+// @Description Get the comment lock and keyword filter of an anchor (anchor owner only)
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Anchor ID"
+// @Success 200 {object} response.APIResponse{data=CommentSettingsResponse}
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /anchors/{id}/comment-settings [get]
+func (h *Handler) GetCommentSettings(c *gin.Context) {
+	anchor, _, ok := h.moderatedAnchor(c)
+	if !ok {
+		return
+	}
+
+	response.Success(c, buildCommentSettingsResponse(anchor))
+}
+
+// UpdateCommentSettings godoc
+// @Summary Update comment settings
+// @Description Lock or unlock comments and replace the keyword filter that holds matching comments for review (anchor owner only)
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Anchor ID"
+// @Param request body UpdateCommentSettingsRequest true "Settings to change"
+// @Success 200 {object} response.APIResponse{data=CommentSettingsResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /anchors/{id}/comment-settings [patch]
+func (h *Handler) UpdateCommentSettings(c *gin.Context) {
+	anchor, _, ok := h.moderatedAnchor(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCommentSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "INVALID_REQUEST", "Invalid request format")
+		return
+	}
+	if err := ValidateUpdateCommentSettingsRequest(&req); err != nil {
+		response.BadRequest(c, "VALIDATION_FAILED", err.Error())
+		return
+	}
+
+	updates := bson.M{}
+	if req.CommentsLocked != nil {
+		updates["commentsLocked"] = *req.CommentsLocked
+		anchor.CommentsLocked = *req.CommentsLocked
+	}
+	if req.FilterKeywords != nil {
+		updates["commentFilterWords"] = *req.FilterKeywords
+		anchor.CommentFilterWords = *req.FilterKeywords
+	}
+
+	if err := h.anchorsRepo.UpdateAnchor(c.Request.Context(), anchor.ID, updates); err != nil {
+		response.InternalServerError(c, "UPDATE_FAILED", "Failed to update comment settings")
+		return
+	}
+
+	response.Success(c, buildCommentSettingsResponse(anchor), "Comment settings updated")
+}
+
+// Helper methods
+
+// moderatedComment loads the comment in the path and its anchor, requiring the current user to own the anchor
+func (h *Handler) moderatedComment(c *gin.Context) (*Comment, *anchors.Anchor, bool) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return nil, nil, false
+	}
+	currentUser := usr.(*auth.User)
+
+	commentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "Invalid comment ID")
+		return nil, nil, false
+	}
+
+	comment, err := h.repo.GetCommentByID(c.Request.Context(), commentID)
+	if err != nil {
+		response.NotFound(c, "COMMENT_NOT_FOUND", "Comment not found")
+		return nil, nil, false
+	}
+
+	anchor, err := h.anchorsRepo.GetAnchorByID(c.Request.Context(), comment.AnchorID)
+	if err != nil || anchor.DeletedAt != nil {
+		response.NotFound(c, "ANCHOR_NOT_FOUND", "Anchor not found")
+		return nil, nil, false
+	}
+
+	if !anchor.IsOwnedBy(currentUser.ID) {
+		response.Forbidden(c, "FORBIDDEN", "Only the anchor owner can moderate comments")
+		return nil, nil, false
+	}
+
+	return comment, anchor, true
+}
+
+// moderatedAnchor loads the anchor in the path, requiring the current user to own it
+func (h *Handler) moderatedAnchor(c *gin.Context) (*anchors.Anchor, *auth.User, bool) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return nil, nil, false
+	}
+	currentUser := usr.(*auth.User)
+
+	anchorID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "Invalid anchor ID")
+		return nil, nil, false
+	}
+
+	anchor, err := h.anchorsRepo.GetAnchorByID(c.Request.Context(), anchorID)
+	if err != nil || anchor.DeletedAt != nil {
+		response.NotFound(c, "ANCHOR_NOT_FOUND", "Anchor not found")
+		return nil, nil, false
+	}
+
+	if !anchor.IsOwnedBy(currentUser.ID) {
+		response.Forbidden(c, "FORBIDDEN", "Only the anchor owner can moderate comments")
+		return nil, nil, false
+	}
+
+	return anchor, currentUser, true
+}
+
+// adjustCommentCounts moves the anchor and item comment counts as comments enter or leave the public discussion
+func (h *Handler) adjustCommentCounts(ctx context.Context, comment *Comment, delta int) {
+	_ = h.anchorsRepo.IncrementCommentCount(ctx, comment.AnchorID, delta)
+	if comment.ItemID != nil {
+		_ = h.anchorsRepo.IncrementItemCommentCount(ctx, *comment.ItemID, delta)
+	}
+
+	// Update engagement score (async)
+	go func() {
+		_ = h.anchorsRepo.UpdateEngagementScore(context.Background(), comment.AnchorID)
+	}()
+}
+
+// notifyNewComment sends the mention, reply and comment notifications for a published comment
+func (h *Handler) notifyNewComment(comment *Comment, anchor *anchors.Anchor, parentAuthorID *primitive.ObjectID, actor *auth.User) {
+	commentData := &notifications.CommentData{
+		ID:             comment.ID,
+		AnchorID:       comment.AnchorID,
+		ItemID:         comment.ItemID,
+		Content:        comment.Content,
+		Mentions:       comment.Mentions,
+		ParentAuthorID: parentAuthorID,
+	}
+	_ = h.notificationService.CreateCommentNotifications(context.Background(), commentData, anchor.ID, anchor.UserID, actor)
+
+	if actor.ID != anchor.UserID {
+		_ = h.webhookService.Publish(context.Background(), anchor.UserID, webhooks.EventCommentCreated, webhooks.CommentCreatedData{
+			CommentID: comment.ID,
+			AnchorID:  comment.AnchorID,
+			ItemID:    comment.ItemID,
+			ParentID:  comment.ParentID,
+			Content:   comment.Content,
+			Actor:     webhooks.Actor{ID: actor.ID, Username: actor.Username},
+		})
+	}
+}
+
+// canSeeComment reports whether a viewer may see a comment: hidden and held comments are
+// only shown to the anchor owner and the comment's author
+func canSeeComment(comment *Comment, anchor *anchors.Anchor, viewerID *primitive.ObjectID) bool {
+	// Comments of deactivated accounts are hidden from everyone but their authors
+	if comment.AuthorDeactivated && !comment.IsDeleted() && (viewerID == nil || comment.UserID != *viewerID) {
+		return false
+	}
+	if comment.IsPublished() {
+		return true
+	}
+	return viewerID != nil && (anchor.IsOwnedBy(*viewerID) || comment.UserID == *viewerID)
+}
+
+// moderationScope lets the anchor owner see every comment and authors see their own
+func moderationScope(anchor *anchors.Anchor, viewerID *primitive.ObjectID) ModerationScope {
+	return ModerationScope{
+		ShowAll:  viewerID != nil && anchor.IsOwnedBy(*viewerID),
+		ViewerID: viewerID,
+	}
+}
+
+// matchesKeywordFilter reports whether content contains any of the anchor's filtered keywords
+func matchesKeywordFilter(content string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return false
+	}
+
+	lower := strings.ToLower(content)
+	for _, keyword := range keywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func statusOf(comment *Comment) string {
+	if comment.IsPublished() {
+		return StatusPublished
+	}
+	return comment.Status
+}
+
+func buildCommentSettingsResponse(anchor *anchors.Anchor) CommentSettingsResponse {
+	keywords := anchor.CommentFilterWords
+	if keywords == nil {
+		keywords = []string{}
+	}
+	return CommentSettingsResponse{
+		AnchorID:       anchor.ID,
+		CommentsLocked: anchor.CommentsLocked,
+		FilterKeywords: keywords,
+	}
+}
@@ -12,13 +12,15 @@ import (
 )
 
 type Repository struct {
-	commentsCollection     *mongo.Collection
-	commentLikesCollection *mongo.Collection
+	commentsCollection         *mongo.Collection
+	commentLikesCollection     *mongo.Collection
+	commentRevisionsCollection *mongo.Collection
 }
 
 func NewRepository(db *mongo.Database) *Repository {
 	commentsCollection := db.Collection("comments")
 	commentLikesCollection := db.Collection("commentLikes")
+	commentRevisionsCollection := db.Collection("commentRevisions")
 
 	// Create indexes
 	commentsCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
@@ -43,6 +45,22 @@ func NewRepository(db *mongo.Database) *Repository {
 				{Key: "createdAt", Value: -1},
 			},
 		},
+		// Comments on a single item
+		{
+			Keys: bson.D{
+				{Key: "itemId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
+		// Replies of a comment, oldest first
+		{
+			Keys: bson.D{
+				{Key: "parentId", Value: 1},
+				{Key: "createdAt", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+		},
 	})
 
 	commentLikesCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
@@ -55,21 +73,46 @@ func NewRepository(db *mongo.Database) *Repository {
 		},
 	})
 
+	// Edit history of a comment, newest first
+	commentRevisionsCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "commentId", Value: 1}, {Key: "replacedAt", Value: -1}},
+		},
+	})
+
 	return &Repository{
-		commentsCollection:     commentsCollection,
-		commentLikesCollection: commentLikesCollection,
+		commentsCollection:         commentsCollection,
+		commentLikesCollection:     commentLikesCollection,
+		commentRevisionsCollection: commentRevisionsCollection,
 	}
 }
 
-// CreateComment inserts a new comment
+// CreateComment inserts a new comment, bumping the parent's reply count for published replies
 func (r *Repository) CreateComment(ctx context.Context, comment *Comment) error {
 	comment.ID = primitive.NewObjectID()
 	comment.CreatedAt = time.Now()
 	comment.UpdatedAt = time.Now()
 	comment.LikeCount = 0
+	comment.ReplyCount = 0
 	comment.IsEdited = false
 
-	_, err := r.commentsCollection.InsertOne(ctx, comment)
+	if _, err := r.commentsCollection.InsertOne(ctx, comment); err != nil {
+		return err
+	}
+
+	if comment.ParentID != nil && comment.IsPublished() {
+		return r.addReply(ctx, *comment.ParentID)
+	}
+
+	return nil
+}
+
+// addReply increments a parent's reply count after one of its replies entered the thread
+func (r *Repository) addReply(ctx context.Context, parentID primitive.ObjectID) error {
+	_, err := r.commentsCollection.UpdateOne(ctx,
+		bson.M{"_id": parentID},
+		bson.M{"$inc": bson.M{"replyCount": 1}},
+	)
 	return err
 }
 
@@ -91,6 +134,24 @@ func (r *Repository) GetCommentByID(ctx context.Context, commentID primitive.Obj
 	return &comment, nil
 }
 
+// GetThreadComment retrieves a comment by ID, including deleted placeholders that still have replies
+func (r *Repository) GetThreadComment(ctx context.Context, commentID primitive.ObjectID) (*Comment, error) {
+	var comment Comment
+	err := r.commentsCollection.FindOne(ctx, bson.M{
+		"_id": commentID,
+		"$or": visibleInThread(),
+	}).Decode(&comment)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
 // UpdateComment updates a comment
 func (r *Repository) UpdateComment(ctx context.Context, commentID primitive.ObjectID, updates bson.M) error {
 	updates["updatedAt"] = time.Now()
@@ -112,41 +173,174 @@ func (r *Repository) UpdateComment(ctx context.Context, commentID primitive.Obje
 	return nil
 }
 
-// SoftDeleteComment soft deletes a comment
+// EditComment replaces a comment's content and mentions, saving the current version as a revision first
+func (r *Repository) EditComment(ctx context.Context, comment *Comment, content string, mentions []primitive.ObjectID, editorID primitive.ObjectID) error {
+	now := time.Now()
+
+	writtenAt := comment.CreatedAt
+	if comment.EditedAt != nil {
+		writtenAt = *comment.EditedAt
+	}
+
+	previousMentions := comment.Mentions
+	if previousMentions == nil {
+		previousMentions = []primitive.ObjectID{}
+	}
+
+	revision := CommentRevision{
+		ID:         primitive.NewObjectID(),
+		CommentID:  comment.ID,
+		Content:    comment.Content,
+		Mentions:   previousMentions,
+		WrittenAt:  writtenAt,
+		ReplacedAt: now,
+		EditedBy:   editorID,
+	}
+	if _, err := r.commentRevisionsCollection.InsertOne(ctx, revision); err != nil {
+		return err
+	}
+
+	result, err := r.commentsCollection.UpdateOne(ctx,
+		bson.M{"_id": comment.ID, "deletedAt": nil},
+		bson.M{
+			"$set": bson.M{
+				"content":   content,
+				"mentions":  mentions,
+				"isEdited":  true,
+				"editedAt":  now,
+				"updatedAt": now,
+			},
+			"$inc": bson.M{"editCount": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("comment not found")
+	}
+
+	return nil
+}
+
+// GetRevisions returns a comment's previous versions, newest first
+func (r *Repository) GetRevisions(ctx context.Context, commentID primitive.ObjectID) ([]CommentRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "replacedAt", Value: -1}})
+	cursor, err := r.commentRevisionsCollection.Find(ctx, bson.M{"commentId": commentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []CommentRevision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// SoftDeleteComment soft deletes a comment. A comment that still has replies stays in the
+// thread as a "deleted" placeholder; otherwise it is released from its parent's reply count.
 func (r *Repository) SoftDeleteComment(ctx context.Context, commentID primitive.ObjectID) error {
-	result, err := r.commentsCollection.UpdateOne(
+	var comment Comment
+	err := r.commentsCollection.FindOneAndUpdate(
 		ctx,
 		bson.M{"_id": commentID, "deletedAt": nil},
-		bson.M{"$set": bson.M{"deletedAt": time.Now(), "updatedAt": time.Now()}},
-	)
+		bson.M{"$set": bson.M{"deletedAt": time.Now(), "updatedAt": time.Now(), "isPinned": false}},
+	).Decode(&comment)
 
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("comment not found")
+		}
 		return err
 	}
 
-	if result.MatchedCount == 0 {
-		return errors.New("comment not found")
+	if comment.ParentID != nil && comment.ReplyCount == 0 && comment.IsPublished() {
+		return r.releaseReply(ctx, *comment.ParentID)
 	}
 
 	return nil
 }
 
-// GetCommentsByAnchor retrieves comments for an anchor with pagination
-func (r *Repository) GetCommentsByAnchor(ctx context.Context, anchorID primitive.ObjectID, sort string, page, limit int) ([]Comment, int64, error) {
+// releaseReply decrements a parent's reply count after one of its replies left the thread.
+// A deleted parent left without replies disappears as well, so the release walks up the thread.
+func (r *Repository) releaseReply(ctx context.Context, parentID primitive.ObjectID) error {
+	for {
+		var parent Comment
+		err := r.commentsCollection.FindOneAndUpdate(
+			ctx,
+			bson.M{"_id": parentID, "replyCount": bson.M{"$gt": 0}},
+			bson.M{"$inc": bson.M{"replyCount": -1}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&parent)
+
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil
+			}
+			return err
+		}
+
+		if !parent.IsDeleted() || parent.ReplyCount > 0 || parent.ParentID == nil || !parent.IsPublished() {
+			return nil
+		}
+		parentID = *parent.ParentID
+	}
+}
+
+// visibleInThread matches live comments and deleted placeholders that still hold replies
+func visibleInThread() bson.A {
+	return bson.A{
+		bson.M{"deletedAt": nil},
+		bson.M{"replyCount": bson.M{"$gt": 0}},
+	}
+}
+
+// activeAuthorFilter hides comments of deactivated accounts from everyone but their authors.
+// Deleted placeholders stay, so replies by others remain reachable.
+func activeAuthorFilter(viewerID *primitive.ObjectID) bson.M {
+	visible := bson.A{
+		bson.M{"authorDeactivated": bson.M{"$ne": true}},
+		bson.M{"deletedAt": bson.M{"$ne": nil}},
+	}
+	if viewerID != nil {
+		visible = append(visible, bson.M{"userId": *viewerID})
+	}
+	return bson.M{"$or": visible}
+}
+
+// GetCommentsByAnchor retrieves top-level comments for an anchor with pagination, skipping comments by excludeUserIDs.
+// When itemID is set only comments on that item are returned.
+// Hidden and held comments are only included as the moderation scope allows; the pinned comment comes first.
+func (r *Repository) GetCommentsByAnchor(ctx context.Context, anchorID primitive.ObjectID, itemID *primitive.ObjectID, sort string, page, limit int, excludeUserIDs []primitive.ObjectID, scope ModerationScope) ([]Comment, int64, error) {
 	filter := bson.M{
-		"anchorId":  anchorID,
-		"deletedAt": nil,
+		"anchorId": anchorID,
+		"parentId": nil,
+		"$or":      visibleInThread(),
+	}
+	if itemID != nil {
+		filter["itemId"] = *itemID
 	}
+	if len(excludeUserIDs) > 0 {
+		filter["userId"] = bson.M{"$nin": excludeUserIDs}
+	}
+	and := bson.A{activeAuthorFilter(scope.ViewerID)}
+	if moderation := moderationFilter(scope); moderation != nil {
+		and = append(and, moderation)
+	}
+	filter["$and"] = and
 
 	// Determine sort order
-	var sortOrder bson.D
+	sortOrder := bson.D{{Key: "isPinned", Value: -1}}
 	switch sort {
 	case SortOldest:
-		sortOrder = bson.D{{Key: "createdAt", Value: 1}}
+		sortOrder = append(sortOrder, bson.E{Key: "createdAt", Value: 1})
 	case SortTop:
-		sortOrder = bson.D{{Key: "likeCount", Value: -1}, {Key: "createdAt", Value: -1}}
+		sortOrder = append(sortOrder, bson.E{Key: "likeCount", Value: -1}, bson.E{Key: "createdAt", Value: -1})
 	default: // newest
-		sortOrder = bson.D{{Key: "createdAt", Value: -1}}
+		sortOrder = append(sortOrder, bson.E{Key: "createdAt", Value: -1})
 	}
 
 	opts := options.Find().
@@ -173,6 +367,149 @@ func (r *Repository) GetCommentsByAnchor(ctx context.Context, anchorID primitive
 	return comments, total, nil
 }
 
+// GetReplies retrieves direct replies of a comment oldest first, starting after the cursor
+func (r *Repository) GetReplies(ctx context.Context, parentID primitive.ObjectID, cursor *ReplyCursor, limit int, excludeUserIDs []primitive.ObjectID, scope ModerationScope) ([]Comment, error) {
+	conditions := bson.A{
+		bson.M{"parentId": parentID},
+		bson.M{"$or": visibleInThread()},
+		activeAuthorFilter(scope.ViewerID),
+	}
+	if len(excludeUserIDs) > 0 {
+		conditions = append(conditions, bson.M{"userId": bson.M{"$nin": excludeUserIDs}})
+	}
+	if moderation := moderationFilter(scope); moderation != nil {
+		conditions = append(conditions, moderation)
+	}
+	if cursor != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"createdAt": bson.M{"$gt": cursor.Timestamp}},
+			bson.M{"createdAt": cursor.Timestamp, "_id": bson.M{"$gt": cursor.CommentID}},
+		}})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cur, err := r.commentsCollection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	replies := []Comment{}
+	if err = cur.All(ctx, &replies); err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+// moderationFilter restricts a listing to published comments plus the viewer's own, or nil when everything is shown
+func moderationFilter(scope ModerationScope) bson.M {
+	if scope.ShowAll {
+		return nil
+	}
+
+	published := bson.M{"status": bson.M{"$nin": bson.A{StatusHidden, StatusHeld}}}
+	if scope.ViewerID == nil {
+		return published
+	}
+	return bson.M{"$or": bson.A{published, bson.M{"userId": *scope.ViewerID}}}
+}
+
+// GetCommentsByStatus retrieves an anchor's comments in a moderation status, oldest first (owner review queue)
+func (r *Repository) GetCommentsByStatus(ctx context.Context, anchorID primitive.ObjectID, status string, page, limit int) ([]Comment, int64, error) {
+	filter := bson.M{
+		"anchorId":  anchorID,
+		"status":    status,
+		"deletedAt": nil,
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.commentsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []Comment{}
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.commentsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// SetCommentStatus moves a comment to a moderation status; comments leaving published are unpinned.
+// Replies entering or leaving published move their parent's reply count with them.
+func (r *Repository) SetCommentStatus(ctx context.Context, commentID primitive.ObjectID, status string) error {
+	set := bson.M{"status": status, "updatedAt": time.Now()}
+	if status != StatusPublished {
+		set["isPinned"] = false
+	}
+
+	var previous Comment
+	err := r.commentsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": commentID, "deletedAt": nil},
+		bson.M{"$set": set},
+	).Decode(&previous)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("comment not found")
+		}
+		return err
+	}
+
+	if previous.ParentID == nil {
+		return nil
+	}
+
+	wasPublished := previous.IsPublished()
+	switch {
+	case status == StatusPublished && !wasPublished:
+		return r.addReply(ctx, *previous.ParentID)
+	case status != StatusPublished && wasPublished:
+		return r.releaseReply(ctx, *previous.ParentID)
+	}
+
+	return nil
+}
+
+// PinComment pins a comment, unpinning whichever comment was pinned on the anchor before
+func (r *Repository) PinComment(ctx context.Context, anchorID, commentID primitive.ObjectID) error {
+	_, err := r.commentsCollection.UpdateMany(ctx,
+		bson.M{"anchorId": anchorID, "isPinned": true, "_id": bson.M{"$ne": commentID}},
+		bson.M{"$set": bson.M{"isPinned": false}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.commentsCollection.UpdateOne(ctx,
+		bson.M{"_id": commentID},
+		bson.M{"$set": bson.M{"isPinned": true}},
+	)
+	return err
+}
+
+// UnpinComment removes the pin from a comment
+func (r *Repository) UnpinComment(ctx context.Context, commentID primitive.ObjectID) error {
+	_, err := r.commentsCollection.UpdateOne(ctx,
+		bson.M{"_id": commentID},
+		bson.M{"$set": bson.M{"isPinned": false}},
+	)
+	return err
+}
+
 // CreateCommentLike creates a like (idempotent)
 func (r *Repository) CreateCommentLike(ctx context.Context, commentID, userID primitive.ObjectID) error {
 	like := CommentLike{
@@ -261,3 +598,150 @@ func (r *Repository) IncrementCommentLikeCount(ctx context.Context, commentID pr
 
 	return nil
 }
+
+// GetLikedCommentIDs returns the IDs of every comment a user has liked
+func (r *Repository) GetLikedCommentIDs(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cursor, err := r.commentLikesCollection.Find(ctx, bson.M{"userId": userID}, options.Find().SetProjection(bson.M{"commentId": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var likes []CommentLike
+	if err = cursor.All(ctx, &likes); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(likes))
+	for i, like := range likes {
+		ids[i] = like.CommentID
+	}
+
+	return ids, nil
+}
+
+// DeleteCommentLikesByUser removes every comment like made by a user
+func (r *Repository) DeleteCommentLikesByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.commentLikesCollection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
+
+// AnonymizeUserComments detaches a user's comments from their account so they
+// render as "Deleted User", and drops the user from other comments' mentions
+func (r *Repository) AnonymizeUserComments(ctx context.Context, userID primitive.ObjectID) error {
+	// The comments stay visible as "Deleted User", so they no longer follow the account's state
+	_, err := r.commentsCollection.UpdateMany(ctx,
+		bson.M{"userId": userID},
+		bson.M{
+			"$set":   bson.M{"userId": primitive.NilObjectID, "mentions": []primitive.ObjectID{}},
+			"$unset": bson.M{"authorDeactivated": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.commentsCollection.UpdateMany(ctx,
+		bson.M{"mentions": userID},
+		bson.M{"$pull": bson.M{"mentions": userID}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Edit history keeps no trace of the user either
+	_, err = r.commentRevisionsCollection.UpdateMany(ctx,
+		bson.M{"editedBy": userID},
+		bson.M{"$set": bson.M{"editedBy": primitive.NilObjectID}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.commentRevisionsCollection.UpdateMany(ctx,
+		bson.M{"mentions": userID},
+		bson.M{"$pull": bson.M{"mentions": userID}},
+	)
+	return err
+}
+
+// DeleteByAnchors permanently removes all comments (and their likes and edit history) on the given anchors
+func (r *Repository) DeleteByAnchors(ctx context.Context, anchorIDs []primitive.ObjectID) error {
+	if len(anchorIDs) == 0 {
+		return nil
+	}
+
+	filter := bson.M{"anchorId": bson.M{"$in": anchorIDs}}
+	cursor, err := r.commentsCollection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var commentIDs []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err == nil {
+			commentIDs = append(commentIDs, doc.ID)
+		}
+	}
+
+	if len(commentIDs) > 0 {
+		if _, err := r.commentLikesCollection.DeleteMany(ctx, bson.M{"commentId": bson.M{"$in": commentIDs}}); err != nil {
+			return err
+		}
+		if _, err := r.commentRevisionsCollection.DeleteMany(ctx, bson.M{"commentId": bson.M{"$in": commentIDs}}); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.commentsCollection.DeleteMany(ctx, filter)
+	return err
+}
+
+// RecountCommentLikes resets a comment's like count from the likes collection
+func (r *Repository) RecountCommentLikes(ctx context.Context, commentID primitive.ObjectID) error {
+	count, err := r.commentLikesCollection.CountDocuments(ctx, bson.M{"commentId": commentID})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.commentsCollection.UpdateOne(ctx,
+		bson.M{"_id": commentID},
+		bson.M{"$set": bson.M{"likeCount": count}},
+	)
+	return err
+}
+
+// GetAllByUser returns every comment written by a user, oldest first (for data export)
+func (r *Repository) GetAllByUser(ctx context.Context, userID primitive.ObjectID) ([]Comment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.commentsCollection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []Comment{}
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// GetCommentLikesByUser returns every comment like made by a user (for data export)
+func (r *Repository) GetCommentLikesByUser(ctx context.Context, userID primitive.ObjectID) ([]CommentLike, error) {
+	cursor, err := r.commentLikesCollection.Find(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	likes := []CommentLike{}
+	if err = cursor.All(ctx, &likes); err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
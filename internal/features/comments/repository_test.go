@@ -0,0 +1,48 @@
+package comments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/pkg/testdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReplyCountTracksPublishedReplies(t *testing.T) {
+	repo := NewRepository(testdb.New(t))
+	ctx := context.Background()
+
+	anchorID := primitive.NewObjectID()
+	create := func(parentID *primitive.ObjectID, status string) *Comment {
+		comment := &Comment{
+			AnchorID: anchorID,
+			UserID:   primitive.NewObjectID(),
+			Content:  "Nice",
+			Mentions: []primitive.ObjectID{},
+			ParentID: parentID,
+			Status:   status,
+		}
+		require.NoError(t, repo.CreateComment(ctx, comment))
+		return comment
+	}
+	replyCount := func(id primitive.ObjectID) int {
+		comment, err := repo.GetCommentByID(ctx, id)
+		require.NoError(t, err)
+		return comment.ReplyCount
+	}
+
+	parent := create(nil, StatusPublished)
+	create(&parent.ID, StatusPublished)
+	held := create(&parent.ID, StatusHeld)
+	require.Equal(t, 1, replyCount(parent.ID), "held replies are not counted")
+
+	require.NoError(t, repo.SetCommentStatus(ctx, held.ID, StatusPublished))
+	require.Equal(t, 2, replyCount(parent.ID), "approving counts the reply")
+
+	require.NoError(t, repo.SetCommentStatus(ctx, held.ID, StatusHidden))
+	require.Equal(t, 1, replyCount(parent.ID), "hiding releases the reply")
+
+	require.NoError(t, repo.SoftDeleteComment(ctx, held.ID))
+	require.Equal(t, 1, replyCount(parent.ID), "deleting a hidden reply leaves the count alone")
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/features/anchors"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -20,7 +22,7 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	notificationService := notifications.GetService(db)
 
 	// Initialize handler with notification service
-	handler := NewHandler(repo, authRepo, anchorsRepo, notificationService, cfg)
+	handler := NewHandler(repo, authRepo, anchorsRepo, notificationService, webhooks.NewService(db, cfg), safety.NewBlockPolicy(db), cfg)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
@@ -31,14 +33,31 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	{
 		anchorComments.POST("", authMiddleware, handler.AddComment)
 		anchorComments.GET("", optionalAuth, handler.ListComments)
+		anchorComments.GET("/moderation", authMiddleware, handler.ListModerationQueue)
 	}
 
+	// Anchor owner comment settings
+	router.GET("/anchors/:id/comment-settings", authMiddleware, handler.GetCommentSettings)
+	router.PATCH("/anchors/:id/comment-settings", authMiddleware, handler.UpdateCommentSettings)
+
+	// Item comment routes
+	router.GET("/anchors/:id/items/:itemId/comments", optionalAuth, handler.ListItemComments)
+
 	// Direct comment routes
 	comments := router.Group("/comments")
 	{
 		comments.GET("/:id", optionalAuth, handler.GetComment)
 		comments.PATCH("/:id", authMiddleware, handler.EditComment)
 		comments.DELETE("/:id", authMiddleware, handler.DeleteComment)
+		comments.GET("/:id/replies", optionalAuth, handler.ListReplies)
+		comments.GET("/:id/history", optionalAuth, handler.GetCommentHistory)
+
+		// Anchor owner moderation
+		comments.POST("/:id/pin", authMiddleware, handler.PinComment)
+		comments.DELETE("/:id/pin", authMiddleware, handler.UnpinComment)
+		comments.POST("/:id/hide", authMiddleware, handler.HideComment)
+		comments.DELETE("/:id/hide", authMiddleware, handler.UnhideComment)
+		comments.POST("/:id/approve", authMiddleware, handler.ApproveComment)
 		comments.POST("/:id/like", authMiddleware, handler.LikeComment)
 		comments.GET("/:id/like/status", authMiddleware, handler.GetCommentLikeStatus)
 	}
@@ -7,6 +7,8 @@ import (
 
 func ValidateCreateCommentRequest(req *CreateCommentRequest) error {
 	req.Content = strings.TrimSpace(req.Content)
+	req.ParentID = strings.TrimSpace(req.ParentID)
+	req.ItemID = strings.TrimSpace(req.ItemID)
 
 	if req.Content == "" {
 		return errors.New("content is required")
@@ -62,3 +64,70 @@ func ValidateCommentListQuery(query *CommentListQuery) error {
 
 	return nil
 }
+
+func ValidateReplyListQuery(query *ReplyListQuery) error {
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+
+	query.Cursor = strings.TrimSpace(query.Cursor)
+
+	return nil
+}
+
+func ValidateUpdateCommentSettingsRequest(req *UpdateCommentSettingsRequest) error {
+	if req.CommentsLocked == nil && req.FilterKeywords == nil {
+		return errors.New("nothing to update")
+	}
+
+	if req.FilterKeywords == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	keywords := make([]string, 0, len(*req.FilterKeywords))
+	for _, k := range *req.FilterKeywords {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" || seen[k] {
+			continue
+		}
+		if len(k) < 2 || len(k) > 50 {
+			return errors.New("filter keywords must be between 2 and 50 characters")
+		}
+		seen[k] = true
+		keywords = append(keywords, k)
+	}
+
+	if len(keywords) > MaxFilterKeywords {
+		return errors.New("you can filter up to 50 keywords")
+	}
+
+	*req.FilterKeywords = keywords
+	return nil
+}
+
+func ValidateModerationListQuery(query *ModerationListQuery) error {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+
+	if query.Limit < 1 {
+		query.Limit = 20
+	}
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+
+	if query.Status == "" {
+		query.Status = StatusHeld
+	}
+
+	if query.Status != StatusHeld && query.Status != StatusHidden {
+		return errors.New("status must be: held or hidden")
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package data_export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/features/anchor_follows"
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/comments"
+	"github.com/xyz-asif/gotodo/internal/features/follows"
+	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/lists"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/tag_follows"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Builder gathers a user's data from every feature and writes it as a zip archive
+type Builder struct {
+	authRepo          *auth.Repository
+	anchorsRepo       *anchors.Repository
+	commentsRepo      *comments.Repository
+	likesRepo         *likes.Repository
+	followsRepo       *follows.Repository
+	anchorFollowsRepo *anchor_follows.Repository
+	tagFollowsRepo    *tag_follows.Repository
+	listsRepo         *lists.Repository
+	mutesRepo         *mutes.Repository
+	notificationsRepo *notifications.Repository
+	safetyRepo        *safety.Repository
+	webhooksRepo      *webhooks.Repository
+}
+
+// NewBuilder creates an archive builder
+func NewBuilder(db *mongo.Database) *Builder {
+	return &Builder{
+		authRepo:          auth.NewRepository(db),
+		anchorsRepo:       anchors.NewRepository(db),
+		commentsRepo:      comments.NewRepository(db),
+		likesRepo:         likes.NewRepository(db),
+		followsRepo:       follows.NewRepository(db),
+		anchorFollowsRepo: anchor_follows.NewRepository(db),
+		tagFollowsRepo:    tag_follows.NewRepository(db),
+		listsRepo:         lists.NewRepository(db),
+		mutesRepo:         mutes.NewRepository(db),
+		notificationsRepo: notifications.NewRepository(db),
+		safetyRepo:        safety.NewRepository(db),
+		webhooksRepo:      webhooks.NewRepository(db),
+	}
+}
+
+// archiveFile is one JSON document in the archive
+type archiveFile struct {
+	name string
+	data interface{}
+}
+
+// Build writes the user's export archive to w
+func (b *Builder) Build(ctx context.Context, userID primitive.ObjectID, w io.Writer) error {
+	user, err := b.authRepo.GetUserByObjectID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Anchors with their items; uploaded files are collected as media links
+	userAnchors, err := b.anchorsRepo.GetAllUserAnchors(ctx, userID)
+	if err != nil {
+		return err
+	}
+	media := profileMedia(user)
+	anchorDocs := make([]anchorExport, 0, len(userAnchors))
+	for _, anchor := range userAnchors {
+		items, err := b.anchorsRepo.GetAnchorItems(ctx, anchor.ID)
+		if err != nil {
+			return err
+		}
+		if items == nil {
+			items = []anchors.Item{}
+		}
+		anchorDocs = append(anchorDocs, anchorExport{Anchor: anchor, Items: items})
+		media = append(media, itemMedia(items)...)
+	}
+
+	userComments, err := b.commentsRepo.GetAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	anchorLikes, err := b.likesRepo.GetAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	commentLikes, err := b.commentsRepo.GetCommentLikesByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	followGraph, err := b.buildFollows(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	anchorFollows, err := b.anchorFollowsRepo.GetAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tagFollows, err := b.tagFollowsRepo.GetFollowedTags(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	userLists, err := b.listsRepo.GetUserLists(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+	listDocs := make([]listExport, 0, len(userLists))
+	for _, list := range userLists {
+		members, err := b.userRefs(ctx, list.MemberIDs, nil)
+		if err != nil {
+			return err
+		}
+		listDocs = append(listDocs, listExport{List: list, Members: members})
+	}
+
+	userNotifications, err := b.notificationsRepo.GetAllForRecipient(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	devices, err := b.notificationsRepo.GetUserDevices(ctx, userID)
+	if err != nil {
+		return err
+	}
+	settings, err := b.notificationsRepo.GetNotificationSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	webhookDocs, err := b.buildWebhooks(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	reports, err := b.safetyRepo.GetReportsByReporter(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := b.userRefs(ctx, user.BlockedUsers, nil)
+	if err != nil {
+		return err
+	}
+
+	userMutes, err := b.mutesRepo.GetActiveMutes(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := b.authRepo.ListActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	tokens, err := b.authRepo.ListPersonalAccessTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if tokens == nil {
+		tokens = []auth.PersonalAccessToken{}
+	}
+
+	files := []archiveFile{
+		{"profile.json", user},
+		{"anchors.json", anchorDocs},
+		{"comments.json", userComments},
+		{"likes.json", likesExport{Anchors: anchorLikes, Comments: commentLikes}},
+		{"follows.json", followGraph},
+		{"anchor_follows.json", anchorFollows},
+		{"tag_follows.json", tagFollows},
+		{"lists.json", listDocs},
+		{"notifications.json", userNotifications},
+		{"notification_settings.json", notificationsExport{Devices: devices, Settings: settings}},
+		{"webhooks.json", webhookDocs},
+		{"reports.json", reports},
+		{"blocked_users.json", blocked},
+		{"mutes.json", userMutes},
+		{"sessions.json", sessionsExport{Sessions: sessions, PersonalAccessTokens: tokens}},
+		{"media.json", media},
+	}
+
+	manifest := exportManifest{UserID: userID, GeneratedAt: time.Now()}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range append([]archiveFile{{"manifest.json", manifest}}, files...) {
+		if err := writeJSON(zw, f.name, f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// buildFollows resolves both directions of the user's follow graph
+func (b *Builder) buildFollows(ctx context.Context, userID primitive.ObjectID) (followsExport, error) {
+	following, err := b.followsRepo.GetAllFollowing(ctx, userID)
+	if err != nil {
+		return followsExport{}, err
+	}
+	followers, err := b.followsRepo.GetAllFollowers(ctx, userID)
+	if err != nil {
+		return followsExport{}, err
+	}
+
+	followingIDs := make([]primitive.ObjectID, len(following))
+	followingSince := make(map[primitive.ObjectID]time.Time, len(following))
+	for i, f := range following {
+		followingIDs[i] = f.FollowingID
+		followingSince[f.FollowingID] = f.CreatedAt
+	}
+	followerIDs := make([]primitive.ObjectID, len(followers))
+	followerSince := make(map[primitive.ObjectID]time.Time, len(followers))
+	for i, f := range followers {
+		followerIDs[i] = f.FollowerID
+		followerSince[f.FollowerID] = f.CreatedAt
+	}
+
+	result := followsExport{}
+	if result.Following, err = b.userRefs(ctx, followingIDs, followingSince); err != nil {
+		return followsExport{}, err
+	}
+	if result.Followers, err = b.userRefs(ctx, followerIDs, followerSince); err != nil {
+		return followsExport{}, err
+	}
+	return result, nil
+}
+
+// buildWebhooks collects the user's webhooks with their delivery logs
+func (b *Builder) buildWebhooks(ctx context.Context, userID primitive.ObjectID) ([]webhookExport, error) {
+	userWebhooks, err := b.webhooksRepo.GetUserWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]webhookExport, 0, len(userWebhooks))
+	for _, webhook := range userWebhooks {
+		deliveries, err := b.webhooksRepo.GetAllDeliveries(ctx, webhook.ID)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, webhookExport{Webhook: webhook, Deliveries: deliveries})
+	}
+	return docs, nil
+}
+
+// userRefs resolves usernames for a list of user IDs, keeping their order
+func (b *Builder) userRefs(ctx context.Context, ids []primitive.ObjectID, since map[primitive.ObjectID]time.Time) ([]userRef, error) {
+	refs := make([]userRef, 0, len(ids))
+	if len(ids) == 0 {
+		return refs, nil
+	}
+
+	users, err := b.authRepo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	usernames := make(map[primitive.ObjectID]string, len(users))
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+	}
+
+	for _, id := range ids {
+		ref := userRef{UserID: id, Username: usernames[id]}
+		if t, ok := since[id]; ok {
+			t := t
+			ref.Since = &t
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// profileMedia lists the user's profile picture and cover image
+func profileMedia(user *auth.User) []mediaLink {
+	links := []mediaLink{}
+	if user.ProfilePictureURL != "" {
+		links = append(links, mediaLink{Kind: "profile_picture", URL: user.ProfilePictureURL})
+	}
+	if user.CoverImageURL != "" {
+		links = append(links, mediaLink{Kind: "cover_image", URL: user.CoverImageURL})
+	}
+	return links
+}
+
+// itemMedia lists files uploaded as anchor items
+func itemMedia(items []anchors.Item) []mediaLink {
+	var links []mediaLink
+	for i := range items {
+		item := &items[i]
+		link := mediaLink{AnchorID: &item.AnchorID, ItemID: &item.ID}
+		switch {
+		case item.ImageData != nil && item.ImageData.CloudinaryURL != "":
+			link.Kind, link.URL = "item_image", item.ImageData.CloudinaryURL
+		case item.AudioData != nil && item.AudioData.CloudinaryURL != "":
+			link.Kind, link.URL = "item_audio", item.AudioData.CloudinaryURL
+		case item.FileData != nil && item.FileData.CloudinaryURL != "":
+			link.Kind, link.URL = "item_file", item.FileData.CloudinaryURL
+		default:
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// writeJSON adds an indented JSON document to the archive
+func writeJSON(zw *zip.Writer, name string, data interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
@@ -0,0 +1,157 @@
+package data_export
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RequestExport godoc
+// @Summary Request a data export
+// @Description Start assembling a zip archive of all the user's data. Returns the job in progress if there is one. One export per 24 hours.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} response.APIResponse{data=ExportJobResponse}
+// @Failure 401 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
+// @Router /users/me/export [post]
+func (h *Handler) RequestExport(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*auth.User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	job, created, err := h.service.RequestExport(c.Request.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, ErrExportTooSoon) {
+			response.Error(c, http.StatusTooManyRequests, "You can request one export every 24 hours", "EXPORT_RATE_LIMITED")
+			return
+		}
+		response.InternalServerError(c, "Failed to start export", "DATABASE_ERROR")
+		return
+	}
+
+	message := "Export started"
+	if !created {
+		message = "Export already in progress"
+	}
+	response.Respond(c, http.StatusAccepted, true, message, h.buildResponse(job))
+}
+
+// GetExportStatus godoc
+// @Summary Get data export status
+// @Description Get the status of the latest data export. Once ready, includes a short-lived signed download link.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=ExportJobResponse}
+// @Failure 401 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /users/me/export [get]
+func (h *Handler) GetExportStatus(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return
+	}
+	user, ok := val.(*auth.User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	job, err := h.service.GetLatestJob(c.Request.Context(), user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch export", "DATABASE_ERROR")
+		return
+	}
+	if job == nil {
+		response.NotFound(c, "No export has been requested", "EXPORT_NOT_FOUND")
+		return
+	}
+
+	response.Success(c, h.buildResponse(job))
+}
+
+// DownloadExport godoc
+// @Summary Download a data export
+// @Description Download the export archive using the signed link from GET /users/me/export
+// @Tags users
+// @Produce application/zip
+// @Param id path string true "Export ID"
+// @Param expires query int true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /exports/{id}/download [get]
+func (h *Handler) DownloadExport(c *gin.Context) {
+	jobID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid export ID", "INVALID_ID")
+		return
+	}
+
+	var query DownloadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "Missing download signature", "VALIDATION_FAILED")
+		return
+	}
+
+	if !h.service.VerifyDownload(jobID, query.Expires, query.Signature) {
+		response.Forbidden(c, "Download link is invalid or has expired", "INVALID_DOWNLOAD_LINK")
+		return
+	}
+
+	job, err := h.service.GetJob(c.Request.Context(), jobID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch export", "DATABASE_ERROR")
+		return
+	}
+	if job == nil || !job.IsDownloadable(time.Now()) {
+		response.NotFound(c, "Export not available", "EXPORT_NOT_FOUND")
+		return
+	}
+
+	c.FileAttachment(h.service.FilePath(job.FileName), "anchor-data-export-"+job.CreatedAt.Format("2006-01-02")+".zip")
+}
+
+func (h *Handler) buildResponse(job *ExportJob) ExportJobResponse {
+	resp := ExportJobResponse{
+		ID:          job.ID,
+		Status:      job.Status,
+		FileSize:    job.FileSize,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+
+	if job.IsDownloadable(time.Now()) {
+		link, expiresAt := h.service.DownloadLink(job)
+		resp.DownloadURL = &link
+		resp.DownloadExpiresAt = &expiresAt
+	}
+
+	return resp
+}
@@ -0,0 +1,138 @@
+package data_export
+
+import (
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/comments"
+	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/lists"
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Export job status constants
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusReady      = "ready"
+	StatusFailed     = "failed"
+	StatusExpired    = "expired" // Archive removed after the retention period
+)
+
+// MinExportInterval limits how often a user can request a new export
+const MinExportInterval = 24 * time.Hour
+
+// ExportJob tracks the assembly of one data export archive
+type ExportJob struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Status      string             `bson:"status" json:"status"`
+	FileName    string             `bson:"fileName,omitempty" json:"-"`
+	FileSize    int64              `bson:"fileSize,omitempty" json:"fileSize,omitempty"`
+	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	CompletedAt *time.Time         `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"` // When the archive is deleted
+}
+
+// IsActive reports whether the job is still being assembled
+func (j *ExportJob) IsActive() bool {
+	return j.Status == StatusPending || j.Status == StatusProcessing
+}
+
+// IsDownloadable reports whether the archive exists and has not expired
+func (j *ExportJob) IsDownloadable(now time.Time) bool {
+	return j.Status == StatusReady && j.ExpiresAt != nil && now.Before(*j.ExpiresAt)
+}
+
+// DownloadQuery for GET /exports/:id/download
+type DownloadQuery struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+// Response DTOs
+
+// ExportJobResponse is returned by the export endpoints
+type ExportJobResponse struct {
+	ID                primitive.ObjectID `json:"id"`
+	Status            string             `json:"status"`
+	FileSize          int64              `json:"fileSize,omitempty"`
+	Error             string             `json:"error,omitempty"`
+	CreatedAt         time.Time          `json:"createdAt"`
+	CompletedAt       *time.Time         `json:"completedAt,omitempty"`
+	ExpiresAt         *time.Time         `json:"expiresAt,omitempty"`
+	DownloadURL       *string            `json:"downloadUrl,omitempty"`       // Present once ready; signed and short-lived
+	DownloadExpiresAt *time.Time         `json:"downloadExpiresAt,omitempty"` // When downloadUrl stops working
+}
+
+// Archive contents
+
+// exportManifest describes the archive (manifest.json)
+type exportManifest struct {
+	UserID      primitive.ObjectID `json:"userId"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Files       []string           `json:"files"`
+}
+
+// anchorExport is an anchor with its items (anchors.json)
+type anchorExport struct {
+	Anchor anchors.Anchor `json:"anchor"`
+	Items  []anchors.Item `json:"items"`
+}
+
+// likesExport holds the user's likes (likes.json)
+type likesExport struct {
+	Anchors  []likes.Like           `json:"anchors"`
+	Comments []comments.CommentLike `json:"comments"`
+}
+
+// userRef identifies another user in the export
+type userRef struct {
+	UserID   primitive.ObjectID `json:"userId"`
+	Username string             `json:"username,omitempty"` // Empty if the account no longer exists
+	Since    *time.Time         `json:"since,omitempty"`
+}
+
+// followsExport lists follow relationships (follows.json)
+type followsExport struct {
+	Following []userRef `json:"following"`
+	Followers []userRef `json:"followers"`
+}
+
+// listExport is a user list with its members (lists.json)
+type listExport struct {
+	List    lists.List `json:"list"`
+	Members []userRef  `json:"members"`
+}
+
+// sessionsExport lists active sign-ins and API tokens (sessions.json)
+type sessionsExport struct {
+	Sessions             []auth.RefreshTokenSession `json:"sessions"`
+	PersonalAccessTokens []auth.PersonalAccessToken `json:"personalAccessTokens"`
+}
+
+// notificationsExport holds the user's push devices and notification preferences
+// (notification_settings.json). Settings is nil if the user never changed the defaults.
+type notificationsExport struct {
+	Devices  []notifications.Device              `json:"devices"`
+	Settings *notifications.NotificationSettings `json:"settings"`
+}
+
+// webhookExport is a webhook with its delivery log (webhooks.json)
+type webhookExport struct {
+	Webhook    webhooks.Webhook    `json:"webhook"`
+	Deliveries []webhooks.Delivery `json:"deliveries"`
+}
+
+// mediaLink points at an uploaded file (media.json)
+type mediaLink struct {
+	Kind     string              `json:"kind"` // profile_picture, cover_image, item_image, item_audio, item_file
+	URL      string              `json:"url"`
+	AnchorID *primitive.ObjectID `json:"anchorId,omitempty"`
+	ItemID   *primitive.ObjectID `json:"itemId,omitempty"`
+}
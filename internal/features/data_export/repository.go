@@ -0,0 +1,185 @@
+package data_export
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository handles database interactions for data export jobs
+type Repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates repository and ensures indexes
+func NewRepository(db *mongo.Database) *Repository {
+	collection := db.Collection("data_exports")
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// Latest export of a user
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			// Cleanup of expired archives
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "expiresAt", Value: 1}},
+		},
+	})
+
+	return &Repository{
+		collection: collection,
+	}
+}
+
+// CreateJob inserts a pending export job
+func (r *Repository) CreateJob(ctx context.Context, userID primitive.ObjectID) (*ExportJob, error) {
+	now := time.Now()
+	job := &ExportJob{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if _, err := r.collection.InsertOne(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetJobByID retrieves an export job, nil if it does not exist
+func (r *Repository) GetJobByID(ctx context.Context, jobID primitive.ObjectID) (*ExportJob, error) {
+	var job ExportJob
+	err := r.collection.FindOne(ctx, bson.M{"_id": jobID}).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetLatestJob retrieves the user's most recent export job, nil if there is none
+func (r *Repository) GetLatestJob(ctx context.Context, userID primitive.ObjectID) (*ExportJob, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	var job ExportJob
+	err := r.collection.FindOne(ctx, bson.M{"userId": userID}, opts).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// MarkProcessing flags the job as being assembled
+func (r *Repository) MarkProcessing(ctx context.Context, jobID primitive.ObjectID) error {
+	return r.setFields(ctx, jobID, bson.M{"status": StatusProcessing})
+}
+
+// MarkReady records the finished archive and when it will be removed. Returns
+// mongo.ErrNoDocuments if the job was deleted while the archive was being built.
+func (r *Repository) MarkReady(ctx context.Context, jobID primitive.ObjectID, fileName string, fileSize int64, expiresAt time.Time) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": jobID}, bson.M{"$set": bson.M{
+		"status":      StatusReady,
+		"fileName":    fileName,
+		"fileSize":    fileSize,
+		"completedAt": time.Now(),
+		"expiresAt":   expiresAt,
+		"updatedAt":   time.Now(),
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// MarkFailed records why the export could not be assembled
+func (r *Repository) MarkFailed(ctx context.Context, jobID primitive.ObjectID, reason string) error {
+	return r.setFields(ctx, jobID, bson.M{
+		"status":      StatusFailed,
+		"error":       reason,
+		"completedAt": time.Now(),
+	})
+}
+
+// MarkExpired flags the job's archive as removed
+func (r *Repository) MarkExpired(ctx context.Context, jobID primitive.ObjectID) error {
+	return r.setFields(ctx, jobID, bson.M{"status": StatusExpired})
+}
+
+// GetExpiredJobs returns ready jobs whose retention period has passed
+func (r *Repository) GetExpiredJobs(ctx context.Context, now time.Time) ([]ExportJob, error) {
+	filter := bson.M{
+		"status":    StatusReady,
+		"expiresAt": bson.M{"$lte": now},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []ExportJob{}
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// FailInterruptedJobs fails jobs left unfinished by a previous process, so their users can retry
+func (r *Repository) FailInterruptedJobs(ctx context.Context, startedBefore time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{
+			"status":    bson.M{"$in": []string{StatusPending, StatusProcessing}},
+			"createdAt": bson.M{"$lt": startedBefore},
+		},
+		bson.M{"$set": bson.M{
+			"status":      StatusFailed,
+			"error":       "export was interrupted, please request a new one",
+			"completedAt": time.Now(),
+			"updatedAt":   time.Now(),
+		}},
+	)
+	return err
+}
+
+// GetAllJobs returns every export job of a user
+func (r *Repository) GetAllJobs(ctx context.Context, userID primitive.ObjectID) ([]ExportJob, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []ExportJob{}
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// DeleteAllForUser removes every export job of a user
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
+
+func (r *Repository) setFields(ctx context.Context, jobID primitive.ObjectID, fields bson.M) error {
+	fields["updatedAt"] = time.Now()
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": jobID}, bson.M{"$set": fields})
+	return err
+}
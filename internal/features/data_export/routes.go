@@ -0,0 +1,27 @@
+package data_export
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RegisterRoutes registers the data export routes
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+	authRepo := auth.NewRepository(db)
+	handler := NewHandler(NewService(db, cfg))
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+
+	// Request and poll exports - session auth only
+	me := router.Group("/users/me")
+	me.Use(authMiddleware)
+	{
+		me.POST("/export", handler.RequestExport)
+		me.GET("/export", handler.GetExportStatus)
+	}
+
+	// Download is authorised by the signed link, so it works from a plain browser request
+	router.GET("/exports/:id/download", handler.DownloadExport)
+}
@@ -0,0 +1,235 @@
+package data_export
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/config"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// cleanupInterval is how often expired archives are removed
+const cleanupInterval = time.Hour
+
+// ErrExportTooSoon is returned when the user requested an export within MinExportInterval
+var ErrExportTooSoon = errors.New("an export was already generated recently")
+
+// Service runs data export jobs and signs their download links
+type Service struct {
+	repo      *Repository
+	builder   *Builder
+	config    *config.Config
+	startedAt time.Time // Jobs older than this were left behind by a previous process
+}
+
+// NewService creates the data export service
+func NewService(db *mongo.Database, cfg *config.Config) *Service {
+	return &Service{
+		repo:      NewRepository(db),
+		builder:   NewBuilder(db),
+		config:    cfg,
+		startedAt: time.Now(),
+	}
+}
+
+// RequestExport starts a new export for the user, or returns the one still in progress.
+// The boolean reports whether a new job was created.
+func (s *Service) RequestExport(ctx context.Context, userID primitive.ObjectID) (*ExportJob, bool, error) {
+	latest, err := s.repo.GetLatestJob(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if latest != nil {
+		if latest.IsActive() {
+			return latest, false, nil
+		}
+		if latest.Status != StatusFailed && time.Since(latest.CreatedAt) < MinExportInterval {
+			return latest, false, ErrExportTooSoon
+		}
+	}
+
+	job, err := s.repo.CreateJob(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Assemble in the background; the request returns immediately
+	go s.process(context.Background(), job)
+
+	return job, true, nil
+}
+
+// GetLatestJob returns the user's most recent export, nil if none
+func (s *Service) GetLatestJob(ctx context.Context, userID primitive.ObjectID) (*ExportJob, error) {
+	return s.repo.GetLatestJob(ctx, userID)
+}
+
+// GetJob returns an export job by ID, nil if it does not exist
+func (s *Service) GetJob(ctx context.Context, jobID primitive.ObjectID) (*ExportJob, error) {
+	return s.repo.GetJobByID(ctx, jobID)
+}
+
+// process assembles the archive for a job
+func (s *Service) process(ctx context.Context, job *ExportJob) {
+	_ = s.repo.MarkProcessing(ctx, job.ID)
+
+	fileName := archiveName(job.ID)
+	size, err := s.writeArchive(ctx, job.UserID, fileName)
+	if err != nil {
+		log.Printf("Data export %s for user %s failed: %v", job.ID.Hex(), job.UserID.Hex(), err)
+		_ = s.repo.MarkFailed(ctx, job.ID, "failed to assemble export")
+		return
+	}
+
+	expiresAt := time.Now().Add(s.retention())
+	if err := s.repo.MarkReady(ctx, job.ID, fileName, size, expiresAt); err != nil {
+		_ = os.Remove(s.FilePath(fileName))
+	}
+}
+
+// writeArchive builds the archive into a temporary file and moves it into place once complete
+func (s *Service) writeArchive(ctx context.Context, userID primitive.ObjectID, fileName string) (int64, error) {
+	if err := os.MkdirAll(s.config.ExportDir, 0o700); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(s.config.ExportDir, "export-*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.builder.Build(ctx, userID, tmp); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), s.FilePath(fileName)); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// archiveName is the file name of a job's archive
+func archiveName(jobID primitive.ObjectID) string {
+	return jobID.Hex() + ".zip"
+}
+
+// DeleteAllForUser removes a user's export jobs and their archives, including any
+// archive still being built
+func (s *Service) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	jobs, err := s.repo.GetAllJobs(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if err := os.Remove(s.FilePath(archiveName(job.ID))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return s.repo.DeleteAllForUser(ctx, userID)
+}
+
+// FilePath returns where an archive is stored
+func (s *Service) FilePath(fileName string) string {
+	return filepath.Join(s.config.ExportDir, filepath.Base(fileName))
+}
+
+// DownloadLink returns a signed download path for a ready job and when it expires
+func (s *Service) DownloadLink(job *ExportJob) (string, time.Time) {
+	expiresAt := time.Now().Add(s.linkExpiry())
+	if job.ExpiresAt != nil && job.ExpiresAt.Before(expiresAt) {
+		expiresAt = *job.ExpiresAt
+	}
+
+	link := fmt.Sprintf("/api/v1/exports/%s/download?expires=%d&signature=%s",
+		job.ID.Hex(), expiresAt.Unix(), s.sign(job.ID, expiresAt.Unix()))
+	return link, expiresAt
+}
+
+// VerifyDownload checks a download link's signature and expiry
+func (s *Service) VerifyDownload(jobID primitive.ObjectID, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	expected := s.sign(jobID, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// sign computes the link signature with a key derived from the JWT secret
+func (s *Service) sign(jobID primitive.ObjectID, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.JWTSecret+":data_export"))
+	mac.Write([]byte(jobID.Hex() + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run removes expired archives periodically until ctx is cancelled.
+// Jobs left unfinished by a previous process are failed first.
+func (s *Service) Run(ctx context.Context) {
+	if err := s.repo.FailInterruptedJobs(ctx, s.startedAt); err != nil {
+		log.Printf("Data export: failed to reset interrupted jobs: %v", err)
+	}
+
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanupExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanupExpired deletes archives past their retention period
+func (s *Service) cleanupExpired(ctx context.Context) {
+	jobs, err := s.repo.GetExpiredJobs(ctx, time.Now())
+	if err != nil {
+		log.Printf("Data export: failed to list expired exports: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if err := os.Remove(s.FilePath(job.FileName)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Data export: failed to remove %s: %v", job.FileName, err)
+			continue
+		}
+		_ = s.repo.MarkExpired(ctx, job.ID)
+	}
+}
+
+func (s *Service) retention() time.Duration {
+	if s.config.ExportRetentionHours <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(s.config.ExportRetentionHours) * time.Hour
+}
+
+func (s *Service) linkExpiry() time.Duration {
+	if s.config.ExportLinkExpiryMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(s.config.ExportLinkExpiryMinutes) * time.Minute
+}
@@ -0,0 +1,83 @@
+package digest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ConfirmUnsubscribe godoc
+// @Summary Confirm unsubscribing from the email digest
+// @Description Page opened by the link in a digest email. It only asks for confirmation, so link scanners and prefetchers do not unsubscribe the user; the form posts to the same signed link.
+// @Tags notifications
+// @Produce html
+// @Param user query string true "User ID"
+// @Param signature query string true "Link signature"
+// @Success 200 {string} string "confirmation page"
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /digest/unsubscribe [get]
+func (h *Handler) ConfirmUnsubscribe(c *gin.Context) {
+	userID, ok := h.verifyLink(c)
+	if !ok {
+		return
+	}
+
+	page, err := RenderUnsubscribePage(h.service.UnsubscribeURL(userID), h.service.SettingsURL())
+	if err != nil {
+		response.InternalServerError(c, "Failed to render page", "RENDER_FAILED")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe from the email digest
+// @Description Turn off the email digest using the signed link from a digest email. Also accepts one-click POSTs from mail clients (RFC 8058).
+// @Tags notifications
+// @Produce json
+// @Param user query string true "User ID"
+// @Param signature query string true "Link signature"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Router /digest/unsubscribe [post]
+func (h *Handler) Unsubscribe(c *gin.Context) {
+	userID, ok := h.verifyLink(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unsubscribe(c.Request.Context(), userID); err != nil {
+		response.InternalServerError(c, "Failed to unsubscribe", "UNSUBSCRIBE_FAILED")
+		return
+	}
+
+	response.Success(c, nil, "You will no longer receive digest emails")
+}
+
+// verifyLink checks the signed unsubscribe link and writes the error response if it is invalid
+func (h *Handler) verifyLink(c *gin.Context) (primitive.ObjectID, bool) {
+	userID, err := primitive.ObjectIDFromHex(c.Query("user"))
+	if err != nil {
+		response.BadRequest(c, "Invalid unsubscribe link", "INVALID_LINK")
+		return primitive.NilObjectID, false
+	}
+
+	if !h.service.VerifyUnsubscribe(userID, c.Query("signature")) {
+		response.Forbidden(c, "Invalid unsubscribe link", "INVALID_SIGNATURE")
+		return primitive.NilObjectID, false
+	}
+	return userID, true
+}
@@ -0,0 +1,76 @@
+package digest
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Digest section limits
+const (
+	MaxDigestNotifications = 10
+	MaxDigestUpdates       = 5
+	MaxDigestTrending      = 5
+)
+
+// State records when a user's digest was last considered and last sent
+type State struct {
+	UserID     primitive.ObjectID `bson:"_id" json:"userId"`
+	LastRunAt  time.Time          `bson:"lastRunAt" json:"lastRunAt"`
+	LastSentAt *time.Time         `bson:"lastSentAt,omitempty" json:"lastSentAt,omitempty"`
+}
+
+// Recipient is the slice of a user the digest job needs
+type Recipient struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Email       string             `bson:"email"`
+	Username    string             `bson:"username"`
+	DisplayName string             `bson:"displayName"`
+	Interests   []string           `bson:"interests"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+}
+
+// Digest is the rendered content of one email
+type Digest struct {
+	Name        string // Greeting name
+	Frequency   string // notifications.DigestDaily or DigestWeekly
+	Since       time.Time
+	UnreadCount int64
+	Unread      []DigestNotification
+	Updates     []DigestAnchorUpdate
+	Trending    []DigestAnchor
+
+	NotificationsURL string
+	SettingsURL      string
+	UnsubscribeURL   string
+}
+
+// IsEmpty reports whether there is nothing worth emailing
+func (d *Digest) IsEmpty() bool {
+	return d.UnreadCount == 0 && len(d.Updates) == 0 && len(d.Trending) == 0
+}
+
+// MoreUnread is how many unread notifications the email does not list
+func (d *Digest) MoreUnread() int64 {
+	return d.UnreadCount - int64(len(d.Unread))
+}
+
+type DigestNotification struct {
+	Headline string
+	Preview  string
+	URL      string
+}
+
+// DigestAnchorUpdate is a followed anchor that gained items since the user last opened it
+type DigestAnchorUpdate struct {
+	Title    string
+	NewItems int
+	URL      string
+}
+
+type DigestAnchor struct {
+	Title     string
+	LikeCount int
+	Tags      []string
+	URL       string
+}
@@ -0,0 +1,65 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strconv"
+	texttemplate "text/template"
+
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var (
+	htmlTemplate        = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/digest.html"))
+	unsubscribeTemplate = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/unsubscribe.html"))
+	textTemplate        = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/digest.txt"))
+)
+
+// Render produces the subject and the HTML and plain-text bodies of a digest
+func Render(d *Digest) (subject, html, text string, err error) {
+	var htmlBuf, textBuf bytes.Buffer
+	if err = htmlTemplate.Execute(&htmlBuf, d); err != nil {
+		return "", "", "", err
+	}
+	if err = textTemplate.Execute(&textBuf, d); err != nil {
+		return "", "", "", err
+	}
+	return subjectFor(d), htmlBuf.String(), textBuf.String(), nil
+}
+
+// unsubscribePage is the data of the unsubscribe confirmation page
+type unsubscribePage struct {
+	ActionURL   string // Signed unsubscribe link the form posts to
+	SettingsURL string
+}
+
+// RenderUnsubscribePage produces the page that asks the user to confirm unsubscribing
+func RenderUnsubscribePage(actionURL, settingsURL string) (string, error) {
+	var buf bytes.Buffer
+	if err := unsubscribeTemplate.Execute(&buf, unsubscribePage{ActionURL: actionURL, SettingsURL: settingsURL}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func subjectFor(d *Digest) string {
+	period := "week"
+	if d.Frequency == notifications.DigestDaily {
+		period = "day"
+	}
+
+	switch {
+	case d.UnreadCount == 1:
+		return "You have 1 unread notification"
+	case d.UnreadCount > 1:
+		return "You have " + strconv.FormatInt(d.UnreadCount, 10) + " unread notifications"
+	case len(d.Updates) > 0:
+		return "New items in anchors you follow"
+	default:
+		return "Trending this " + period + " in your interests"
+	}
+}
@@ -0,0 +1,58 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
+)
+
+func TestRender(t *testing.T) {
+	d := &Digest{
+		Name:        "Sam",
+		Frequency:   notifications.DigestWeekly,
+		Since:       time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		UnreadCount: 12,
+		Unread: []DigestNotification{
+			{Headline: "Alice and 3 others liked your anchor", Preview: "<b>Hikes</b>", URL: "https://app.example.com/anchors/1"},
+		},
+		Updates: []DigestAnchorUpdate{
+			{Title: "Reading list", NewItems: 1, URL: "https://app.example.com/anchors/2"},
+		},
+		NotificationsURL: "https://app.example.com/notifications",
+		SettingsURL:      "https://app.example.com/settings/notifications",
+		UnsubscribeURL:   "https://api.example.com/api/v1/digest/unsubscribe?user=u&signature=s",
+	}
+
+	subject, html, text, err := Render(d)
+	require.NoError(t, err)
+	require.Equal(t, "You have 12 unread notifications", subject)
+
+	require.Contains(t, html, "12 unread notifications")
+	require.Contains(t, html, "&lt;b&gt;Hikes&lt;/b&gt;", "previews are escaped")
+	require.Contains(t, html, "and 11 more")
+	require.Contains(t, html, "1 new item<")
+	require.Contains(t, html, "signature=s")
+
+	require.Contains(t, text, "Hi Sam, here is what happened since Mar 2.")
+	require.Contains(t, text, "- Alice and 3 others liked your anchor")
+	require.Contains(t, text, "And 11 more: https://app.example.com/notifications")
+	require.Contains(t, text, "Unsubscribe: https://api.example.com/api/v1/digest/unsubscribe?user=u&signature=s")
+	require.NotContains(t, text, "TRENDING")
+}
+
+func TestDigestIsEmpty(t *testing.T) {
+	require.True(t, (&Digest{}).IsEmpty())
+	require.False(t, (&Digest{Trending: []DigestAnchor{{Title: "x"}}}).IsEmpty())
+}
+
+func TestRenderUnsubscribePage(t *testing.T) {
+	page, err := RenderUnsubscribePage(
+		"https://api.example.com/api/v1/digest/unsubscribe?user=u&signature=s",
+		"https://app.example.com/settings/notifications",
+	)
+	require.NoError(t, err)
+	require.Contains(t, page, `<form method="post" action="https://api.example.com/api/v1/digest/unsubscribe?user=u&amp;signature=s">`)
+	require.Contains(t, page, `href="https://app.example.com/settings/notifications"`)
+}
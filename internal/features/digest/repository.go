@@ -0,0 +1,133 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	states *mongo.Collection
+	users  *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{
+		states: db.Collection("digestStates"), // Keyed by user ID
+		users:  db.Collection("users"),
+	}
+}
+
+// GetRecipients returns up to limit active users with an email address, in ID order after the given ID
+func (r *Repository) GetRecipients(ctx context.Context, afterID primitive.ObjectID, limit int) ([]Recipient, error) {
+	filter := bson.M{
+		"_id":           bson.M{"$gt": afterID},
+		"email":         bson.M{"$nin": []interface{}{"", nil}},
+		"suspendedAt":   nil,
+		"deactivatedAt": nil,
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{
+			"email":       1,
+			"username":    1,
+			"displayName": 1,
+			"interests":   1,
+			"createdAt":   1,
+		})
+
+	cursor, err := r.users.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	recipients := []Recipient{}
+	if err = cursor.All(ctx, &recipients); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// GetStates returns the digest state of each given user that has one
+func (r *Repository) GetStates(ctx context.Context, userIDs []primitive.ObjectID) (map[primitive.ObjectID]State, error) {
+	result := make(map[primitive.ObjectID]State)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	cursor, err := r.states.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var states []State
+	if err = cursor.All(ctx, &states); err != nil {
+		return nil, err
+	}
+	for _, s := range states {
+		result[s.UserID] = s
+	}
+	return result, nil
+}
+
+// ClaimRun atomically moves a user's lastRunAt from previous (zero if the user has no state
+// yet) to at. It returns false if another run changed it first, so each digest is sent once.
+func (r *Repository) ClaimRun(ctx context.Context, userID primitive.ObjectID, previous, at time.Time) (bool, error) {
+	filter := bson.M{"_id": userID, "lastRunAt": previous}
+	if previous.IsZero() {
+		filter["lastRunAt"] = bson.M{"$exists": false}
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(previous.IsZero())
+
+	err := r.states.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"lastRunAt": at}}, opts).Err()
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		// An upsert that inserted the state returns no previous document
+		return previous.IsZero(), nil
+	case mongo.IsDuplicateKeyError(err):
+		// Another run inserted the state first
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// ReleaseRun restores the lastRunAt a claim replaced, unless the state changed since
+func (r *Repository) ReleaseRun(ctx context.Context, userID primitive.ObjectID, previous, claimedAt time.Time) error {
+	update := bson.M{"$set": bson.M{"lastRunAt": previous}}
+	if previous.IsZero() {
+		update = bson.M{"$unset": bson.M{"lastRunAt": ""}}
+	}
+	_, err := r.states.UpdateOne(ctx, bson.M{"_id": userID, "lastRunAt": claimedAt}, update)
+	return err
+}
+
+// MarkRun records that a user's digest was considered at the given time, and sent if sent is true
+func (r *Repository) MarkRun(ctx context.Context, userID primitive.ObjectID, at time.Time, sent bool) error {
+	set := bson.M{"lastRunAt": at}
+	if sent {
+		set["lastSentAt"] = at
+	}
+	_, err := r.states.UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": set},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// DeleteState removes a user's digest state
+func (r *Repository) DeleteState(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.states.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
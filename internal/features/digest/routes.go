@@ -0,0 +1,19 @@
+package digest
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+	service := NewService(db, cfg, NewMailer(cfg))
+	handler := NewHandler(service)
+
+	// Links in digest emails; the signature stands in for authentication
+	digest := router.Group("/digest")
+	{
+		digest.GET("/unsubscribe", handler.ConfirmUnsubscribe)
+		digest.POST("/unsubscribe", handler.Unsubscribe)
+	}
+}
@@ -0,0 +1,418 @@
+package digest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/anchor_follows"
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/pkg/mailer"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// recipientBatchSize is how many users are loaded at a time while looking for due digests
+const recipientBatchSize = 200
+
+// Service builds and sends email digests
+type Service struct {
+	repo              *Repository
+	notificationsRepo *notifications.Repository
+	anchorsRepo       *anchors.Repository
+	anchorFollowsRepo *anchor_follows.Repository
+	authRepo          *auth.Repository
+	blockPolicy       *safety.BlockPolicy
+	mailer            mailer.Mailer
+	config            *config.Config
+}
+
+// NewService creates the digest service
+func NewService(db *mongo.Database, cfg *config.Config, m mailer.Mailer) *Service {
+	return &Service{
+		repo:              NewRepository(db),
+		notificationsRepo: notifications.NewRepository(db),
+		anchorsRepo:       anchors.NewRepository(db),
+		anchorFollowsRepo: anchor_follows.NewRepository(db),
+		authRepo:          auth.NewRepository(db),
+		blockPolicy:       safety.NewBlockPolicy(db),
+		mailer:            m,
+		config:            cfg,
+	}
+}
+
+// NewMailer returns the mailer selected by MAIL_DRIVER
+func NewMailer(cfg *config.Config) mailer.Mailer {
+	if cfg.MailDriver == "smtp" {
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+	return mailer.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+}
+
+// Run sends due digests periodically until ctx is cancelled
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval())
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce goes through every user and sends the digests that are due
+func (s *Service) RunOnce(ctx context.Context) {
+	afterID := primitive.NilObjectID
+	for ctx.Err() == nil {
+		recipients, err := s.repo.GetRecipients(ctx, afterID, recipientBatchSize)
+		if err != nil {
+			log.Printf("Digest: failed to list users: %v", err)
+			return
+		}
+		if len(recipients) == 0 {
+			return
+		}
+		afterID = recipients[len(recipients)-1].ID
+
+		userIDs := make([]primitive.ObjectID, len(recipients))
+		for i, r := range recipients {
+			userIDs[i] = r.ID
+		}
+		states, err := s.repo.GetStates(ctx, userIDs)
+		if err != nil {
+			log.Printf("Digest: failed to load digest state: %v", err)
+			return
+		}
+		settings, err := s.notificationsRepo.GetNotificationSettingsForUsers(ctx, userIDs)
+		if err != nil {
+			log.Printf("Digest: failed to load notification settings: %v", err)
+			return
+		}
+
+		for i := range recipients {
+			r := &recipients[i]
+			userSettings := settings[r.ID]
+			if userSettings == nil {
+				userSettings = notifications.DefaultNotificationSettings(r.ID)
+			}
+			s.processRecipient(ctx, r, userSettings, states[r.ID])
+		}
+	}
+}
+
+// processRecipient sends one user's digest if it is due and has anything to say
+func (s *Service) processRecipient(ctx context.Context, r *Recipient, settings *notifications.NotificationSettings, state State) {
+	frequency := settings.DigestFrequency()
+	period, ok := periodOf(frequency)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	last := state.LastRunAt
+	if last.IsZero() {
+		last = r.CreatedAt
+	}
+	if now.Sub(last) < period {
+		return
+	}
+
+	// Cover the period, or less if a digest went out more recently
+	since := now.Add(-period)
+	if state.LastSentAt != nil && state.LastSentAt.After(since) {
+		since = *state.LastSentAt
+	}
+
+	// Another instance running at the same time may have loaded the same state; only the one
+	// that moves lastRunAt on sends the digest
+	claimed, err := s.repo.ClaimRun(ctx, r.ID, state.LastRunAt, now)
+	if err != nil {
+		log.Printf("Digest: failed to claim digest for user %s: %v", r.ID.Hex(), err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	d, err := s.Build(ctx, r, settings, since)
+	if err != nil {
+		log.Printf("Digest: failed to build digest for user %s: %v", r.ID.Hex(), err)
+		s.releaseRun(ctx, r.ID, state.LastRunAt, now)
+		return
+	}
+
+	sent := false
+	if !d.IsEmpty() {
+		if err := s.send(ctx, r, d); err != nil {
+			// Released, so the next run retries
+			log.Printf("Digest: failed to send digest to user %s: %v", r.ID.Hex(), err)
+			s.releaseRun(ctx, r.ID, state.LastRunAt, now)
+			return
+		}
+		sent = true
+	}
+
+	if err := s.repo.MarkRun(ctx, r.ID, now, sent); err != nil {
+		log.Printf("Digest: failed to record digest for user %s: %v", r.ID.Hex(), err)
+	}
+}
+
+// releaseRun gives up a claimed run so the next run retries it
+func (s *Service) releaseRun(ctx context.Context, userID primitive.ObjectID, previous, claimedAt time.Time) {
+	if err := s.repo.ReleaseRun(ctx, userID, previous, claimedAt); err != nil {
+		log.Printf("Digest: failed to release digest for user %s: %v", userID.Hex(), err)
+	}
+}
+
+// Build gathers a user's digest content since the given time
+func (s *Service) Build(ctx context.Context, r *Recipient, settings *notifications.NotificationSettings, since time.Time) (*Digest, error) {
+	d := &Digest{
+		Name:             r.DisplayName,
+		Frequency:        settings.DigestFrequency(),
+		Since:            since,
+		NotificationsURL: s.frontendURL("/notifications"),
+		SettingsURL:      s.SettingsURL(),
+		UnsubscribeURL:   s.UnsubscribeURL(r.ID),
+	}
+	if d.Name == "" {
+		d.Name = r.Username
+	}
+
+	if err := s.addUnread(ctx, d, r, settings, since); err != nil {
+		return nil, err
+	}
+	if err := s.addFollowedUpdates(ctx, d, r); err != nil {
+		return nil, err
+	}
+	if err := s.addTrending(ctx, d, r, since); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// addUnread lists unread notifications of the types the user wants by email
+func (s *Service) addUnread(ctx context.Context, d *Digest, r *Recipient, settings *notifications.NotificationSettings, since time.Time) error {
+	var types []string
+	for _, t := range notifications.NotificationTypes {
+		if settings.Channels(t).Email {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+
+	unread, total, err := s.notificationsRepo.GetUnreadSince(ctx, r.ID, types, since, MaxDigestNotifications)
+	if err != nil {
+		return err
+	}
+	d.UnreadCount = total
+	if len(unread) == 0 {
+		return nil
+	}
+
+	actorIDs := make([]primitive.ObjectID, 0, len(unread))
+	for _, n := range unread {
+		actorIDs = append(actorIDs, n.ActorID)
+	}
+	names := make(map[primitive.ObjectID]string)
+	if actors, err := s.authRepo.GetUsersByIDs(ctx, actorIDs); err == nil {
+		for _, a := range actors {
+			name := a.DisplayName
+			if name == "" {
+				name = a.Username
+			}
+			names[a.ID] = name
+		}
+	}
+
+	for _, n := range unread {
+		link := d.NotificationsURL
+		if n.AnchorID != nil {
+			link = s.anchorURL(*n.AnchorID)
+		}
+		d.Unread = append(d.Unread, DigestNotification{
+			Headline: notifications.Headline(n, names[n.ActorID]),
+			Preview:  n.Preview,
+			URL:      link,
+		})
+	}
+	return nil
+}
+
+// addFollowedUpdates lists followed anchors whose version moved past what the user last saw
+func (s *Service) addFollowedUpdates(ctx context.Context, d *Digest, r *Recipient) error {
+	follows, err := s.anchorFollowsRepo.GetAllByUser(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	if len(follows) == 0 {
+		return nil
+	}
+
+	lastSeen := make(map[primitive.ObjectID]int, len(follows))
+	anchorIDs := make([]primitive.ObjectID, len(follows))
+	for i, f := range follows {
+		lastSeen[f.AnchorID] = f.LastSeenVersion
+		anchorIDs[i] = f.AnchorID
+	}
+
+	followed, err := s.anchorsRepo.GetAnchorsByIDs(ctx, anchorIDs)
+	if err != nil {
+		return err
+	}
+
+	var updated []anchors.Anchor
+	for _, a := range followed {
+		if a.Version <= lastSeen[a.ID] {
+			continue
+		}
+		if a.Visibility == anchors.VisibilityPrivate && a.UserID != r.ID {
+			continue
+		}
+		updated = append(updated, a)
+	}
+
+	// Most recently updated first
+	sort.Slice(updated, func(i, j int) bool {
+		return updated[i].LastItemAddedAt.After(updated[j].LastItemAddedAt)
+	})
+	if len(updated) > MaxDigestUpdates {
+		updated = updated[:MaxDigestUpdates]
+	}
+
+	for _, a := range updated {
+		d.Updates = append(d.Updates, DigestAnchorUpdate{
+			Title:    a.Title,
+			NewItems: a.Version - lastSeen[a.ID],
+			URL:      s.anchorURL(a.ID),
+		})
+	}
+	return nil
+}
+
+// addTrending lists the most engaging new public anchors tagged with the user's interests
+func (s *Service) addTrending(ctx context.Context, d *Digest, r *Recipient, since time.Time) error {
+	if len(r.Interests) == 0 {
+		return nil
+	}
+
+	exclude := append(s.blockPolicy.BlockedUserIDs(ctx, r.ID), r.ID)
+	trending, err := s.anchorsRepo.GetTrendingByTags(ctx, r.Interests, since, exclude, MaxDigestTrending)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range trending {
+		d.Trending = append(d.Trending, DigestAnchor{
+			Title:     a.Title,
+			LikeCount: a.LikeCount,
+			Tags:      a.Tags,
+			URL:       s.anchorURL(a.ID),
+		})
+	}
+	return nil
+}
+
+// send renders and emails a digest
+func (s *Service) send(ctx context.Context, r *Recipient, d *Digest) error {
+	subject, html, text, err := Render(d)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      r.Email,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+		Headers: map[string]string{
+			// One-click unsubscribe (RFC 8058)
+			"List-Unsubscribe":      "<" + d.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
+
+// Unsubscribe turns off a user's email digest
+func (s *Service) Unsubscribe(ctx context.Context, userID primitive.ObjectID) error {
+	settings, err := s.notificationsRepo.GetNotificationSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		settings = notifications.DefaultNotificationSettings(userID)
+	}
+
+	settings.EmailDigest = notifications.DigestOff
+	return s.notificationsRepo.SaveNotificationSettings(ctx, settings)
+}
+
+// UnsubscribeURL returns the signed link that turns off a user's digest. It does not
+// expire, so links in old emails keep working.
+func (s *Service) UnsubscribeURL(userID primitive.ObjectID) string {
+	query := url.Values{
+		"user":      {userID.Hex()},
+		"signature": {s.sign(userID)},
+	}
+	return strings.TrimSuffix(s.config.PublicAPIURL, "/") + "/api/v1/digest/unsubscribe?" + query.Encode()
+}
+
+// VerifyUnsubscribe checks an unsubscribe link's signature
+func (s *Service) VerifyUnsubscribe(userID primitive.ObjectID, signature string) bool {
+	return hmac.Equal([]byte(s.sign(userID)), []byte(signature))
+}
+
+// sign computes the link signature with a key derived from the JWT secret
+func (s *Service) sign(userID primitive.ObjectID) string {
+	mac := hmac.New(sha256.New, []byte(s.config.JWTSecret+":digest_unsubscribe"))
+	mac.Write([]byte(userID.Hex()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) frontendURL(path string) string {
+	return strings.TrimSuffix(s.config.FrontendURL, "/") + path
+}
+
+// SettingsURL returns the frontend page where users manage their notification settings
+func (s *Service) SettingsURL() string {
+	return s.frontendURL("/settings/notifications")
+}
+
+func (s *Service) anchorURL(anchorID primitive.ObjectID) string {
+	return s.frontendURL("/anchors/" + anchorID.Hex())
+}
+
+func (s *Service) interval() time.Duration {
+	if s.config.DigestIntervalMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(s.config.DigestIntervalMinutes) * time.Minute
+}
+
+// periodOf returns how much time a digest frequency covers
+func periodOf(frequency string) (time.Duration, bool) {
+	switch frequency {
+	case notifications.DigestDaily:
+		return 24 * time.Hour, true
+	case notifications.DigestWeekly:
+		return 7 * 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
@@ -56,6 +56,18 @@ func EncodeDiscoverCursor(score *int, createdAt time.Time, anchorID primitive.Ob
 	return base64.StdEncoding.EncodeToString(jsonBytes)
 }
 
+// EncodeHotCursor creates cursor for the hot and rising discovery categories
+func EncodeHotCursor(hotScore float64, scoredAt *time.Time, createdAt time.Time, anchorID primitive.ObjectID) string {
+	cursorData := DiscoverCursor{
+		HotScore:  &hotScore,
+		ScoredAt:  scoredAt,
+		CreatedAt: createdAt,
+		AnchorID:  anchorID,
+	}
+	jsonBytes, _ := json.Marshal(cursorData)
+	return base64.StdEncoding.EncodeToString(jsonBytes)
+}
+
 // DecodeDiscoverCursor decodes a discovery cursor
 func DecodeDiscoverCursor(cursor string) (*DiscoverCursor, error) {
 	if cursor == "" {
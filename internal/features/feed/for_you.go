@@ -0,0 +1,296 @@
+package feed
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Weights of the for_you score components; they sum to 1 so scores fall within [0, 1]
+const (
+	forYouTagWeight        = 0.4
+	forYouAuthorWeight     = 0.2
+	forYouFreshnessWeight  = 0.2
+	forYouEngagementWeight = 0.2
+)
+
+// Affinity each signal adds to a tag or author in the viewer's profile
+const (
+	interestAffinity       = 3
+	followedAnchorAffinity = 2
+	likedAnchorAffinity    = 1
+)
+
+const (
+	forYouCandidateLimit = 500            // Candidates ranked per request
+	forYouMaxPerAuthor   = 2              // Anchors by one author on a page
+	forYouFreshHalfLife  = 48 * time.Hour // Freshness halves every half-life since the latest content
+	forYouNewWindow      = 7 * 24 * time.Hour
+)
+
+// affinityProfile weighs the tags and authors a viewer has shown interest in
+type affinityProfile struct {
+	interests map[string]bool
+	tags      map[string]float64
+	authors   map[primitive.ObjectID]float64
+	maxTag    float64
+	maxAuthor float64
+}
+
+// newAffinityProfile builds a profile from the viewer's interests and the anchors they liked
+// and follow
+func newAffinityProfile(interests []string, liked, followed []anchors.Anchor) *affinityProfile {
+	p := &affinityProfile{
+		interests: make(map[string]bool, len(interests)),
+		tags:      make(map[string]float64),
+		authors:   make(map[primitive.ObjectID]float64),
+	}
+
+	for _, tag := range interests {
+		p.interests[tag] = true
+		p.addTag(tag, interestAffinity)
+	}
+	for _, a := range liked {
+		p.addAnchor(a, likedAnchorAffinity)
+	}
+	for _, a := range followed {
+		p.addAnchor(a, followedAnchorAffinity)
+	}
+
+	return p
+}
+
+func (p *affinityProfile) addAnchor(a anchors.Anchor, weight float64) {
+	for _, tag := range a.Tags {
+		p.addTag(tag, weight)
+	}
+	p.authors[a.UserID] += weight
+	p.maxAuthor = math.Max(p.maxAuthor, p.authors[a.UserID])
+}
+
+func (p *affinityProfile) addTag(tag string, weight float64) {
+	p.tags[tag] += weight
+	p.maxTag = math.Max(p.maxTag, p.tags[tag])
+}
+
+// tagList returns every tag in the profile
+func (p *affinityProfile) tagList() []string {
+	tags := make([]string, 0, len(p.tags))
+	for tag := range p.tags {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// authorList returns every author in the profile
+func (p *affinityProfile) authorList() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(p.authors))
+	for id := range p.authors {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// tagAffinity scores how well tags match the profile, within [0, 1], and returns the
+// strongest matching tag
+func (p *affinityProfile) tagAffinity(tags []string) (float64, string) {
+	if p.maxTag == 0 {
+		return 0, ""
+	}
+
+	var sum, best float64
+	var bestTag string
+	for _, tag := range tags {
+		weight := p.tags[tag]
+		sum += weight
+		if weight > best {
+			best, bestTag = weight, tag
+		}
+	}
+	return math.Min(sum/p.maxTag, 1), bestTag
+}
+
+// authorAffinity scores the viewer's affinity for an author, within [0, 1]
+func (p *affinityProfile) authorAffinity(authorID primitive.ObjectID) float64 {
+	if p.maxAuthor == 0 {
+		return 0
+	}
+	return p.authors[authorID] / p.maxAuthor
+}
+
+// rankedAnchor is a for_you candidate with its score and recommendation reason
+type rankedAnchor struct {
+	Anchor    anchors.Anchor
+	Score     float64
+	Reason    string
+	ReasonTag string
+}
+
+// scoreComponent is one weighted part of a for_you score
+type scoreComponent struct {
+	reason string
+	value  float64
+}
+
+// strongestComponent returns the reason of the largest non-zero component
+func strongestComponent(components []scoreComponent) string {
+	var reason string
+	var strongest float64
+	for _, c := range components {
+		if c.value > strongest {
+			strongest, reason = c.value, c.reason
+		}
+	}
+	return reason
+}
+
+// rankForYou scores candidates against the profile, best first
+func rankForYou(candidates []anchors.Anchor, profile *affinityProfile, now time.Time) []rankedAnchor {
+	maxEngagement := 0
+	for _, a := range candidates {
+		if a.EngagementScore > maxEngagement {
+			maxEngagement = a.EngagementScore
+		}
+	}
+
+	ranked := make([]rankedAnchor, len(candidates))
+	for i, a := range candidates {
+		tagScore, topTag := profile.tagAffinity(a.Tags)
+
+		activeAt := a.LastItemAddedAt
+		if a.CreatedAt.After(activeAt) {
+			activeAt = a.CreatedAt
+		}
+		age := math.Max(now.Sub(activeAt).Hours(), 0)
+		freshness := math.Exp2(-age / forYouFreshHalfLife.Hours())
+
+		var engagement float64
+		if maxEngagement > 0 && a.EngagementScore > 0 {
+			engagement = math.Log1p(float64(a.EngagementScore)) / math.Log1p(float64(maxEngagement))
+		}
+
+		personal := []scoreComponent{
+			{ForYouReasonTags, forYouTagWeight * tagScore},
+			{ForYouReasonAuthor, forYouAuthorWeight * profile.authorAffinity(a.UserID)},
+		}
+		global := []scoreComponent{
+			{ForYouReasonNew, forYouFreshnessWeight * freshness},
+			{ForYouReasonPopular, forYouEngagementWeight * engagement},
+		}
+
+		r := rankedAnchor{Anchor: a}
+		for _, c := range append(personal, global...) {
+			r.Score += c.value
+		}
+
+		// Explain with the strongest personal signal when there is one
+		r.Reason = strongestComponent(personal)
+		if r.Reason == "" {
+			r.Reason = strongestComponent(global)
+		}
+		if r.Reason == "" {
+			r.Reason = ForYouReasonNew
+		}
+		if r.Reason == ForYouReasonTags {
+			r.ReasonTag = topTag
+			if profile.interests[topTag] {
+				r.Reason = ForYouReasonInterest
+			}
+		}
+		ranked[i] = r
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Anchor.ID.Hex() > ranked[j].Anchor.ID.Hex()
+	})
+	return ranked
+}
+
+// diversify picks up to limit anchors in rank order, with at most maxPerAuthor by any one
+// author. Anchors passed over stay unseen and can fill later pages.
+func diversify(ranked []rankedAnchor, limit, maxPerAuthor int) (page []rankedAnchor, hasMore bool) {
+	perAuthor := make(map[primitive.ObjectID]int)
+	for _, r := range ranked {
+		if len(page) == limit {
+			break
+		}
+		if perAuthor[r.Anchor.UserID] >= maxPerAuthor {
+			continue
+		}
+		perAuthor[r.Anchor.UserID]++
+		page = append(page, r)
+	}
+	return page, len(ranked) > len(page)
+}
+
+// getForYouAnchors returns the next page of the viewer's for_you feed. Served anchors are
+// recorded so following pages, and the feed for the next ForYouSeenTTL, skip them.
+func (s *Service) getForYouAnchors(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	excludeUserIDs []primitive.ObjectID,
+	author AuthorVisibility,
+	tag *string,
+	mutedTags []string,
+	limit int,
+) ([]rankedAnchor, bool, error) {
+	now := time.Now()
+
+	var interests []string
+	if user, err := s.authRepo.GetUserByObjectID(ctx, userID); err == nil {
+		interests = user.Interests
+	}
+
+	likedIDs, err := s.likesRepo.GetLikedAnchorIDs(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	followedIDs, err := s.anchorFollowsRepo.GetFollowedAnchorIDs(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	liked, err := s.feedRepo.GetAffinitySources(ctx, likedIDs)
+	if err != nil {
+		return nil, false, err
+	}
+	followed, err := s.feedRepo.GetAffinitySources(ctx, followedIDs)
+	if err != nil {
+		return nil, false, err
+	}
+	profile := newAffinityProfile(interests, liked, followed)
+
+	// Anchors the viewer was already served, liked or follows
+	seenIDs, err := s.feedRepo.GetSeenAnchorIDs(ctx, userID, now.Add(-ForYouSeenTTL))
+	if err != nil {
+		return nil, false, err
+	}
+	seenIDs = append(append(seenIDs, likedIDs...), followedIDs...)
+
+	candidates, err := s.feedRepo.GetForYouCandidates(
+		ctx, excludeUserIDs, author, seenIDs, profile.tagList(), profile.authorList(), tag, mutedTags,
+		now.Add(-forYouNewWindow), forYouCandidateLimit,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	page, hasMore := diversify(rankForYou(candidates, profile, now), limit, forYouMaxPerAuthor)
+
+	servedIDs := make([]primitive.ObjectID, len(page))
+	for i, r := range page {
+		servedIDs[i] = r.Anchor.ID
+	}
+	if err := s.feedRepo.RecordImpressions(ctx, userID, servedIDs, now); err != nil {
+		log.Printf("Feed: failed to record for_you impressions: %v", err)
+	}
+
+	return page, hasMore, nil
+}
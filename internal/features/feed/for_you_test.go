@@ -0,0 +1,76 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func forYouAnchor(author primitive.ObjectID, age time.Duration, engagement int, tags ...string) anchors.Anchor {
+	at := time.Now().Add(-age)
+	return anchors.Anchor{
+		ID:              primitive.NewObjectID(),
+		UserID:          author,
+		Tags:            tags,
+		EngagementScore: engagement,
+		CreatedAt:       at,
+		LastItemAddedAt: at,
+	}
+}
+
+func TestRankForYouPrefersAffinity(t *testing.T) {
+	liked := forYouAnchor(primitive.NewObjectID(), 0, 0, "golang")
+	profile := newAffinityProfile([]string{"design"}, []anchors.Anchor{liked}, nil)
+
+	interest := forYouAnchor(primitive.NewObjectID(), 24*time.Hour, 0, "design")
+	similar := forYouAnchor(primitive.NewObjectID(), 24*time.Hour, 0, "golang")
+	unrelated := forYouAnchor(primitive.NewObjectID(), 24*time.Hour, 0, "cooking")
+
+	ranked := rankForYou([]anchors.Anchor{unrelated, similar, interest}, profile, time.Now())
+	require.Equal(t, interest.ID, ranked[0].Anchor.ID)
+	require.Equal(t, ForYouReasonInterest, ranked[0].Reason)
+	require.Equal(t, "design", ranked[0].ReasonTag)
+
+	require.Equal(t, similar.ID, ranked[1].Anchor.ID)
+	require.Equal(t, ForYouReasonTags, ranked[1].Reason)
+	require.Equal(t, "golang", ranked[1].ReasonTag)
+
+	require.Equal(t, unrelated.ID, ranked[2].Anchor.ID)
+	require.Equal(t, ForYouReasonNew, ranked[2].Reason)
+}
+
+func TestRankForYouWithoutProfile(t *testing.T) {
+	profile := newAffinityProfile(nil, nil, nil)
+
+	popular := forYouAnchor(primitive.NewObjectID(), 5*24*time.Hour, 500)
+	quiet := forYouAnchor(primitive.NewObjectID(), 5*24*time.Hour, 1)
+
+	ranked := rankForYou([]anchors.Anchor{quiet, popular}, profile, time.Now())
+	require.Equal(t, popular.ID, ranked[0].Anchor.ID)
+	require.Equal(t, ForYouReasonPopular, ranked[0].Reason)
+}
+
+func TestDiversifyLimitsAuthors(t *testing.T) {
+	prolific := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+
+	var ranked []rankedAnchor
+	for i := 0; i < 4; i++ {
+		ranked = append(ranked, rankedAnchor{Anchor: forYouAnchor(prolific, 0, 0)})
+	}
+	ranked = append(ranked, rankedAnchor{Anchor: forYouAnchor(other, 0, 0)})
+
+	page, hasMore := diversify(ranked, 3, 2)
+	require.Len(t, page, 3)
+	require.True(t, hasMore)
+	require.Equal(t, prolific, page[0].Anchor.UserID)
+	require.Equal(t, prolific, page[1].Anchor.UserID)
+	require.Equal(t, other, page[2].Anchor.UserID)
+
+	page, hasMore = diversify(ranked[:2], 3, 2)
+	require.Len(t, page, 2)
+	require.False(t, hasMore)
+}
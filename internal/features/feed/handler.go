@@ -1,6 +1,7 @@
 package feed
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -71,6 +72,64 @@ func (h *Handler) GetFollowingFeed(c *gin.Context) {
 	response.Success(c, feedResponse)
 }
 
+// GetListTimeline godoc
+// @Summary Get list timeline
+// @Description Get anchors from the accounts on a list. Private lists are visible only to their owner.
+// @Tags feed
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "List ID"
+// @Param limit query int false "Items per page (default 20, max 50)"
+// @Param cursor query string false "Pagination cursor"
+// @Success 200 {object} response.APIResponse{data=FeedResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /feed/lists/{id} [get]
+func (h *Handler) GetListTimeline(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "User not found in context")
+		return
+	}
+	user := usr.(*auth.User)
+
+	listID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid list ID")
+		return
+	}
+
+	var query FeedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters")
+		return
+	}
+
+	if err := ValidateFeedQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if query.Cursor != "" {
+		if _, err := ValidateCursor(query.Cursor); err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	timeline, err := h.service.GetListTimeline(c.Request.Context(), user.ID, listID, &query)
+	if err != nil {
+		if errors.Is(err, ErrListNotFound) {
+			response.Error(c, http.StatusNotFound, "List not found")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, "Failed to retrieve list timeline")
+		return
+	}
+
+	response.Success(c, timeline)
+}
+
 // GetDiscoverFeed godoc
 // @Summary Get discovery feed
 // @Description Get discovery feed of trending/popular public anchors from non-followed users
@@ -78,10 +137,11 @@ func (h *Handler) GetFollowingFeed(c *gin.Context) {
 // @Produce json
 // @Param limit query int false "Items per page (default 20, max 50)"
 // @Param cursor query string false "Pagination cursor"
-// @Param category query string false "Category: trending, popular, recent (default trending)"
+// @Param category query string false "Category: trending, popular, recent, hot, rising, for_you (default trending). for_you requires authentication"
 // @Param tag query string false "Filter by tag"
 // @Success 200 {object} response.APIResponse{data=DiscoverResponse}
 // @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
 // @Router /feed/discover [get]
 func (h *Handler) GetDiscoverFeed(c *gin.Context) {
 	// Get user if authenticated (optional)
@@ -103,6 +163,12 @@ func (h *Handler) GetDiscoverFeed(c *gin.Context) {
 		return
 	}
 
+	// for_you is built from the viewer's interests, likes and follows
+	if query.Category == CategoryForYou && userID == nil {
+		response.Error(c, http.StatusUnauthorized, "Authentication required for the for_you category")
+		return
+	}
+
 	if query.Cursor != "" {
 		if _, err := DecodeDiscoverCursor(query.Cursor); err != nil {
 			response.Error(c, http.StatusBadRequest, "Invalid cursor")
@@ -162,6 +228,12 @@ func (h *Handler) GetTagFeed(c *gin.Context) {
 		return
 	}
 
+	// for_you is built from the viewer's interests, likes and follows
+	if query.Category == CategoryForYou && userID == nil {
+		response.Error(c, http.StatusUnauthorized, "Authentication required for the for_you category")
+		return
+	}
+
 	if query.Cursor != "" {
 		if _, err := DecodeDiscoverCursor(query.Cursor); err != nil {
 			response.Error(c, http.StatusBadRequest, "Invalid cursor")
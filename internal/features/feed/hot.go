@@ -0,0 +1,141 @@
+package feed
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/config"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Engagement weights, matching anchors' all-time engagement score
+const (
+	hotLikeWeight    = 2
+	hotCloneWeight   = 3
+	hotCommentWeight = 1
+)
+
+// hotScoreBatchSize caps the number of score updates sent in one bulk write
+const hotScoreBatchSize = 500
+
+// Defaults used when the hot score settings are missing or invalid
+const (
+	defaultHotScoreGravity  = 1.8
+	defaultHotScoreWindow   = 72 * time.Hour
+	defaultHotScoreInterval = 10 * time.Minute
+)
+
+// HotScore ranks an anchor by the engagement it received within the scoring window against
+// the age of its latest content: (engagement + 1) / (hours since activeAt + 2)^gravity.
+// activeAt is when the anchor was created or last had an item added, so updating an old
+// anchor brings it back up.
+func HotScore(engagement float64, activeAt, now time.Time, gravity float64) float64 {
+	ageHours := now.Sub(activeAt).Hours()
+	if ageHours < 0 {
+		ageHours = 0
+	}
+	return (engagement + 1) / math.Pow(ageHours+2, gravity)
+}
+
+// HotScorer periodically recomputes the hot score of public anchors
+type HotScorer struct {
+	repo     *Repository
+	gravity  float64
+	window   time.Duration
+	interval time.Duration
+}
+
+func NewHotScorer(db *mongo.Database, cfg *config.Config) *HotScorer {
+	h := &HotScorer{
+		repo:     NewRepository(db),
+		gravity:  cfg.HotScoreGravity,
+		window:   time.Duration(cfg.HotScoreWindowHours) * time.Hour,
+		interval: time.Duration(cfg.HotScoreIntervalMinutes) * time.Minute,
+	}
+	if h.gravity <= 0 {
+		h.gravity = defaultHotScoreGravity
+	}
+	if h.window <= 0 {
+		h.window = defaultHotScoreWindow
+	}
+	if h.interval <= 0 {
+		h.interval = defaultHotScoreInterval
+	}
+	return h
+}
+
+// Run recomputes hot scores periodically until ctx is cancelled
+func (h *HotScorer) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.RunOnce(ctx); err != nil {
+			log.Printf("Hot scores: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce scores every public anchor with new content or engagement inside the window and
+// clears the score of anchors that are no longer eligible. The previous run's scores are kept
+// in prevHotScore first, so clients paging through them are not affected by the rewrite.
+func (h *HotScorer) RunOnce(ctx context.Context) error {
+	now := time.Now().Truncate(time.Millisecond) // As stored, so runs compare equal after a round trip
+	since := now.Add(-h.window)
+
+	run, err := h.repo.GetHotScoreRun(ctx)
+	if err != nil {
+		return err
+	}
+	if run.Current != nil {
+		if err := h.repo.KeepPreviousHotScores(ctx, *run.Current); err != nil {
+			return err
+		}
+		run = HotScoreRun{Previous: run.Current}
+		if err := h.repo.SaveHotScoreRun(ctx, run); err != nil {
+			return err
+		}
+	}
+
+	engagement, err := h.repo.GetRecentEngagement(ctx, since)
+	if err != nil {
+		return err
+	}
+	engagedIDs := make([]primitive.ObjectID, 0, len(engagement))
+	for id := range engagement {
+		engagedIDs = append(engagedIDs, id)
+	}
+
+	candidates, err := h.repo.GetHotScoreCandidates(ctx, since, engagedIDs)
+	if err != nil {
+		return err
+	}
+
+	scores := make(map[primitive.ObjectID]float64, len(candidates))
+	for _, anchor := range candidates {
+		activeAt := anchor.LastItemAddedAt
+		if anchor.CreatedAt.After(activeAt) {
+			activeAt = anchor.CreatedAt
+		}
+		scores[anchor.ID] = HotScore(engagement[anchor.ID], activeAt, now, h.gravity)
+	}
+
+	if err := h.repo.SetHotScores(ctx, scores, now); err != nil {
+		return err
+	}
+	if _, err := h.repo.ClearStaleHotScores(ctx, now); err != nil {
+		return err
+	}
+
+	run.Current = &now
+	return h.repo.SaveHotScoreRun(ctx, run)
+}
@@ -0,0 +1,74 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotScoreDecaysWithAge(t *testing.T) {
+	now := time.Now()
+
+	fresh := HotScore(10, now.Add(-time.Hour), now, 1.8)
+	stale := HotScore(10, now.Add(-48*time.Hour), now, 1.8)
+	require.Greater(t, fresh, stale)
+}
+
+func TestHotScoreFavoursVelocity(t *testing.T) {
+	now := time.Now()
+
+	// A new anchor with modest engagement outranks an old one with more
+	newer := HotScore(6, now.Add(-2*time.Hour), now, 1.8)
+	older := HotScore(40, now.Add(-60*time.Hour), now, 1.8)
+	require.Greater(t, newer, older)
+
+	// At the same age, more engagement ranks higher
+	require.Greater(t, HotScore(5, now.Add(-time.Hour), now, 1.8), HotScore(4, now.Add(-time.Hour), now, 1.8))
+}
+
+func TestHotScoreGravity(t *testing.T) {
+	now := time.Now()
+	activeAt := now.Add(-24 * time.Hour)
+
+	require.Greater(t, HotScore(10, activeAt, now, 1.2), HotScore(10, activeAt, now, 2.0))
+}
+
+func TestHotScoreFutureActivity(t *testing.T) {
+	now := time.Now()
+
+	// Clock skew must not push the score above that of a brand new anchor
+	require.Equal(t, HotScore(3, now, now, 1.8), HotScore(3, now.Add(time.Minute), now, 1.8))
+}
+
+func TestHotSlotForKeepsCursorRun(t *testing.T) {
+	older := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(10 * time.Minute)
+	run := HotScoreRun{Current: &newer, Previous: &older}
+
+	// First pages use the latest run
+	require.False(t, hotSlotFor(run, nil).previous)
+	require.True(t, hotSlotFor(run, nil).scoredAt.Equal(newer))
+
+	// A cursor from the previous run keeps paging against its scores
+	slot := hotSlotFor(run, &DiscoverCursor{ScoredAt: &older})
+	require.True(t, slot.previous)
+	require.Equal(t, "prevHotScore", slot.scoreField)
+	require.True(t, slot.scoredAt.Equal(older))
+
+	// A cursor whose run is gone falls back to the latest run
+	gone := older.Add(-10 * time.Minute)
+	require.False(t, hotSlotFor(run, &DiscoverCursor{ScoredAt: &gone}).previous)
+}
+
+func TestHotSlotForDuringRun(t *testing.T) {
+	older := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	// While a run rewrites hotScore, pages are ranked by the run kept in prevHotScore
+	slot := hotSlotFor(HotScoreRun{Previous: &older}, nil)
+	require.True(t, slot.previous)
+	require.True(t, slot.scoredAt.Equal(older))
+
+	// Before the first run completes nothing is filtered by run
+	require.Nil(t, hotSlotFor(HotScoreRun{}, nil).scoredAt)
+}
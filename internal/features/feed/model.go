@@ -6,12 +6,47 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Feed item reasons: why an anchor is in the home feed
+const (
+	ReasonOwn         = "own"
+	ReasonFollowing   = "following"
+	ReasonFollowedTag = "followed_tag"
+	ReasonList        = "list" // Timeline of a user list
+)
+
 const (
 	CategoryTrending = "trending"
 	CategoryPopular  = "popular"
 	CategoryRecent   = "recent"
+	CategoryHot      = "hot"     // Ranked by time-decayed recent engagement
+	CategoryRising   = "rising"  // Hot anchors created within RisingMaxAge
+	CategoryForYou   = "for_you" // Personalised for the signed-in viewer
+)
+
+// Reasons an anchor was recommended in the for_you category
+const (
+	ForYouReasonInterest = "interest"     // Tagged with one of the viewer's interests
+	ForYouReasonTags     = "similar_tags" // Shares tags with anchors the viewer liked or follows
+	ForYouReasonAuthor   = "author"       // By an author whose anchors the viewer liked or follows
+	ForYouReasonPopular  = "popular"      // Highly engaged with overall
+	ForYouReasonNew      = "new"          // Recently created or updated
 )
 
+// ForYouSeenTTL is how long anchors served in the for_you category stay out of it
+const ForYouSeenTTL = 48 * time.Hour
+
+// RisingMaxAge is how old an anchor can be to appear in the rising category
+const RisingMaxAge = 7 * 24 * time.Hour
+
+// HotScoreRun records which hot score runs are stored in full. Each run moves the scores of
+// the run before it to prevHotScore, so hot and rising pages keep paging against the scores
+// their first page was ranked by for one more interval.
+type HotScoreRun struct {
+	ID       string     `bson:"_id"`
+	Current  *time.Time `bson:"current,omitempty"`  // Run stored in hotScore; nil while a run rewrites it
+	Previous *time.Time `bson:"previous,omitempty"` // Run stored in prevHotScore
+}
+
 // FeedQuery represents the query parameters for filtering the feed
 type FeedQuery struct {
 	Limit      int    `form:"limit"`
@@ -88,6 +123,8 @@ type FeedItem struct {
 	Author          FeedItemAuthor     `json:"author"`
 	Engagement      FeedEngagement     `json:"engagement"`
 	Preview         FeedPreview        `json:"preview"`
+	Reason          string             `json:"reason"`              // "own", "following", "followed_tag", "list"
+	ReasonTag       *string            `json:"reasonTag,omitempty"` // The followed tag, for "followed_tag"
 }
 
 // FeedPagination represents the pagination metadata
@@ -103,6 +140,7 @@ type FeedMeta struct {
 	FeedType           string  `json:"feedType"`
 	IncludesOwnAnchors bool    `json:"includesOwnAnchors"`
 	TotalFollowing     int     `json:"totalFollowing"`
+	TotalFollowedTags  int     `json:"totalFollowedTags"`
 	EmptyReason        *string `json:"emptyReason"`
 }
 
@@ -124,6 +162,8 @@ type DiscoverQuery struct {
 // DiscoverCursor for pagination (includes score for trending/popular)
 type DiscoverCursor struct {
 	Score     *int               `json:"s,omitempty"`
+	HotScore  *float64           `json:"h,omitempty"` // Set for the hot and rising categories
+	ScoredAt  *time.Time         `json:"g,omitempty"` // Hot score run the first page was ranked by
 	CreatedAt time.Time          `json:"c"`
 	AnchorID  primitive.ObjectID `json:"i"`
 }
@@ -153,11 +193,14 @@ type DiscoverItem struct {
 	CloneCount      int                `json:"cloneCount"`
 	CommentCount    int                `json:"commentCount"`
 	EngagementScore int                `json:"engagementScore"`
+	HotScore        float64            `json:"hotScore"`
 	LastItemAddedAt time.Time          `json:"lastItemAddedAt"`
 	CreatedAt       time.Time          `json:"createdAt"`
 	Author          DiscoverItemAuthor `json:"author"`
 	Engagement      FeedEngagement     `json:"engagement"`
 	Preview         FeedPreview        `json:"preview"`
+	Reason          string             `json:"reason,omitempty"`    // for_you only: why the anchor was recommended
+	ReasonTag       string             `json:"reasonTag,omitempty"` // The tag behind interest and similar_tags reasons
 }
 
 // DiscoverMeta for discovery feed metadata
@@ -2,9 +2,11 @@ package feed
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/comments"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -12,14 +14,22 @@ import (
 )
 
 type Repository struct {
-	anchorsCollection *mongo.Collection
-	itemsCollection   *mongo.Collection
+	anchorsCollection  *mongo.Collection
+	itemsCollection    *mongo.Collection
+	likesCollection    *mongo.Collection
+	commentsCollection *mongo.Collection
+	seenCollection     *mongo.Collection
+	hotRunsCollection  *mongo.Collection
 }
 
 func NewRepository(db *mongo.Database) *Repository {
 	repo := &Repository{
-		anchorsCollection: db.Collection("anchors"),
-		itemsCollection:   db.Collection("items"),
+		anchorsCollection:  db.Collection("anchors"),
+		itemsCollection:    db.Collection("items"),
+		likesCollection:    db.Collection("likes"),
+		commentsCollection: db.Collection("comments"),
+		seenCollection:     db.Collection("feedImpressions"),
+		hotRunsCollection:  db.Collection("hotScoreRuns"),
 	}
 	repo.ensureIndexes()
 	return repo
@@ -37,40 +47,148 @@ func (r *Repository) ensureIndexes() {
 		},
 	}
 	r.anchorsCollection.Indexes().CreateOne(context.Background(), indexModel)
+
+	// Hot and rising discover categories, and the hot scorer's queries
+	r.anchorsCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "visibility", Value: 1},
+				{Key: "deletedAt", Value: 1},
+				{Key: "hotScoredAt", Value: 1},
+				{Key: "hotScore", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "visibility", Value: 1},
+				{Key: "deletedAt", Value: 1},
+				{Key: "prevHotScoredAt", Value: 1},
+				{Key: "prevHotScore", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			Keys:    bson.D{{Key: "hotScoredAt", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "createdAt", Value: -1}},
+		},
+	})
+	r.likesCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "createdAt", Value: -1}},
+	})
+	r.commentsCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "createdAt", Value: -1}},
+	})
+
+	// Anchors served in the for_you category, forgotten after ForYouSeenTTL
+	r.seenCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "anchorId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "seenAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(ForYouSeenTTL.Seconds())),
+		},
+	})
 }
 
-// GetFeedAnchors retrieves anchors for the feed with pagination
+// AuthorVisibility hides anchors of deactivated authors, except from the authors themselves,
+// and of private authors, except from the authors and their approved followers. It filters
+// on the author state auth copies onto each anchor.
+type AuthorVisibility struct {
+	ViewerID     *primitive.ObjectID // nil for anonymous viewers
+	FollowingIDs []primitive.ObjectID
+}
+
+// condition returns the filter clause enforcing the visibility rules
+func (v AuthorVisibility) condition() bson.M {
+	approved := append([]primitive.ObjectID{}, v.FollowingIDs...)
+	active := bson.M{"authorDeactivated": bson.M{"$ne": true}}
+	if v.ViewerID != nil {
+		approved = append(approved, *v.ViewerID)
+		active = bson.M{"$or": []bson.M{active, {"userId": *v.ViewerID}}}
+	}
+
+	return bson.M{"$and": []bson.M{
+		active,
+		{"$or": []bson.M{
+			{"authorPrivate": bson.M{"$ne": true}},
+			{"userId": bson.M{"$in": approved}},
+		}},
+	}}
+}
+
+// GetFeedAnchors retrieves anchors for the feed with pagination: public anchors of userIDs,
+// unlisted anchors of unlistedUserIDs, plus public anchors carrying any of followedTags except
+// those with a muted tag. Unlisted anchors are only meant for the author's followers, so
+// unlistedUserIDs should be limited to authors the viewer follows (and the viewer).
 func (r *Repository) GetFeedAnchors(
 	ctx context.Context,
 	userIDs []primitive.ObjectID,
-	blockedUserIDs []primitive.ObjectID, // New param
+	unlistedUserIDs []primitive.ObjectID,
+	followedTags []string,
+	mutedTags []string,
+	blockedUserIDs []primitive.ObjectID,
+	author AuthorVisibility,
 	cursor *FeedCursor,
 	limit int,
 ) ([]anchors.Anchor, error) {
-	// Base filter: Authors in userIDs, Public or Unlisted, Not Deleted
-	filter := bson.M{
-		"userId":     bson.M{"$in": userIDs},
-		"visibility": bson.M{"$in": []string{"public", "unlisted"}},
-		"deletedAt":  nil,
+	if userIDs == nil {
+		userIDs = []primitive.ObjectID{}
+	}
+
+	// Sources: authors (public, or unlisted when followed) and followed tags (public only)
+	sources := []bson.M{
+		{
+			"userId":     bson.M{"$in": userIDs},
+			"visibility": "public",
+		},
+	}
+	if len(unlistedUserIDs) > 0 {
+		sources = append(sources, bson.M{
+			"userId":     bson.M{"$in": unlistedUserIDs},
+			"visibility": "unlisted",
+		})
+	}
+	if len(followedTags) > 0 {
+		tagSource := bson.M{
+			"tags":       bson.M{"$in": followedTags},
+			"visibility": "public",
+		}
+		if len(mutedTags) > 0 {
+			tagSource["tags"] = bson.M{"$in": followedTags, "$nin": mutedTags}
+		}
+		sources = append(sources, tagSource)
+	}
+
+	conditions := []bson.M{
+		{"$or": sources},
+		{"deletedAt": nil},
+		author.condition(),
 	}
 
-	// Filter out blocked users if provided (though userIDs should already be filtered by service)
-	// But as a safeguard:
+	// Filter out blocked and muted users
 	if len(blockedUserIDs) > 0 {
-		filter["userId"] = bson.M{"$in": userIDs, "$nin": blockedUserIDs}
+		conditions = append(conditions, bson.M{"userId": bson.M{"$nin": blockedUserIDs}})
 	}
 
 	// Apply cursor pagination if present
 	if cursor != nil {
-		filter["$or"] = []bson.M{
+		conditions = append(conditions, bson.M{"$or": []bson.M{
 			{"lastItemAddedAt": bson.M{"$lt": cursor.Timestamp}},
 			{
 				"lastItemAddedAt": cursor.Timestamp,
 				"_id":             bson.M{"$lt": cursor.AnchorID},
 			},
-		}
+		}})
 	}
 
+	filter := bson.M{"$and": conditions}
+
 	// Sort by lastItemAddedAt DESC, _id DESC
 	opts := options.Find().
 		SetSort(bson.D{
@@ -158,8 +276,10 @@ func (r *Repository) GetDiscoverAnchors(
 	ctx context.Context,
 	excludeUserIDs []primitive.ObjectID,
 	blockedUserIDs []primitive.ObjectID, // New param
+	author AuthorVisibility,
 	category string,
 	tag *string,
+	mutedTags []string,
 	cursor *DiscoverCursor,
 	limit int,
 ) ([]anchors.Anchor, error) {
@@ -167,6 +287,7 @@ func (r *Repository) GetDiscoverAnchors(
 	filter := bson.M{
 		"visibility": "public",
 		"deletedAt":  nil,
+		"$and":       []bson.M{author.condition()},
 	}
 
 	// Exclude followed users, self, AND blocked users
@@ -202,6 +323,21 @@ func (r *Repository) GetDiscoverAnchors(
 		filter["tags"] = *tag
 	}
 
+	// Drop anchors carrying any tag the viewer muted
+	if len(mutedTags) > 0 {
+		filter["$nor"] = []bson.M{{"tags": bson.M{"$in": mutedTags}}}
+	}
+
+	// Hot and rising rank by one stored hot score run, the one the first page came from
+	var slot hotSlot
+	if category == CategoryHot || category == CategoryRising {
+		run, err := r.GetHotScoreRun(ctx)
+		if err != nil {
+			return nil, err
+		}
+		slot = hotSlotFor(run, cursor)
+	}
+
 	// Category-specific logic
 	var sort bson.D
 
@@ -226,6 +362,19 @@ func (r *Repository) GetDiscoverAnchors(
 			{Key: "createdAt", Value: -1},
 			{Key: "_id", Value: -1},
 		}
+	case CategoryHot, CategoryRising:
+		filter[slot.scoreField] = bson.M{"$gt": 0}
+		if slot.scoredAt != nil {
+			filter[slot.scoredAtField] = *slot.scoredAt
+		}
+		if category == CategoryRising {
+			// Hot anchors that are themselves new, not old anchors with fresh items
+			filter["createdAt"] = bson.M{"$gte": time.Now().Add(-RisingMaxAge)}
+		}
+		sort = bson.D{
+			{Key: slot.scoreField, Value: -1},
+			{Key: "_id", Value: -1},
+		}
 	}
 
 	// Apply cursor pagination
@@ -238,6 +387,16 @@ func (r *Repository) GetDiscoverAnchors(
 					"_id":       bson.M{"$lt": cursor.AnchorID},
 				},
 			}
+		} else if category == CategoryHot || category == CategoryRising {
+			if cursor.HotScore != nil {
+				filter["$or"] = []bson.M{
+					{slot.scoreField: bson.M{"$lt": *cursor.HotScore}},
+					{
+						slot.scoreField: *cursor.HotScore,
+						"_id":           bson.M{"$lt": cursor.AnchorID},
+					},
+				}
+			}
 		} else {
 			// trending/popular - use score
 			if cursor.Score != nil {
@@ -270,5 +429,336 @@ func (r *Repository) GetDiscoverAnchors(
 		return nil, err
 	}
 
+	// Report the score the page was ranked by, which the next cursor continues from
+	if slot.previous {
+		for i := range results {
+			results[i].HotScore = results[i].PrevHotScore
+			results[i].HotScoredAt = results[i].PrevHotScoredAt
+		}
+	}
+
+	return results, nil
+}
+
+// hotSlot is the stored hot score run a hot or rising page is ranked by
+type hotSlot struct {
+	scoreField    string
+	scoredAtField string
+	scoredAt      *time.Time // nil before the first run completes
+	previous      bool       // The run is stored in prevHotScore
+}
+
+// hotSlotFor picks the run a page is ranked by: the cursor's run while either field still
+// holds it, otherwise the latest complete run. A cursor whose run is gone continues from its
+// score in the latest run.
+func hotSlotFor(run HotScoreRun, cursor *DiscoverCursor) hotSlot {
+	current := hotSlot{scoreField: "hotScore", scoredAtField: "hotScoredAt", scoredAt: run.Current}
+	previous := hotSlot{scoreField: "prevHotScore", scoredAtField: "prevHotScoredAt", scoredAt: run.Previous, previous: true}
+
+	if cursor != nil && cursor.ScoredAt != nil {
+		switch {
+		case run.Current != nil && run.Current.Equal(*cursor.ScoredAt):
+			return current
+		case run.Previous != nil && run.Previous.Equal(*cursor.ScoredAt):
+			return previous
+		}
+	}
+	if run.Current == nil && run.Previous != nil {
+		return previous
+	}
+	return current
+}
+
+// hotScoreRunID is the ID of the single HotScoreRun document
+const hotScoreRunID = "hot"
+
+// GetHotScoreRun returns which hot score runs are stored; empty before the first run
+func (r *Repository) GetHotScoreRun(ctx context.Context) (HotScoreRun, error) {
+	var run HotScoreRun
+	err := r.hotRunsCollection.FindOne(ctx, bson.M{"_id": hotScoreRunID}).Decode(&run)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return HotScoreRun{}, err
+	}
+	return run, nil
+}
+
+// SaveHotScoreRun records which hot score runs are stored
+func (r *Repository) SaveHotScoreRun(ctx context.Context, run HotScoreRun) error {
+	run.ID = hotScoreRunID
+	_, err := r.hotRunsCollection.ReplaceOne(ctx, bson.M{"_id": hotScoreRunID}, run, options.Replace().SetUpsert(true))
+	return err
+}
+
+// KeepPreviousHotScores copies the scores of the given run to prevHotScore, before the next
+// run overwrites hotScore
+func (r *Repository) KeepPreviousHotScores(ctx context.Context, scoredAt time.Time) error {
+	_, err := r.anchorsCollection.UpdateMany(ctx,
+		bson.M{"hotScoredAt": scoredAt},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"prevHotScore":    "$hotScore",
+			"prevHotScoredAt": "$hotScoredAt",
+		}}}},
+	)
+	return err
+}
+
+// GetRecentEngagement returns the weighted likes, clones and published comments each anchor
+// received since the given time, keyed by anchor ID
+func (r *Repository) GetRecentEngagement(ctx context.Context, since time.Time) (map[primitive.ObjectID]float64, error) {
+	engagement := make(map[primitive.ObjectID]float64)
+
+	count := func(coll *mongo.Collection, match bson.M, anchorField string, weight float64) error {
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: match}},
+			{{Key: "$group", Value: bson.M{"_id": "$" + anchorField, "count": bson.M{"$sum": 1}}}},
+		}
+		cursor, err := coll.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var doc struct {
+				AnchorID primitive.ObjectID `bson:"_id"`
+				Count    int                `bson:"count"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			engagement[doc.AnchorID] += weight * float64(doc.Count)
+		}
+		return cursor.Err()
+	}
+
+	if err := count(r.likesCollection, bson.M{
+		"createdAt": bson.M{"$gte": since},
+	}, "anchorId", hotLikeWeight); err != nil {
+		return nil, err
+	}
+	if err := count(r.commentsCollection, bson.M{
+		"createdAt": bson.M{"$gte": since},
+		"deletedAt": nil,
+		"status":    bson.M{"$nin": []string{comments.StatusHidden, comments.StatusHeld}},
+	}, "anchorId", hotCommentWeight); err != nil {
+		return nil, err
+	}
+	if err := count(r.anchorsCollection, bson.M{
+		"createdAt":          bson.M{"$gte": since},
+		"clonedFromAnchorId": bson.M{"$ne": nil},
+	}, "clonedFromAnchorId", hotCloneWeight); err != nil {
+		return nil, err
+	}
+
+	return engagement, nil
+}
+
+// GetHotScoreCandidates returns the public anchors created, updated with new items or engaged
+// with since the given time. Only the fields the hot score needs are loaded.
+func (r *Repository) GetHotScoreCandidates(ctx context.Context, since time.Time, engagedIDs []primitive.ObjectID) ([]anchors.Anchor, error) {
+	sources := []bson.M{
+		{"lastItemAddedAt": bson.M{"$gte": since}},
+		{"createdAt": bson.M{"$gte": since}},
+	}
+	if len(engagedIDs) > 0 {
+		sources = append(sources, bson.M{"_id": bson.M{"$in": engagedIDs}})
+	}
+	filter := bson.M{
+		"visibility": "public",
+		"deletedAt":  nil,
+		"$or":        sources,
+	}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "createdAt": 1, "lastItemAddedAt": 1})
+
+	cursor, err := r.anchorsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []anchors.Anchor
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SetHotScores stores the given hot scores, stamping them with scoredAt
+func (r *Repository) SetHotScores(ctx context.Context, scores map[primitive.ObjectID]float64, scoredAt time.Time) error {
+	models := make([]mongo.WriteModel, 0, hotScoreBatchSize)
+	flush := func() error {
+		if len(models) == 0 {
+			return nil
+		}
+		_, err := r.anchorsCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		models = models[:0]
+		return err
+	}
+
+	for id, score := range scores {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"hotScore": score, "hotScoredAt": scoredAt}}))
+		if len(models) == hotScoreBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// ClearStaleHotScores zeroes the hot score of anchors not scored since the given time, i.e.
+// those that dropped out of the scoring window, were made private or were deleted
+func (r *Repository) ClearStaleHotScores(ctx context.Context, scoredBefore time.Time) (int64, error) {
+	result, err := r.anchorsCollection.UpdateMany(ctx,
+		bson.M{
+			"hotScoredAt": bson.M{"$lt": scoredBefore},
+			"hotScore":    bson.M{"$gt": 0},
+		},
+		bson.M{"$set": bson.M{"hotScore": 0}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// GetAffinitySources returns the author and tags of the given anchors, which shape a
+// viewer's for_you profile
+func (r *Repository) GetAffinitySources(ctx context.Context, anchorIDs []primitive.ObjectID) ([]anchors.Anchor, error) {
+	if len(anchorIDs) == 0 {
+		return []anchors.Anchor{}, nil
+	}
+
+	filter := bson.M{
+		"_id":       bson.M{"$in": anchorIDs},
+		"deletedAt": nil,
+	}
+	opts := options.Find().SetProjection(bson.M{"userId": 1, "tags": 1})
+
+	cursor, err := r.anchorsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []anchors.Anchor
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetForYouCandidates returns the public anchors to rank for a viewer's for_you feed: those
+// matching the viewer's affinity tags or authors, hot anchors and anchors created since
+// newSince, most recently updated first
+func (r *Repository) GetForYouCandidates(
+	ctx context.Context,
+	excludeUserIDs []primitive.ObjectID,
+	author AuthorVisibility,
+	excludeAnchorIDs []primitive.ObjectID,
+	tags []string,
+	authorIDs []primitive.ObjectID,
+	tag *string,
+	mutedTags []string,
+	newSince time.Time,
+	limit int,
+) ([]anchors.Anchor, error) {
+	sources := []bson.M{
+		{"hotScore": bson.M{"$gt": 0}},
+		{"createdAt": bson.M{"$gte": newSince}},
+	}
+	if len(tags) > 0 {
+		sources = append(sources, bson.M{"tags": bson.M{"$in": tags}})
+	}
+	if len(authorIDs) > 0 {
+		sources = append(sources, bson.M{"userId": bson.M{"$in": authorIDs}})
+	}
+
+	filter := bson.M{
+		"visibility": "public",
+		"deletedAt":  nil,
+		"$or":        sources,
+		"$and":       []bson.M{author.condition()},
+	}
+	if len(excludeUserIDs) > 0 {
+		filter["userId"] = bson.M{"$nin": excludeUserIDs}
+	}
+	if len(excludeAnchorIDs) > 0 {
+		filter["_id"] = bson.M{"$nin": excludeAnchorIDs}
+	}
+	if tag != nil && *tag != "" {
+		filter["tags"] = *tag
+	}
+	if len(mutedTags) > 0 {
+		filter["$nor"] = []bson.M{{"tags": bson.M{"$in": mutedTags}}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "lastItemAddedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.anchorsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []anchors.Anchor
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
+
+// GetSeenAnchorIDs returns the anchors served to a user in the for_you category since the
+// given time
+func (r *Repository) GetSeenAnchorIDs(ctx context.Context, userID primitive.ObjectID, since time.Time) ([]primitive.ObjectID, error) {
+	filter := bson.M{
+		"userId": userID,
+		"seenAt": bson.M{"$gte": since},
+	}
+	opts := options.Find().SetProjection(bson.M{"anchorId": 1})
+
+	cursor, err := r.seenCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var doc struct {
+			AnchorID primitive.ObjectID `bson:"anchorId"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.AnchorID)
+	}
+	return ids, cursor.Err()
+}
+
+// RecordImpressions marks the given anchors as served to a user in the for_you category
+func (r *Repository) RecordImpressions(ctx context.Context, userID primitive.ObjectID, anchorIDs []primitive.ObjectID, seenAt time.Time) error {
+	if len(anchorIDs) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(anchorIDs))
+	for i, anchorID := range anchorIDs {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"userId": userID, "anchorId": anchorID}).
+			SetUpdate(bson.M{"$set": bson.M{"seenAt": seenAt}}).
+			SetUpsert(true)
+	}
+	_, err := r.seenCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// DeleteImpressionsByUser removes a user's for_you impressions
+func (r *Repository) DeleteImpressionsByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.seenCollection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
 	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/lists"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/tag_follows"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -20,9 +24,10 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	likesRepo := likes.NewRepository(db)
 	anchorsRepo := anchors.NewRepository(db)
 	anchorFollowsRepo := anchor_follows.NewRepository(db)
+	mutesRepo := mutes.NewRepository(db)
 
 	// Initialize service
-	service := NewService(feedRepo, authRepo, followsRepo, likesRepo, anchorsRepo, anchorFollowsRepo)
+	service := NewService(feedRepo, authRepo, followsRepo, likesRepo, anchorsRepo, anchorFollowsRepo, mutesRepo, tag_follows.NewRepository(db), lists.NewRepository(db), safety.NewBlockPolicy(db))
 
 	// Initialize handler
 	handler := NewHandler(service, cfg)
@@ -40,6 +45,9 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 		// Discovery feed - optional auth
 		feed.GET("/discover", optionalAuth, handler.GetDiscoverFeed)
 
+		// List timeline - requires auth
+		feed.GET("/lists/:id", authMiddleware, handler.GetListTimeline)
+
 		// Tag feed - optional auth (or no auth, as per instruction's example)
 		feed.GET("/tags/:tagName", handler.GetTagFeed)
 	}
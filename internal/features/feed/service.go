@@ -2,15 +2,23 @@ package feed
 
 import (
 	"context"
+	"errors"
 
 	"github.com/xyz-asif/gotodo/internal/features/anchor_follows"
 	"github.com/xyz-asif/gotodo/internal/features/anchors"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
 	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/lists"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/tag_follows"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrListNotFound is returned when a list does not exist or is hidden from the viewer
+var ErrListNotFound = errors.New("list not found")
+
 type Service struct {
 	feedRepo          *Repository
 	authRepo          *auth.Repository
@@ -18,6 +26,10 @@ type Service struct {
 	likesRepo         *likes.Repository
 	anchorsRepo       *anchors.Repository
 	anchorFollowsRepo *anchor_follows.Repository
+	mutesRepo         *mutes.Repository
+	tagFollowsRepo    *tag_follows.Repository
+	listsRepo         *lists.Repository
+	blockPolicy       *safety.BlockPolicy
 }
 
 func NewService(
@@ -27,6 +39,10 @@ func NewService(
 	likesRepo *likes.Repository,
 	anchorsRepo *anchors.Repository,
 	anchorFollowsRepo *anchor_follows.Repository,
+	mutesRepo *mutes.Repository,
+	tagFollowsRepo *tag_follows.Repository,
+	listsRepo *lists.Repository,
+	blockPolicy *safety.BlockPolicy,
 ) *Service {
 	return &Service{
 		feedRepo:          feedRepo,
@@ -35,7 +51,37 @@ func NewService(
 		likesRepo:         likesRepo,
 		anchorsRepo:       anchorsRepo,
 		anchorFollowsRepo: anchorFollowsRepo,
+		mutesRepo:         mutesRepo,
+		tagFollowsRepo:    tagFollowsRepo,
+		listsRepo:         listsRepo,
+		blockPolicy:       blockPolicy,
+	}
+}
+
+// withMutedUsers extends an exclusion list with the users the viewer has muted
+func (s *Service) withMutedUsers(ctx context.Context, excluded []primitive.ObjectID, viewerID *primitive.ObjectID) []primitive.ObjectID {
+	if viewerID == nil {
+		return excluded
+	}
+
+	mutedIDs, err := s.mutesRepo.GetMutedUserIDs(ctx, *viewerID)
+	if err != nil {
+		return excluded
+	}
+	return append(excluded, mutedIDs...)
+}
+
+// mutedTags returns the tags the viewer has muted, nil for anonymous viewers
+func (s *Service) mutedTags(ctx context.Context, viewerID *primitive.ObjectID) []string {
+	if viewerID == nil {
+		return nil
+	}
+
+	tags, err := s.mutesRepo.GetMutedTags(ctx, *viewerID)
+	if err != nil {
+		return nil
 	}
+	return tags
 }
 
 func (s *Service) GetHomeFeed(
@@ -66,6 +112,12 @@ func (s *Service) GetHomeFeed(
 		feedUserIDs = append(feedUserIDs, userID)
 	}
 
+	// Followed tags mix in new public anchors from outside the user's follows
+	followedTags, err := s.tagFollowsRepo.GetFollowedTagNames(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize enrichment sections for first page only
 	var followingAnchors FollowingAnchorsSection
 	var suggestedCategories []SuggestedCategory
@@ -76,7 +128,7 @@ func (s *Service) GetHomeFeed(
 	}
 
 	// 4. Check for empty connection
-	if len(feedUserIDs) == 0 {
+	if len(feedUserIDs) == 0 && len(followedTags) == 0 {
 		reason := "NO_FOLLOWING"
 		return &HomeFeedResponse{
 			FollowingAnchors:    followingAnchors,
@@ -90,6 +142,7 @@ func (s *Service) GetHomeFeed(
 					FeedType:           "following",
 					IncludesOwnAnchors: includeOwn,
 					TotalFollowing:     0,
+					TotalFollowedTags:  0,
 					EmptyReason:        &reason,
 				},
 			},
@@ -97,13 +150,15 @@ func (s *Service) GetHomeFeed(
 	}
 
 	// 5. Query Anchors
-	var blockedUserIDs []primitive.ObjectID
-	currentUser, err := s.authRepo.GetUserByObjectID(ctx, userID)
-	if err == nil && currentUser != nil {
-		blockedUserIDs = currentUser.BlockedUsers
-	}
+	// Blocks hide content in both directions
+	blockedUserIDs := s.blockPolicy.BlockedUserIDs(ctx, userID)
+
+	blockedUserIDs = s.withMutedUsers(ctx, blockedUserIDs, &userID)
 
-	anchorsList, err := s.feedRepo.GetFeedAnchors(ctx, feedUserIDs, blockedUserIDs, cursor, query.Limit+1)
+	// Tag anchors may come from deactivated or private accounts the user is not approved for
+	author := AuthorVisibility{ViewerID: &userID, FollowingIDs: followingIDs}
+
+	anchorsList, err := s.feedRepo.GetFeedAnchors(ctx, feedUserIDs, feedUserIDs, followedTags, s.mutedTags(ctx, &userID), blockedUserIDs, author, cursor, query.Limit+1)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +174,7 @@ func (s *Service) GetHomeFeed(
 	if len(anchorsList) == 0 {
 		reason := "END_OF_FEED"
 		if cursor == nil {
-			if len(followingIDs) == 0 && !includeOwn {
+			if len(followingIDs) == 0 && !includeOwn && len(followedTags) == 0 {
 				reason = "NO_FOLLOWING"
 			} else {
 				reason = "NO_CONTENT"
@@ -141,13 +196,173 @@ func (s *Service) GetHomeFeed(
 					FeedType:           "following",
 					IncludesOwnAnchors: includeOwn,
 					TotalFollowing:     len(followingIDs),
+					TotalFollowedTags:  len(followedTags),
 					EmptyReason:        &reason,
 				},
 			},
 		}, nil
 	}
 
-	// 8. Enrich Authors
+	// 8. Enrich and build response items
+	feedItems, err := s.buildFeedItems(ctx, anchorsList, userID, followingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	followingSet := make(map[primitive.ObjectID]bool, len(followingIDs))
+	for _, id := range followingIDs {
+		followingSet[id] = true
+	}
+	for i, anchor := range anchorsList {
+		feedItems[i].Reason, feedItems[i].ReasonTag = feedReason(anchor, userID, followingSet, followedTags)
+	}
+
+	// 9. Build Next Cursor
+	var nextCursor *string
+	if hasMore {
+		lastAnchor := anchorsList[len(anchorsList)-1]
+		c := EncodeCursor(lastAnchor.LastItemAddedAt, lastAnchor.ID)
+		nextCursor = &c
+	}
+
+	return &HomeFeedResponse{
+		FollowingAnchors:    followingAnchors,
+		SuggestedCategories: suggestedCategories,
+		Feed: FeedResponse{
+			Items: feedItems,
+			Pagination: FeedPagination{
+				Limit:      query.Limit,
+				HasMore:    hasMore,
+				NextCursor: nextCursor,
+				ItemCount:  len(feedItems),
+			},
+			Meta: FeedMeta{
+				FeedType:           "following",
+				IncludesOwnAnchors: includeOwn,
+				TotalFollowing:     len(followingIDs),
+				TotalFollowedTags:  len(followedTags),
+				EmptyReason:        nil,
+			},
+		},
+	}, nil
+}
+
+// GetListTimeline returns the anchors of a list's members, newest activity first
+func (s *Service) GetListTimeline(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	listID primitive.ObjectID,
+	query *FeedQuery,
+) (*FeedResponse, error) {
+	// 1. Load the list; private lists and lists of blocked owners are hidden
+	list, err := s.listsRepo.GetListByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil || !list.IsVisibleTo(&userID) || s.blockPolicy.IsBlocked(ctx, userID, list.UserID) {
+		return nil, ErrListNotFound
+	}
+
+	// 2. Decode Cursor
+	cursor, err := DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := FeedMeta{
+		FeedType:       "list",
+		TotalFollowing: list.MemberCount, // Accounts on the list
+	}
+
+	if len(list.MemberIDs) == 0 {
+		reason := "EMPTY_LIST"
+		meta.EmptyReason = &reason
+		return &FeedResponse{
+			Items:      []FeedItem{},
+			Pagination: FeedPagination{Limit: query.Limit},
+			Meta:       meta,
+		}, nil
+	}
+
+	// 3. Exclusions: blocks, deactivated, muted and private accounts the viewer is not approved for
+	followingIDs, err := s.followsRepo.GetAllFollowingIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedUserIDs := s.blockPolicy.BlockedUserIDs(ctx, userID)
+	blockedUserIDs = s.withMutedUsers(ctx, blockedUserIDs, &userID)
+	author := AuthorVisibility{ViewerID: &userID, FollowingIDs: followingIDs}
+
+	// Unlisted anchors only of members the viewer follows, as on the home feed
+	following := make(map[primitive.ObjectID]bool, len(followingIDs))
+	for _, id := range followingIDs {
+		following[id] = true
+	}
+	var unlistedIDs []primitive.ObjectID
+	for _, id := range list.MemberIDs {
+		if following[id] || id == userID {
+			unlistedIDs = append(unlistedIDs, id)
+		}
+	}
+
+	// 4. Query Anchors
+	anchorsList, err := s.feedRepo.GetFeedAnchors(ctx, list.MemberIDs, unlistedIDs, nil, nil, blockedUserIDs, author, cursor, query.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := false
+	if len(anchorsList) > query.Limit {
+		hasMore = true
+		anchorsList = anchorsList[:query.Limit]
+	}
+
+	if len(anchorsList) == 0 {
+		reason := "END_OF_FEED"
+		if cursor == nil {
+			reason = "NO_CONTENT"
+		}
+		meta.EmptyReason = &reason
+		return &FeedResponse{
+			Items:      []FeedItem{},
+			Pagination: FeedPagination{Limit: query.Limit},
+			Meta:       meta,
+		}, nil
+	}
+
+	// 5. Enrich and build response items
+	feedItems, err := s.buildFeedItems(ctx, anchorsList, userID, followingIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range feedItems {
+		feedItems[i].Reason = ReasonList
+	}
+
+	// 6. Build Next Cursor
+	var nextCursor *string
+	if hasMore {
+		lastAnchor := anchorsList[len(anchorsList)-1]
+		c := EncodeCursor(lastAnchor.LastItemAddedAt, lastAnchor.ID)
+		nextCursor = &c
+	}
+
+	return &FeedResponse{
+		Items: feedItems,
+		Pagination: FeedPagination{
+			Limit:      query.Limit,
+			HasMore:    hasMore,
+			NextCursor: nextCursor,
+			ItemCount:  len(feedItems),
+		},
+		Meta: meta,
+	}, nil
+}
+
+// buildFeedItems enriches anchors with authors, the viewer's engagement and previews
+func (s *Service) buildFeedItems(ctx context.Context, anchorsList []anchors.Anchor, userID primitive.ObjectID, followingIDs []primitive.ObjectID) ([]FeedItem, error) {
+	// Enrich authors
 	anchorAuthorIDs := make([]primitive.ObjectID, len(anchorsList))
 	for i, a := range anchorsList {
 		anchorAuthorIDs[i] = a.UserID
@@ -157,13 +372,13 @@ func (s *Service) GetHomeFeed(
 		return nil, err
 	}
 
-	// 9. Enrich Engagement
+	// Enrich engagement
 	engagementMap, err := s.enrichWithEngagement(ctx, anchorsList, userID, followingIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	// 10. Enrich Previews
+	// Enrich previews
 	anchorIDs := make([]primitive.ObjectID, len(anchorsList))
 	for i, a := range anchorsList {
 		anchorIDs[i] = a.ID
@@ -173,7 +388,7 @@ func (s *Service) GetHomeFeed(
 		return nil, err
 	}
 
-	// 11. Build Response Items
+	// Build response items
 	feedItems := make([]FeedItem, len(anchorsList))
 	for i, anchor := range anchorsList {
 		author, ok := authorsMap[anchor.UserID]
@@ -208,33 +423,26 @@ func (s *Service) GetHomeFeed(
 		}
 	}
 
-	// 12. Build Next Cursor
-	var nextCursor *string
-	if hasMore {
-		lastAnchor := anchorsList[len(anchorsList)-1]
-		c := EncodeCursor(lastAnchor.LastItemAddedAt, lastAnchor.ID)
-		nextCursor = &c
-	}
+	return feedItems, nil
+}
 
-	return &HomeFeedResponse{
-		FollowingAnchors:    followingAnchors,
-		SuggestedCategories: suggestedCategories,
-		Feed: FeedResponse{
-			Items: feedItems,
-			Pagination: FeedPagination{
-				Limit:      query.Limit,
-				HasMore:    hasMore,
-				NextCursor: nextCursor,
-				ItemCount:  len(feedItems),
-			},
-			Meta: FeedMeta{
-				FeedType:           "following",
-				IncludesOwnAnchors: includeOwn,
-				TotalFollowing:     len(followingIDs),
-				EmptyReason:        nil,
-			},
-		},
-	}, nil
+// feedReason explains why an anchor is in the home feed. Followed authors take precedence over followed tags.
+func feedReason(anchor anchors.Anchor, userID primitive.ObjectID, followingSet map[primitive.ObjectID]bool, followedTags []string) (string, *string) {
+	if anchor.UserID == userID {
+		return ReasonOwn, nil
+	}
+	if followingSet[anchor.UserID] {
+		return ReasonFollowing, nil
+	}
+	for _, followed := range followedTags {
+		for _, tag := range anchor.Tags {
+			if tag == followed {
+				t := tag
+				return ReasonFollowedTag, &t
+			}
+		}
+	}
+	return ReasonFollowing, nil
 }
 
 func (s *Service) getFollowingAnchorsForFeed(ctx context.Context, userID primitive.ObjectID) (FollowingAnchorsSection, error) {
@@ -542,24 +750,44 @@ func (s *Service) GetDiscoverFeed(
 	// 4. Query anchors
 	var blockedUserIDs []primitive.ObjectID
 	if isAuthenticated {
-		currentUser, err := s.authRepo.GetUserByObjectID(ctx, *userID)
-		if err == nil && currentUser != nil {
-			blockedUserIDs = currentUser.BlockedUsers
-		}
+		blockedUserIDs = s.blockPolicy.BlockedUserIDs(ctx, *userID)
 	}
 
-	anchorsList, err := s.feedRepo.GetDiscoverAnchors(
-		ctx, excludeUserIDs, blockedUserIDs, query.Category, tagPtr, cursor, query.Limit+1,
-	)
-	if err != nil {
-		return nil, err
-	}
+	blockedUserIDs = s.withMutedUsers(ctx, blockedUserIDs, userID)
+	author := AuthorVisibility{ViewerID: userID, FollowingIDs: followingIDs}
 
-	// 5. Check hasMore
+	var anchorsList []anchors.Anchor
 	hasMore := false
-	if len(anchorsList) > query.Limit {
-		hasMore = true
-		anchorsList = anchorsList[:query.Limit]
+	var recommendations map[primitive.ObjectID]rankedAnchor
+
+	if query.Category == CategoryForYou && isAuthenticated {
+		// for_you ranks in memory and skips anchors already served, so the cursor only
+		// marks a follow-up page
+		page, more, err := s.getForYouAnchors(
+			ctx, *userID, append(excludeUserIDs, blockedUserIDs...), author, tagPtr, s.mutedTags(ctx, userID), query.Limit,
+		)
+		if err != nil {
+			return nil, err
+		}
+		hasMore = more
+		recommendations = make(map[primitive.ObjectID]rankedAnchor, len(page))
+		for _, r := range page {
+			anchorsList = append(anchorsList, r.Anchor)
+			recommendations[r.Anchor.ID] = r
+		}
+	} else {
+		anchorsList, err = s.feedRepo.GetDiscoverAnchors(
+			ctx, excludeUserIDs, blockedUserIDs, author, query.Category, tagPtr, s.mutedTags(ctx, userID), cursor, query.Limit+1,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// 5. Check hasMore
+		if len(anchorsList) > query.Limit {
+			hasMore = true
+			anchorsList = anchorsList[:query.Limit]
+		}
 	}
 
 	// 6. Handle empty results
@@ -658,12 +886,17 @@ func (s *Service) GetDiscoverFeed(
 			CloneCount:      anchor.CloneCount,
 			CommentCount:    anchor.CommentCount,
 			EngagementScore: anchor.EngagementScore,
+			HotScore:        anchor.HotScore,
 			LastItemAddedAt: anchor.LastItemAddedAt,
 			CreatedAt:       anchor.CreatedAt,
 			Author:          *author,
 			Engagement:      *engagement,
 			Preview:         *preview,
 		}
+		if r, ok := recommendations[anchor.ID]; ok {
+			items[i].Reason = r.Reason
+			items[i].ReasonTag = r.ReasonTag
+		}
 	}
 
 	// 11. Build next cursor
@@ -671,9 +904,12 @@ func (s *Service) GetDiscoverFeed(
 	if hasMore {
 		lastAnchor := anchorsList[len(anchorsList)-1]
 		var c string
-		if query.Category == CategoryRecent {
+		switch query.Category {
+		case CategoryRecent, CategoryForYou:
 			c = EncodeDiscoverCursor(nil, lastAnchor.CreatedAt, lastAnchor.ID)
-		} else {
+		case CategoryHot, CategoryRising:
+			c = EncodeHotCursor(lastAnchor.HotScore, lastAnchor.HotScoredAt, lastAnchor.CreatedAt, lastAnchor.ID)
+		default:
 			score := lastAnchor.EngagementScore
 			c = EncodeDiscoverCursor(&score, lastAnchor.CreatedAt, lastAnchor.ID)
 		}
@@ -44,8 +44,11 @@ func ValidateDiscoverQuery(query *DiscoverQuery) error {
 	}
 	if query.Category != CategoryTrending &&
 		query.Category != CategoryPopular &&
-		query.Category != CategoryRecent {
-		return errors.New("category must be: trending, popular, or recent")
+		query.Category != CategoryRecent &&
+		query.Category != CategoryHot &&
+		query.Category != CategoryRising &&
+		query.Category != CategoryForYou {
+		return errors.New("category must be: trending, popular, recent, hot, rising, or for_you")
 	}
 
 	// Normalize tag to lowercase
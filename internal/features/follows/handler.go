@@ -8,6 +8,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/notifications" // Already present, but good to confirm
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -17,22 +19,26 @@ type Handler struct {
 	repo                *Repository
 	authRepo            *auth.Repository
 	notificationService *notifications.Service // Added
+	webhookService      *webhooks.Service
+	blockPolicy         *safety.BlockPolicy
 	config              *config.Config
 }
 
 // NewHandler creates a new follow handler
-func NewHandler(repo *Repository, authRepo *auth.Repository, notificationService *notifications.Service, cfg *config.Config) *Handler { // Modified
+func NewHandler(repo *Repository, authRepo *auth.Repository, notificationService *notifications.Service, webhookService *webhooks.Service, blockPolicy *safety.BlockPolicy, cfg *config.Config) *Handler { // Modified
 	return &Handler{
 		repo:                repo,
 		authRepo:            authRepo,
 		notificationService: notificationService, // Added
+		webhookService:      webhookService,
+		blockPolicy:         blockPolicy,
 		config:              cfg,
 	}
 }
 
 // FollowAction godoc
 // @Summary Follow or unfollow user
-// @Description Follow or unfollow a user based on the action specified
+// @Description Follow or unfollow a user based on the action specified. Following a private account creates a pending follow request instead.
 // @Tags follows
 // @Accept json
 // @Produce json
@@ -87,35 +93,70 @@ func (h *Handler) FollowAction(c *gin.Context) {
 		return
 	}
 
-	var isFollowing bool
+	var isFollowing, isRequested bool
+
+	// Users who blocked each other cannot follow each other
+	if req.Action == "follow" && h.blockPolicy.IsBlocked(c.Request.Context(), currentUser.ID, targetID) {
+		response.Forbidden(c, "BLOCKED", "You cannot follow this user")
+		return
+	}
 
 	if req.Action == "follow" {
 		// Check if already following (to prevent duplicate notifications)
-		wasAlreadyFollowing, _ := h.repo.ExistsFollow(c.Request.Context(), currentUser.ID, targetID) // Changed targetUserID to targetID
-
-		err = h.repo.CreateFollow(c.Request.Context(), currentUser.ID, targetID) // Changed targetUserID to targetID
-		if err != nil {
-			response.InternalServerError(c, "FOLLOW_FAILED", "Failed to follow user")
-			return
-		}
-
-		// Only update counts and notify if this is a NEW follow
-		if !wasAlreadyFollowing {
-			_ = h.authRepo.IncrementFollowerCount(c.Request.Context(), targetID, 1) // Changed targetUserID to targetID
-			_ = h.authRepo.IncrementFollowingCount(c.Request.Context(), currentUser.ID, 1)
+		wasAlreadyFollowing, _ := h.repo.ExistsFollow(c.Request.Context(), currentUser.ID, targetID)
+
+		if targetUser.IsPrivate && !wasAlreadyFollowing {
+			// Private accounts approve followers - record a pending request instead
+			created, err := h.repo.CreateFollowRequest(c.Request.Context(), currentUser.ID, targetID)
+			if err != nil {
+				response.InternalServerError(c, "FOLLOW_FAILED", "Failed to request follow")
+				return
+			}
+
+			if created {
+				_ = h.notificationService.EnqueueFollowRequestNotification(
+					c.Request.Context(),
+					currentUser.ID,
+					targetID,
+				)
+			}
 
-			// Create notification (async)
-			go func() {
-				_ = h.notificationService.CreateFollowNotification(
-					context.Background(),
+			isRequested = true
+		} else {
+			err = h.repo.CreateFollow(c.Request.Context(), currentUser.ID, targetID)
+			if err != nil {
+				response.InternalServerError(c, "FOLLOW_FAILED", "Failed to follow user")
+				return
+			}
+
+			// Only update counts and notify if this is a NEW follow
+			if !wasAlreadyFollowing {
+				_ = h.authRepo.IncrementFollowerCount(c.Request.Context(), targetID, 1)
+				_ = h.authRepo.IncrementFollowingCount(c.Request.Context(), currentUser.ID, 1)
+
+				// A request left over from when the account was private is now moot
+				_, _ = h.repo.DeleteFollowRequest(c.Request.Context(), currentUser.ID, targetID)
+
+				// Queue notification and webhooks
+				_ = h.notificationService.EnqueueFollowNotification(
+					c.Request.Context(),
 					currentUser.ID,
-					targetID, // Changed targetUserID to targetID
+					targetID,
 				)
-			}()
-		}
+				h.publishFollowCreated(c.Request.Context(), currentUser, targetID)
+			}
 
-		isFollowing = true
+			isFollowing = true
+		}
 	} else {
+		// Unfollow also withdraws a pending request
+		if _, err := h.repo.DeleteFollowRequest(c.Request.Context(), currentUser.ID, targetID); err != nil {
+			response.InternalServerError(c, "UNFOLLOW_FAILED", "Failed to unfollow user")
+			return
+		}
+
+		wasFollowing, _ := h.repo.ExistsFollow(c.Request.Context(), currentUser.ID, targetID)
+
 		// Unfollow (idempotent)
 		err = h.repo.DeleteFollow(c.Request.Context(), currentUser.ID, targetID)
 		if err != nil {
@@ -123,9 +164,11 @@ func (h *Handler) FollowAction(c *gin.Context) {
 			return
 		}
 
-		// Decrement counts (idempotent - won't fail if already unfollowed)
-		_ = h.authRepo.IncrementFollowerCount(c.Request.Context(), targetID, -1)
-		_ = h.authRepo.IncrementFollowingCount(c.Request.Context(), currentUser.ID, -1)
+		// Only decrement counts if a follow was actually removed
+		if wasFollowing {
+			_ = h.authRepo.IncrementFollowerCount(c.Request.Context(), targetID, -1)
+			_ = h.authRepo.IncrementFollowingCount(c.Request.Context(), currentUser.ID, -1)
+		}
 
 		isFollowing = false
 	}
@@ -137,6 +180,7 @@ func (h *Handler) FollowAction(c *gin.Context) {
 	// Build response
 	resp := FollowActionResponse{
 		IsFollowing: isFollowing,
+		IsRequested: isRequested,
 		TargetUser: FollowTargetUserInfo{
 			ID:            targetUser.ID,
 			Username:      targetUser.Username,
@@ -194,10 +238,21 @@ func (h *Handler) GetFollowStatus(c *gin.Context) {
 		return
 	}
 
+	// A pending request only matters while not following yet
+	isRequested := false
+	if !isFollowing {
+		isRequested, err = h.repo.ExistsFollowRequest(c.Request.Context(), currentUser.ID, targetID)
+		if err != nil {
+			response.InternalServerError(c, "STATUS_CHECK_FAILED", "Failed to check follow status")
+			return
+		}
+	}
+
 	resp := FollowStatusResponse{
 		IsFollowing:  isFollowing,
 		IsFollowedBy: isFollowedBy,
 		IsMutual:     isFollowing && isFollowedBy,
+		IsRequested:  isRequested,
 	}
 
 	response.Success(c, resp)
@@ -340,3 +395,196 @@ func (h *Handler) ListFollows(c *gin.Context) {
 
 	response.Success(c, paginatedResponse)
 }
+
+// ListFollowRequests godoc
+// @Summary List incoming follow requests
+// @Description Get paginated list of pending requests to follow the current user
+// @Tags follows
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 20, max 50)"
+// @Success 200 {object} response.APIResponse{data=PaginatedFollowRequestResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /users/me/follow-requests [get]
+func (h *Handler) ListFollowRequests(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := user.(*auth.User)
+
+	var query FollowRequestListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "INVALID_QUERY", err.Error())
+		return
+	}
+
+	requests, total, err := h.repo.GetIncomingRequests(c.Request.Context(), currentUser.ID, query.Page, query.Limit)
+	if err != nil {
+		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch follow requests")
+		return
+	}
+
+	requesterIDs := make([]primitive.ObjectID, len(requests))
+	for i, request := range requests {
+		requesterIDs[i] = request.RequesterID
+	}
+
+	users, err := h.authRepo.GetUsersByIDs(c.Request.Context(), requesterIDs)
+	if err != nil {
+		response.InternalServerError(c, "FETCH_USERS_FAILED", "Failed to fetch user details")
+		return
+	}
+
+	userMap := make(map[primitive.ObjectID]*auth.User)
+	for i := range users {
+		userMap[users[i].ID] = &users[i]
+	}
+
+	items := []FollowRequestResponse{}
+	for _, request := range requests {
+		requester, exists := userMap[request.RequesterID]
+		if !exists {
+			continue
+		}
+
+		items = append(items, FollowRequestResponse{
+			ID:             request.ID,
+			RequesterID:    requester.ID,
+			Username:       requester.Username,
+			DisplayName:    requester.DisplayName,
+			ProfilePicture: &requester.ProfilePictureURL,
+			Bio:            requester.Bio,
+			RequestedAt:    request.CreatedAt,
+		})
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(query.Limit)))
+
+	paginatedResponse := PaginatedFollowRequestResponse{
+		Data: items,
+	}
+	paginatedResponse.Pagination.Page = query.Page
+	paginatedResponse.Pagination.Limit = query.Limit
+	paginatedResponse.Pagination.Total = total
+	paginatedResponse.Pagination.TotalPages = totalPages
+	paginatedResponse.Pagination.HasMore = query.Page < totalPages
+
+	response.Success(c, paginatedResponse)
+}
+
+// ApproveFollowRequest godoc
+// @Summary Approve a follow request
+// @Description Accept a pending follow request; the requester becomes a follower
+// @Tags follows
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Follow request ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /users/me/follow-requests/{id}/approve [post]
+func (h *Handler) ApproveFollowRequest(c *gin.Context) {
+	currentUser, request, ok := h.loadIncomingRequest(c)
+	if !ok {
+		return
+	}
+
+	wasAlreadyFollowing, _ := h.repo.ExistsFollow(c.Request.Context(), request.RequesterID, currentUser.ID)
+
+	if err := h.repo.CreateFollow(c.Request.Context(), request.RequesterID, currentUser.ID); err != nil {
+		response.InternalServerError(c, "APPROVE_FAILED", "Failed to approve follow request")
+		return
+	}
+
+	// Only the call that removes the request updates counts, so concurrent approvals count once
+	deleted, err := h.repo.DeleteFollowRequest(c.Request.Context(), request.RequesterID, currentUser.ID)
+	if err != nil {
+		response.InternalServerError(c, "APPROVE_FAILED", "Failed to approve follow request")
+		return
+	}
+
+	if deleted && !wasAlreadyFollowing {
+		_ = h.authRepo.IncrementFollowerCount(c.Request.Context(), currentUser.ID, 1)
+		_ = h.authRepo.IncrementFollowingCount(c.Request.Context(), request.RequesterID, 1)
+
+		_ = h.notificationService.EnqueueFollowAcceptNotification(
+			c.Request.Context(),
+			currentUser.ID,
+			request.RequesterID,
+		)
+
+		if requester, err := h.authRepo.GetUserByObjectID(c.Request.Context(), request.RequesterID); err == nil && requester != nil {
+			h.publishFollowCreated(c.Request.Context(), requester, currentUser.ID)
+		}
+	}
+
+	response.Success(c, nil, "Follow request approved")
+}
+
+// RejectFollowRequest godoc
+// @Summary Reject a follow request
+// @Description Decline a pending follow request. The requester is not notified.
+// @Tags follows
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Follow request ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /users/me/follow-requests/{id}/reject [post]
+func (h *Handler) RejectFollowRequest(c *gin.Context) {
+	currentUser, request, ok := h.loadIncomingRequest(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.DeleteFollowRequest(c.Request.Context(), request.RequesterID, currentUser.ID); err != nil {
+		response.InternalServerError(c, "REJECT_FAILED", "Failed to reject follow request")
+		return
+	}
+
+	response.Success(c, nil, "Follow request rejected")
+}
+
+// loadIncomingRequest resolves the follow request in the path, which must be addressed to the current user.
+// Writes the error response and returns false if it cannot.
+func (h *Handler) loadIncomingRequest(c *gin.Context) (*auth.User, *FollowRequest, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return nil, nil, false
+	}
+	currentUser := user.(*auth.User)
+
+	requestID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "Invalid request ID format")
+		return nil, nil, false
+	}
+
+	request, err := h.repo.GetFollowRequest(c.Request.Context(), requestID)
+	if err != nil {
+		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch follow request")
+		return nil, nil, false
+	}
+	if request == nil || request.TargetID != currentUser.ID {
+		response.NotFound(c, "REQUEST_NOT_FOUND", "Follow request not found")
+		return nil, nil, false
+	}
+
+	return currentUser, request, true
+}
+
+// publishFollowCreated tells the followed user's webhooks about a new follower
+func (h *Handler) publishFollowCreated(ctx context.Context, follower *auth.User, followingID primitive.ObjectID) {
+	_ = h.webhookService.Publish(ctx, followingID, webhooks.EventFollowCreated, webhooks.FollowCreatedData{
+		FollowingID: followingID,
+		Actor:       webhooks.Actor{ID: follower.ID, Username: follower.Username},
+	})
+}
@@ -14,6 +14,14 @@ type Follow struct {
 	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
 }
 
+// FollowRequest is a pending request to follow a private account
+type FollowRequest struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RequesterID primitive.ObjectID `bson:"requesterId" json:"requesterId"` // User asking to follow
+	TargetID    primitive.ObjectID `bson:"targetId" json:"targetId"`       // Private account being asked
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
 // FollowActionRequest for POST /users/:id/follow
 type FollowActionRequest struct {
 	Action string `json:"action" binding:"required,oneof=follow unfollow"`
@@ -29,6 +37,7 @@ type FollowListQuery struct {
 // FollowActionResponse after follow/unfollow
 type FollowActionResponse struct {
 	IsFollowing bool                  `json:"isFollowing"`
+	IsRequested bool                  `json:"isRequested"` // A follow request is pending approval
 	TargetUser  FollowTargetUserInfo  `json:"targetUser"`
 	CurrentUser FollowCurrentUserInfo `json:"currentUser"`
 }
@@ -51,6 +60,7 @@ type FollowStatusResponse struct {
 	IsFollowing  bool `json:"isFollowing"`
 	IsFollowedBy bool `json:"isFollowedBy"`
 	IsMutual     bool `json:"isMutual"`
+	IsRequested  bool `json:"isRequested"`
 }
 
 // FollowUserResponse for items in followers/following list
@@ -75,3 +85,39 @@ type PaginatedFollowResponse struct {
 		HasMore    bool  `json:"hasMore"`
 	} `json:"pagination"`
 }
+
+// FollowRequestListQuery for GET /users/me/follow-requests
+type FollowRequestListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// FollowRequestResponse for items in the incoming follow request list
+type FollowRequestResponse struct {
+	ID             primitive.ObjectID `json:"id"`
+	RequesterID    primitive.ObjectID `json:"requesterId"`
+	Username       string             `json:"username"`
+	DisplayName    string             `json:"displayName"`
+	ProfilePicture *string            `json:"profilePicture"`
+	Bio            string             `json:"bio"`
+	RequestedAt    time.Time          `json:"requestedAt"`
+}
+
+// PaginatedFollowRequestResponse represents a paginated follow request list
+type PaginatedFollowRequestResponse struct {
+	Data       []FollowRequestResponse `json:"data"`
+	Pagination struct {
+		Page       int   `json:"page"`
+		Limit      int   `json:"limit"`
+		Total      int64 `json:"total"`
+		TotalPages int   `json:"totalPages"`
+		HasMore    bool  `json:"hasMore"`
+	} `json:"pagination"`
+}
+
+// MutualFollowCount is a user followed by some of the accounts another user follows
+type MutualFollowCount struct {
+	UserID     primitive.ObjectID   `bson:"_id"`
+	Count      int                  `bson:"count"`
+	FollowedBy []primitive.ObjectID `bson:"followedBy"` // Sample of the mutual follows, newest first
+}
@@ -2,6 +2,7 @@ package follows
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,7 +13,8 @@ import (
 
 // Repository handles database interactions for the follows feature
 type Repository struct {
-	collection *mongo.Collection
+	collection         *mongo.Collection
+	requestsCollection *mongo.Collection
 }
 
 // NewRepository creates repository and ensures indexes
@@ -45,8 +47,29 @@ func NewRepository(db *mongo.Database) *Repository {
 		},
 	})
 
+	requestsCollection := db.Collection("follow_requests")
+
+	_, _ = requestsCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// One pending request per requester/target pair
+			Keys: bson.D{
+				{Key: "requesterId", Value: 1},
+				{Key: "targetId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Incoming requests of a user (sorted by newest first)
+			Keys: bson.D{
+				{Key: "targetId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+	})
+
 	return &Repository{
-		collection: collection,
+		collection:         collection,
+		requestsCollection: requestsCollection,
 	}
 }
 
@@ -258,3 +281,262 @@ func (r *Repository) GetFollowingStatus(ctx context.Context, followerID primitiv
 
 	return result, nil
 }
+
+// GetAllFollowerIDs returns all user IDs that follow the given user
+func (r *Repository) GetAllFollowerIDs(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter := bson.M{"followingId": userID}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"followerId": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var follows []Follow
+	if err = cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(follows))
+	for i, f := range follows {
+		ids[i] = f.FollowerID
+	}
+
+	return ids, nil
+}
+
+// DeleteAllForUser removes every follow relationship and follow request involving a user, in both directions
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{
+		"$or": []bson.M{
+			{"followerId": userID},
+			{"followingId": userID},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.requestsCollection.DeleteMany(ctx, bson.M{
+		"$or": []bson.M{
+			{"requesterId": userID},
+			{"targetId": userID},
+		},
+	})
+	return err
+}
+
+// DeleteFollowsBetween removes follows and pending follow requests between two users in both directions.
+// Reports which follows existed so counters can be adjusted.
+func (r *Repository) DeleteFollowsBetween(ctx context.Context, userA, userB primitive.ObjectID) (aFollowedB bool, bFollowedA bool, err error) {
+	if aFollowedB, err = r.deleteFollow(ctx, userA, userB); err != nil {
+		return false, false, err
+	}
+	if bFollowedA, err = r.deleteFollow(ctx, userB, userA); err != nil {
+		return false, false, err
+	}
+
+	_, err = r.requestsCollection.DeleteMany(ctx, bson.M{
+		"$or": []bson.M{
+			{"requesterId": userA, "targetId": userB},
+			{"requesterId": userB, "targetId": userA},
+		},
+	})
+	return aFollowedB, bFollowedA, err
+}
+
+func (r *Repository) deleteFollow(ctx context.Context, followerID, followingID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{
+		"followerId":  followerID,
+		"followingId": followingID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// CountFollowers counts the users following a user
+func (r *Repository) CountFollowers(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"followingId": userID})
+}
+
+// CountFollowing counts the users a user follows
+func (r *Repository) CountFollowing(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"followerId": userID})
+}
+
+// GetAllFollowing returns every follow the user has made, newest first (for data export)
+func (r *Repository) GetAllFollowing(ctx context.Context, userID primitive.ObjectID) ([]Follow, error) {
+	return r.findAll(ctx, bson.M{"followerId": userID})
+}
+
+// GetAllFollowers returns every follow of the user, newest first (for data export)
+func (r *Repository) GetAllFollowers(ctx context.Context, userID primitive.ObjectID) ([]Follow, error) {
+	return r.findAll(ctx, bson.M{"followingId": userID})
+}
+
+func (r *Repository) findAll(ctx context.Context, filter bson.M) ([]Follow, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	follows := []Follow{}
+	if err = cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+// CreateFollowRequest records a pending request to follow a private account.
+// Returns true if a new request was created.
+func (r *Repository) CreateFollowRequest(ctx context.Context, requesterID, targetID primitive.ObjectID) (bool, error) {
+	request := &FollowRequest{
+		ID:          primitive.NewObjectID(),
+		RequesterID: requesterID,
+		TargetID:    targetID,
+		CreatedAt:   time.Now(),
+	}
+
+	_, err := r.requestsCollection.InsertOne(ctx, request)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil // Idempotent - already requested
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetFollowRequest retrieves a follow request by ID, nil if it does not exist
+func (r *Repository) GetFollowRequest(ctx context.Context, requestID primitive.ObjectID) (*FollowRequest, error) {
+	var request FollowRequest
+	err := r.requestsCollection.FindOne(ctx, bson.M{"_id": requestID}).Decode(&request)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// DeleteFollowRequest removes a pending request. Returns true if one existed.
+func (r *Repository) DeleteFollowRequest(ctx context.Context, requesterID, targetID primitive.ObjectID) (bool, error) {
+	result, err := r.requestsCollection.DeleteOne(ctx, bson.M{
+		"requesterId": requesterID,
+		"targetId":    targetID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// ExistsFollowRequest checks if a request from requester to target is pending
+func (r *Repository) ExistsFollowRequest(ctx context.Context, requesterID, targetID primitive.ObjectID) (bool, error) {
+	count, err := r.requestsCollection.CountDocuments(ctx, bson.M{
+		"requesterId": requesterID,
+		"targetId":    targetID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetIncomingRequests retrieves pending requests to follow a user with pagination
+func (r *Repository) GetIncomingRequests(ctx context.Context, targetID primitive.ObjectID, page, limit int) ([]FollowRequest, int64, error) {
+	filter := bson.M{"targetId": targetID}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.requestsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var requests []FollowRequest
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.requestsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return requests, total, nil
+}
+
+// GetRequestedTargetIDs returns the users a requester has pending follow requests to
+func (r *Repository) GetRequestedTargetIDs(ctx context.Context, requesterID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cursor, err := r.requestsCollection.Find(ctx, bson.M{"requesterId": requesterID}, options.Find().SetProjection(bson.M{"targetId": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var requests []FollowRequest
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(requests))
+	for i, req := range requests {
+		ids[i] = req.TargetID
+	}
+
+	return ids, nil
+}
+
+// GetMostFollowedBy ranks the users followed by followerIDs by how many of them follow each one,
+// skipping excludeIDs. Used for friends-of-friends suggestions.
+func (r *Repository) GetMostFollowedBy(ctx context.Context, followerIDs, excludeIDs []primitive.ObjectID, limit int) ([]MutualFollowCount, error) {
+	if len(followerIDs) == 0 {
+		return []MutualFollowCount{}, nil
+	}
+	if excludeIDs == nil {
+		excludeIDs = []primitive.ObjectID{}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"followerId":  bson.M{"$in": followerIDs},
+			"followingId": bson.M{"$nin": excludeIDs},
+		}}},
+		{{Key: "$sort", Value: bson.M{"createdAt": -1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$followingId",
+			"count":      bson.M{"$sum": 1},
+			"followedBy": bson.M{"$push": "$followerId"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.M{
+			"count":      1,
+			"followedBy": bson.M{"$slice": bson.A{"$followedBy", 3}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []MutualFollowCount{}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
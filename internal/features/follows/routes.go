@@ -5,6 +5,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -17,7 +19,7 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	notificationService := notifications.GetService(db)
 
 	// Initialize handler
-	handler := NewHandler(repo, authRepo, notificationService, cfg)
+	handler := NewHandler(repo, authRepo, notificationService, webhooks.NewService(db, cfg), safety.NewBlockPolicy(db), cfg)
 
 	// Initialize middlewares
 	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
@@ -30,6 +32,11 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 		users.POST("/:id/follow", authMiddleware, handler.FollowAction)
 		users.GET("/:id/follow/status", authMiddleware, handler.GetFollowStatus)
 
+		// Follow requests for private accounts
+		users.GET("/me/follow-requests", authMiddleware, handler.ListFollowRequests)
+		users.POST("/me/follow-requests/:id/approve", authMiddleware, handler.ApproveFollowRequest)
+		users.POST("/me/follow-requests/:id/reject", authMiddleware, handler.RejectFollowRequest)
+
 		// Public route with optional auth
 		users.GET("/:id/follows", optionalAuth, handler.ListFollows)
 	}
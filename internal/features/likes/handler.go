@@ -12,6 +12,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -22,18 +24,22 @@ type Handler struct {
 	anchorsRepo         *anchors.Repository
 	authRepo            *auth.Repository
 	notificationService *notifications.Service
+	webhookService      *webhooks.Service
 	followsRepo         *follows.Repository
+	blockPolicy         *safety.BlockPolicy
 	config              *config.Config
 }
 
 // NewHandler creates a new like handler
-func NewHandler(repo *Repository, anchorsRepo *anchors.Repository, authRepo *auth.Repository, notificationService *notifications.Service, followsRepo *follows.Repository, cfg *config.Config) *Handler {
+func NewHandler(repo *Repository, anchorsRepo *anchors.Repository, authRepo *auth.Repository, notificationService *notifications.Service, webhookService *webhooks.Service, followsRepo *follows.Repository, blockPolicy *safety.BlockPolicy, cfg *config.Config) *Handler {
 	return &Handler{
 		repo:                repo,
 		anchorsRepo:         anchorsRepo,
 		authRepo:            authRepo,
 		notificationService: notificationService,
+		webhookService:      webhookService,
 		followsRepo:         followsRepo,
+		blockPolicy:         blockPolicy,
 		config:              cfg,
 	}
 }
@@ -104,6 +110,12 @@ func (h *Handler) LikeAction(c *gin.Context) {
 		}
 	}
 
+	// Users who blocked each other cannot like each other's anchors
+	if req.Action == "like" && h.blockPolicy.IsBlocked(c.Request.Context(), currentUser.ID, anchor.UserID) {
+		response.Forbidden(c, "BLOCKED", "You cannot like this anchor")
+		return
+	}
+
 	var hasLiked bool
 
 	if req.Action == "like" {
@@ -120,17 +132,20 @@ func (h *Handler) LikeAction(c *gin.Context) {
 		if !wasAlreadyLiked {
 			_ = h.anchorsRepo.IncrementLikeCount(c.Request.Context(), anchorID, 1)
 
-			// Create notification (async) - only for NEW likes
+			// Queue notification and webhooks - only for NEW likes
 			if anchor.UserID != currentUser.ID {
-				go func() {
-					_ = h.notificationService.CreateLikeNotification(
-						context.Background(),
-						anchorID,
-						anchor.Title,
-						currentUser.ID,
-						anchor.UserID,
-					)
-				}()
+				_ = h.notificationService.EnqueueLikeNotification(
+					c.Request.Context(),
+					anchorID,
+					anchor.Title,
+					currentUser.ID,
+					anchor.UserID,
+				)
+				_ = h.webhookService.Publish(c.Request.Context(), anchor.UserID, webhooks.EventAnchorLiked, webhooks.AnchorLikedData{
+					AnchorID:    anchorID,
+					AnchorTitle: anchor.Title,
+					Actor:       webhooks.Actor{ID: currentUser.ID, Username: currentUser.Username},
+				})
 			}
 		}
 
@@ -214,3 +214,60 @@ func (r *Repository) GetUserLikedAnchorsPaginated(ctx context.Context, userID pr
 
 	return likes, total, nil
 }
+
+// GetLikedAnchorIDs returns the IDs of every anchor a user has liked
+func (r *Repository) GetLikedAnchorIDs(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, options.Find().SetProjection(bson.M{"anchorId": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var likes []Like
+	if err = cursor.All(ctx, &likes); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(likes))
+	for i, like := range likes {
+		ids[i] = like.AnchorID
+	}
+
+	return ids, nil
+}
+
+// DeleteAllByUser removes every like made by a user
+func (r *Repository) DeleteAllByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
+
+// DeleteByAnchors removes every like on the given anchors
+func (r *Repository) DeleteByAnchors(ctx context.Context, anchorIDs []primitive.ObjectID) error {
+	if len(anchorIDs) == 0 {
+		return nil
+	}
+	_, err := r.collection.DeleteMany(ctx, bson.M{"anchorId": bson.M{"$in": anchorIDs}})
+	return err
+}
+
+// CountLikes counts the likes on an anchor
+func (r *Repository) CountLikes(ctx context.Context, anchorID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"anchorId": anchorID})
+}
+
+// GetAllByUser returns every anchor like made by a user, newest first (for data export)
+func (r *Repository) GetAllByUser(ctx context.Context, userID primitive.ObjectID) ([]Like, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	likes := []Like{}
+	if err = cursor.All(ctx, &likes); err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -21,7 +23,7 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	notificationService := notifications.GetService(db)
 
 	// Initialize handler
-	handler := NewHandler(repo, anchorsRepo, authRepo, notificationService, followsRepo, cfg)
+	handler := NewHandler(repo, anchorsRepo, authRepo, notificationService, webhooks.NewService(db, cfg), followsRepo, safety.NewBlockPolicy(db), cfg)
 
 	// Initialize middlewares
 	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
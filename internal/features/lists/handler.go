@@ -0,0 +1,491 @@
+package lists
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Handler struct {
+	repo        *Repository
+	authRepo    *auth.Repository
+	blockPolicy *safety.BlockPolicy
+}
+
+func NewHandler(repo *Repository, authRepo *auth.Repository, blockPolicy *safety.BlockPolicy) *Handler {
+	return &Handler{
+		repo:        repo,
+		authRepo:    authRepo,
+		blockPolicy: blockPolicy,
+	}
+}
+
+// CreateList godoc
+// @Summary Create a list
+// @Description Create a named group of accounts with its own timeline. Private lists are visible only to you.
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateListRequest true "List details"
+// @Success 201 {object} response.APIResponse{data=ListResponse}
+// @Failure 400 {object} response.APIResponse
+// @Router /lists [post]
+func (h *Handler) CreateList(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req CreateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		response.BadRequest(c, "List name is required", "VALIDATION_FAILED")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	count, err := h.repo.CountUserLists(ctx, user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to create list", "DATABASE_ERROR")
+		return
+	}
+	if count >= MaxListsPerUser {
+		response.BadRequest(c, "You can have up to 50 lists", "LIST_LIMIT_REACHED")
+		return
+	}
+
+	list := &List{
+		UserID:      user.ID,
+		Name:        req.Name,
+		Description: strings.TrimSpace(req.Description),
+		IsPrivate:   req.IsPrivate,
+	}
+	if err := h.repo.CreateList(ctx, list); err != nil {
+		response.InternalServerError(c, "Failed to create list", "DATABASE_ERROR")
+		return
+	}
+
+	response.Created(c, buildListResponse(list, nil), "List created")
+}
+
+// GetUserLists godoc
+// @Summary Get a user's lists
+// @Description Get the lists a user owns. Private lists are included only for their owner.
+// @Tags lists
+// @Produce json
+// @Param id path string true "User ID or 'me'"
+// @Success 200 {object} response.APIResponse{data=ListsResponse}
+// @Failure 404 {object} response.APIResponse
+// @Router /users/{id}/lists [get]
+func (h *Handler) GetUserLists(c *gin.Context) {
+	viewerID := viewerIDFrom(c)
+
+	idStr := c.Param("id")
+	if idStr == "me" {
+		if viewerID == nil {
+			response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+			return
+		}
+		idStr = viewerID.Hex()
+	}
+
+	ownerID, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	owner, err := h.authRepo.GetUserByObjectID(ctx, ownerID)
+	isOwner := viewerID != nil && *viewerID == ownerID
+	if err != nil || owner == nil || (!isOwner && owner.IsDeactivated()) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+	if viewerID != nil && h.blockPolicy.IsBlocked(ctx, *viewerID, ownerID) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	userLists, err := h.repo.GetUserLists(ctx, ownerID, isOwner)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch lists", "DATABASE_ERROR")
+		return
+	}
+
+	ownerInfo := buildOwner(owner)
+	resp := ListsResponse{Lists: make([]ListResponse, len(userLists))}
+	for i := range userLists {
+		resp.Lists[i] = buildListResponse(&userLists[i], ownerInfo)
+	}
+
+	response.Success(c, resp)
+}
+
+// GetList godoc
+// @Summary Get a list
+// @Tags lists
+// @Produce json
+// @Param id path string true "List ID"
+// @Success 200 {object} response.APIResponse{data=ListResponse}
+// @Failure 404 {object} response.APIResponse
+// @Router /lists/{id} [get]
+func (h *Handler) GetList(c *gin.Context) {
+	list, ok := h.visibleList(c)
+	if !ok {
+		return
+	}
+
+	var ownerInfo *ListOwner
+	if owner, err := h.authRepo.GetUserByObjectID(c.Request.Context(), list.UserID); err == nil && owner != nil {
+		ownerInfo = buildOwner(owner)
+	}
+
+	response.Success(c, buildListResponse(list, ownerInfo))
+}
+
+// UpdateList godoc
+// @Summary Update a list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "List ID"
+// @Param request body UpdateListRequest true "Fields to update"
+// @Success 200 {object} response.APIResponse{data=ListResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /lists/{id} [patch]
+func (h *Handler) UpdateList(c *gin.Context) {
+	list, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+
+	updates := bson.M{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			response.BadRequest(c, "List name is required", "VALIDATION_FAILED")
+			return
+		}
+		updates["name"] = name
+		list.Name = name
+	}
+	if req.Description != nil {
+		list.Description = strings.TrimSpace(*req.Description)
+		updates["description"] = list.Description
+	}
+	if req.IsPrivate != nil {
+		list.IsPrivate = *req.IsPrivate
+		updates["isPrivate"] = list.IsPrivate
+	}
+
+	if len(updates) > 0 {
+		if err := h.repo.UpdateList(c.Request.Context(), list.ID, updates); err != nil {
+			response.InternalServerError(c, "Failed to update list", "DATABASE_ERROR")
+			return
+		}
+	}
+
+	response.Success(c, buildListResponse(list, nil), "List updated")
+}
+
+// DeleteList godoc
+// @Summary Delete a list
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "List ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /lists/{id} [delete]
+func (h *Handler) DeleteList(c *gin.Context) {
+	list, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteList(c.Request.Context(), list.ID); err != nil {
+		response.InternalServerError(c, "Failed to delete list", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, nil, "List deleted")
+}
+
+// AddMember godoc
+// @Summary Add an account to a list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "List ID"
+// @Param request body AddMemberRequest true "Account to add"
+// @Success 200 {object} response.APIResponse{data=ListResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /lists/{id}/members [post]
+func (h *Handler) AddMember(c *gin.Context) {
+	list, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	var req AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+	memberID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	member, err := h.authRepo.GetUserByObjectID(ctx, memberID)
+	if err != nil || member == nil || member.IsDeactivated() || h.blockPolicy.IsBlocked(ctx, list.UserID, memberID) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	added, err := h.repo.AddMember(ctx, list.ID, memberID)
+	if err != nil {
+		if errors.Is(err, ErrListFull) {
+			response.BadRequest(c, "A list can have up to 500 accounts", "LIST_FULL")
+			return
+		}
+		response.InternalServerError(c, "Failed to add account", "DATABASE_ERROR")
+		return
+	}
+	if added {
+		list.MemberCount++
+	}
+
+	response.Success(c, buildListResponse(list, nil), "Account added to list")
+}
+
+// RemoveMember godoc
+// @Summary Remove an account from a list
+// @Tags lists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "List ID"
+// @Param userId path string true "Account to remove"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /lists/{id}/members/{userId} [delete]
+func (h *Handler) RemoveMember(c *gin.Context) {
+	list, ok := h.ownedList(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+
+	if err := h.repo.RemoveMember(c.Request.Context(), list.ID, memberID); err != nil {
+		response.InternalServerError(c, "Failed to remove account", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, nil, "Account removed from list")
+}
+
+// GetMembers godoc
+// @Summary Get list members
+// @Tags lists
+// @Produce json
+// @Param id path string true "List ID"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 20, max 50)"
+// @Success 200 {object} response.APIResponse{data=ListMembersResponse}
+// @Failure 404 {object} response.APIResponse
+// @Router /lists/{id}/members [get]
+func (h *Handler) GetMembers(c *gin.Context) {
+	list, ok := h.visibleList(c)
+	if !ok {
+		return
+	}
+
+	var query ListMembersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "Invalid query parameters", "INVALID_QUERY")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Newest members first
+	memberIDs := make([]primitive.ObjectID, len(list.MemberIDs))
+	for i, id := range list.MemberIDs {
+		memberIDs[len(memberIDs)-1-i] = id
+	}
+
+	// Hide accounts blocked with the viewer
+	if viewerID := viewerIDFrom(c); viewerID != nil {
+		memberIDs = h.blockPolicy.FilterBlocked(ctx, *viewerID, memberIDs)
+	}
+
+	total := len(memberIDs)
+	start := (query.Page - 1) * query.Limit
+	end := start + query.Limit
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	pageIDs := memberIDs[start:end]
+
+	usersMap := make(map[primitive.ObjectID]*auth.User)
+	if len(pageIDs) > 0 {
+		users, err := h.authRepo.GetUsersByIDs(ctx, pageIDs)
+		if err != nil {
+			response.InternalServerError(c, "Failed to fetch members", "DATABASE_ERROR")
+			return
+		}
+		for i := range users {
+			usersMap[users[i].ID] = &users[i]
+		}
+	}
+
+	resp := ListMembersResponse{Members: []ListMemberResponse{}}
+	for _, id := range pageIDs {
+		u, exists := usersMap[id]
+		if !exists || u.IsDeactivated() {
+			continue
+		}
+		resp.Members = append(resp.Members, ListMemberResponse{
+			ID:             u.ID,
+			Username:       u.Username,
+			DisplayName:    u.DisplayName,
+			ProfilePicture: &u.ProfilePictureURL,
+			IsVerified:     u.IsVerified,
+		})
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(query.Limit)))
+	resp.Pagination.Page = query.Page
+	resp.Pagination.Limit = query.Limit
+	resp.Pagination.Total = total
+	resp.Pagination.TotalPages = totalPages
+	resp.Pagination.HasMore = query.Page < totalPages
+
+	response.Success(c, resp)
+}
+
+// visibleList loads the list in the path if the viewer may see it, writing an error response otherwise
+func (h *Handler) visibleList(c *gin.Context) (*List, bool) {
+	listID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid list ID", "INVALID_ID")
+		return nil, false
+	}
+
+	ctx := c.Request.Context()
+	viewerID := viewerIDFrom(c)
+
+	list, err := h.repo.GetListByID(ctx, listID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch list", "DATABASE_ERROR")
+		return nil, false
+	}
+	if list == nil || !list.IsVisibleTo(viewerID) ||
+		(viewerID != nil && h.blockPolicy.IsBlocked(ctx, *viewerID, list.UserID)) {
+		response.NotFound(c, "List not found", "LIST_NOT_FOUND")
+		return nil, false
+	}
+	return list, true
+}
+
+// ownedList loads the list in the path if the current user owns it, writing an error response otherwise
+func (h *Handler) ownedList(c *gin.Context) (*List, bool) {
+	user, ok := currentUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	list, ok := h.visibleList(c)
+	if !ok {
+		return nil, false
+	}
+	if list.UserID != user.ID {
+		response.Forbidden(c, "You can only change your own lists", "FORBIDDEN")
+		return nil, false
+	}
+	return list, true
+}
+
+func buildListResponse(list *List, owner *ListOwner) ListResponse {
+	return ListResponse{
+		ID:          list.ID,
+		Name:        list.Name,
+		Description: list.Description,
+		IsPrivate:   list.IsPrivate,
+		MemberCount: list.MemberCount,
+		Owner:       owner,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
+
+func buildOwner(user *auth.User) *ListOwner {
+	return &ListOwner{
+		ID:             user.ID,
+		Username:       user.Username,
+		DisplayName:    user.DisplayName,
+		ProfilePicture: &user.ProfilePictureURL,
+	}
+}
+
+// viewerIDFrom returns the authenticated user's ID, nil for anonymous requests
+func viewerIDFrom(c *gin.Context) *primitive.ObjectID {
+	if val, exists := c.Get("user"); exists {
+		if user, ok := val.(*auth.User); ok {
+			return &user.ID
+		}
+	}
+	return nil
+}
+
+// currentUser returns the authenticated user, writing an error response if there is none
+func currentUser(c *gin.Context) (*auth.User, bool) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return nil, false
+	}
+	user, ok := val.(*auth.User)
+	if !ok {
+		response.InternalServerError(c, "User context error", "INTERNAL_ERROR")
+		return nil, false
+	}
+	return user, true
+}
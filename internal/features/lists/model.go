@@ -0,0 +1,105 @@
+package lists
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Limits
+const (
+	MaxListsPerUser   = 50
+	MaxMembersPerList = 500
+)
+
+// List is a user-owned, named group of accounts with its own timeline
+type List struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID   `bson:"userId" json:"userId"` // Owner
+	Name        string               `bson:"name" json:"name"`
+	Description string               `bson:"description" json:"description"`
+	IsPrivate   bool                 `bson:"isPrivate" json:"isPrivate"` // Private lists are visible only to their owner
+	MemberIDs   []primitive.ObjectID `bson:"memberIds" json:"-"`
+	MemberCount int                  `bson:"memberCount" json:"memberCount"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// IsVisibleTo reports whether the viewer may see the list and its timeline
+func (l *List) IsVisibleTo(viewerID *primitive.ObjectID) bool {
+	if !l.IsPrivate {
+		return true
+	}
+	return viewerID != nil && *viewerID == l.UserID
+}
+
+// CreateListRequest for POST /lists
+type CreateListRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=50"`
+	Description string `json:"description" binding:"max=200"`
+	IsPrivate   bool   `json:"isPrivate"`
+}
+
+// UpdateListRequest for PATCH /lists/:id
+type UpdateListRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Description *string `json:"description" binding:"omitempty,max=200"`
+	IsPrivate   *bool   `json:"isPrivate"`
+}
+
+// AddMemberRequest for POST /lists/:id/members
+type AddMemberRequest struct {
+	UserID string `json:"userId" binding:"required"`
+}
+
+// ListMembersQuery for GET /lists/:id/members
+type ListMembersQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// ListOwner is the owner summary on a list
+type ListOwner struct {
+	ID             primitive.ObjectID `json:"id"`
+	Username       string             `json:"username"`
+	DisplayName    string             `json:"displayName"`
+	ProfilePicture *string            `json:"profilePicture"`
+}
+
+// ListResponse for a single list
+type ListResponse struct {
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	IsPrivate   bool               `json:"isPrivate"`
+	MemberCount int                `json:"memberCount"`
+	Owner       *ListOwner         `json:"owner,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+// ListsResponse for GET /users/me/lists and GET /users/:id/lists
+type ListsResponse struct {
+	Lists []ListResponse `json:"lists"`
+}
+
+// ListMemberResponse for items in the members list
+type ListMemberResponse struct {
+	ID             primitive.ObjectID `json:"id"`
+	Username       string             `json:"username"`
+	DisplayName    string             `json:"displayName"`
+	ProfilePicture *string            `json:"profilePicture"`
+	IsVerified     bool               `json:"isVerified"`
+}
+
+// ListMembersResponse for GET /lists/:id/members
+type ListMembersResponse struct {
+	Members    []ListMemberResponse `json:"members"`
+	Pagination struct {
+		Page       int  `json:"page"`
+		Limit      int  `json:"limit"`
+		Total      int  `json:"total"`
+		TotalPages int  `json:"totalPages"`
+		HasMore    bool `json:"hasMore"`
+	} `json:"pagination"`
+}
@@ -0,0 +1,170 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrListFull is returned when a list already has MaxMembersPerList members
+var ErrListFull = errors.New("list has reached the member limit")
+
+// Repository handles database interactions for user lists
+type Repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates repository and ensures indexes
+func NewRepository(db *mongo.Database) *Repository {
+	collection := db.Collection("lists")
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// A user's lists, newest first
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			// Lists a user is a member of (account purge)
+			Keys: bson.D{{Key: "memberIds", Value: 1}},
+		},
+	})
+
+	return &Repository{
+		collection: collection,
+	}
+}
+
+// CreateList inserts a new empty list
+func (r *Repository) CreateList(ctx context.Context, list *List) error {
+	now := time.Now()
+	list.ID = primitive.NewObjectID()
+	list.MemberIDs = []primitive.ObjectID{}
+	list.MemberCount = 0
+	list.CreatedAt = now
+	list.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, list)
+	return err
+}
+
+// GetListByID retrieves a list, nil if it does not exist
+func (r *Repository) GetListByID(ctx context.Context, listID primitive.ObjectID) (*List, error) {
+	var list List
+	err := r.collection.FindOne(ctx, bson.M{"_id": listID}).Decode(&list)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetUserLists returns a user's lists, newest first, optionally including private ones
+func (r *Repository) GetUserLists(ctx context.Context, userID primitive.ObjectID, includePrivate bool) ([]List, error) {
+	filter := bson.M{"userId": userID}
+	if !includePrivate {
+		filter["isPrivate"] = false
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lists := []List{}
+	if err = cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// CountUserLists returns how many lists a user owns
+func (r *Repository) CountUserLists(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"userId": userID})
+}
+
+// UpdateList sets fields on a list
+func (r *Repository) UpdateList(ctx context.Context, listID primitive.ObjectID, updates bson.M) error {
+	updates["updatedAt"] = time.Now()
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": listID}, bson.M{"$set": updates})
+	return err
+}
+
+// DeleteList removes a list
+func (r *Repository) DeleteList(ctx context.Context, listID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": listID})
+	return err
+}
+
+// AddMember adds a user to a list (idempotent). The boolean reports whether the user was added.
+func (r *Repository) AddMember(ctx context.Context, listID, memberID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":         listID,
+			"memberIds":   bson.M{"$ne": memberID},
+			"memberCount": bson.M{"$lt": MaxMembersPerList},
+		},
+		bson.M{
+			"$push": bson.M{"memberIds": memberID},
+			"$inc":  bson.M{"memberCount": 1},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.ModifiedCount > 0 {
+		return true, nil
+	}
+
+	// Nothing changed: either already a member or the list is full
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": listID, "memberIds": memberID})
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, ErrListFull
+	}
+	return false, nil
+}
+
+// RemoveMember removes a user from a list (idempotent)
+func (r *Repository) RemoveMember(ctx context.Context, listID, memberID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": listID, "memberIds": memberID},
+		bson.M{
+			"$pull": bson.M{"memberIds": memberID},
+			"$inc":  bson.M{"memberCount": -1},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	return err
+}
+
+// RemoveMemberEverywhere removes a user from every list they are on
+func (r *Repository) RemoveMemberEverywhere(ctx context.Context, memberID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"memberIds": memberID},
+		bson.M{
+			"$pull": bson.M{"memberIds": memberID},
+			"$inc":  bson.M{"memberCount": -1},
+		},
+	)
+	return err
+}
+
+// DeleteAllForUser removes the user's lists and the user from other people's lists
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return err
+	}
+	return r.RemoveMemberEverywhere(ctx, userID)
+}
@@ -0,0 +1,36 @@
+package lists
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RegisterRoutes registers the user list routes. List timelines are served by the feed feature.
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+	repo := NewRepository(db)
+	authRepo := auth.NewRepository(db)
+	handler := NewHandler(repo, authRepo, safety.NewBlockPolicy(db))
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+	optionalAuth := middleware.OptionalAuthMiddleware(authRepo, cfg)
+
+	lists := router.Group("/lists")
+	{
+		lists.POST("", authMiddleware, handler.CreateList)
+
+		// Public lists are readable by anyone; private lists only by their owner
+		lists.GET("/:id", optionalAuth, handler.GetList)
+		lists.GET("/:id/members", optionalAuth, handler.GetMembers)
+
+		lists.PATCH("/:id", authMiddleware, handler.UpdateList)
+		lists.DELETE("/:id", authMiddleware, handler.DeleteList)
+		lists.POST("/:id/members", authMiddleware, handler.AddMember)
+		lists.DELETE("/:id/members/:userId", authMiddleware, handler.RemoveMember)
+	}
+
+	// A user's lists - "me" includes private lists
+	router.GET("/users/:id/lists", optionalAuth, handler.GetUserLists)
+}
@@ -0,0 +1,260 @@
+package mutes
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Handler struct {
+	repo     *Repository
+	authRepo *auth.Repository
+}
+
+func NewHandler(repo *Repository, authRepo *auth.Repository) *Handler {
+	return &Handler{
+		repo:     repo,
+		authRepo: authRepo,
+	}
+}
+
+// MuteUser godoc
+// @Summary Mute a user
+// @Description Hide a user's anchors from your feeds and search, and their notifications, without unfollowing or blocking. Optionally time-limited.
+// @Tags safety
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID to mute"
+// @Param request body MuteUserRequest false "Mute duration"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /users/{id}/mute [post]
+func (h *Handler) MuteUser(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+	if targetID == user.ID {
+		response.BadRequest(c, "You cannot mute yourself", "INVALID_ACTION")
+		return
+	}
+
+	var req MuteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+
+	if _, err := h.authRepo.GetUserByObjectID(c.Request.Context(), targetID); err != nil {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	expiresAt := expiryFrom(req.ExpiresInHours)
+	if err := h.repo.MuteUser(c.Request.Context(), user.ID, targetID, expiresAt); err != nil {
+		response.InternalServerError(c, "Failed to mute user", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, gin.H{"isMuted": true, "expiresAt": expiresAt}, "User muted")
+}
+
+// UnmuteUser godoc
+// @Summary Unmute a user
+// @Tags safety
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID to unmute"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /users/{id}/mute [delete]
+func (h *Handler) UnmuteUser(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	targetID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", "INVALID_ID")
+		return
+	}
+
+	if err := h.repo.UnmuteUser(c.Request.Context(), user.ID, targetID); err != nil {
+		response.InternalServerError(c, "Failed to unmute user", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, gin.H{"isMuted": false}, "User unmuted")
+}
+
+// MuteTag godoc
+// @Summary Mute a tag
+// @Description Hide anchors carrying the tag from discovery, tag feeds and search. Optionally time-limited.
+// @Tags safety
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MuteTagRequest true "Tag to mute"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Router /users/me/muted-tags [post]
+func (h *Handler) MuteTag(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req MuteTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+
+	tag := NormalizeTag(req.Tag)
+	if len(tag) < 2 {
+		response.BadRequest(c, "Invalid tag", "VALIDATION_FAILED")
+		return
+	}
+
+	expiresAt := expiryFrom(req.ExpiresInHours)
+	if err := h.repo.MuteTag(c.Request.Context(), user.ID, tag, expiresAt); err != nil {
+		response.InternalServerError(c, "Failed to mute tag", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, MutedTagResponse{Tag: tag, ExpiresAt: expiresAt, MutedAt: time.Now()}, "Tag muted")
+}
+
+// UnmuteTag godoc
+// @Summary Unmute a tag
+// @Tags safety
+// @Produce json
+// @Security BearerAuth
+// @Param tag path string true "Tag to unmute"
+// @Success 200 {object} response.APIResponse
+// @Router /users/me/muted-tags/{tag} [delete]
+func (h *Handler) UnmuteTag(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.UnmuteTag(c.Request.Context(), user.ID, c.Param("tag")); err != nil {
+		response.InternalServerError(c, "Failed to unmute tag", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, nil, "Tag unmuted")
+}
+
+// GetMutes godoc
+// @Summary List mutes
+// @Description Get the users and tags the current user mutes
+// @Tags safety
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=MutesResponse}
+// @Failure 401 {object} response.APIResponse
+// @Router /users/me/mutes [get]
+func (h *Handler) GetMutes(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	mutes, err := h.repo.GetActiveMutes(c.Request.Context(), user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch mutes", "DATABASE_ERROR")
+		return
+	}
+
+	var mutedUserIDs []primitive.ObjectID
+	for _, m := range mutes {
+		if m.Type == TypeUser && m.MutedUserID != nil {
+			mutedUserIDs = append(mutedUserIDs, *m.MutedUserID)
+		}
+	}
+
+	userMap := make(map[primitive.ObjectID]*auth.User)
+	if len(mutedUserIDs) > 0 {
+		users, err := h.authRepo.GetUsersByIDs(c.Request.Context(), mutedUserIDs)
+		if err != nil {
+			response.InternalServerError(c, "Failed to fetch muted users", "DATABASE_ERROR")
+			return
+		}
+		for i := range users {
+			userMap[users[i].ID] = &users[i]
+		}
+	}
+
+	resp := MutesResponse{
+		Users: []MutedUserResponse{},
+		Tags:  []MutedTagResponse{},
+	}
+	for _, m := range mutes {
+		switch m.Type {
+		case TypeUser:
+			if m.MutedUserID == nil {
+				continue
+			}
+			muted, exists := userMap[*m.MutedUserID]
+			if !exists {
+				continue
+			}
+			resp.Users = append(resp.Users, MutedUserResponse{
+				UserID:         muted.ID,
+				Username:       muted.Username,
+				DisplayName:    muted.DisplayName,
+				ProfilePicture: &muted.ProfilePictureURL,
+				ExpiresAt:      m.ExpiresAt,
+				MutedAt:        m.CreatedAt,
+			})
+		case TypeTag:
+			resp.Tags = append(resp.Tags, MutedTagResponse{
+				Tag:       m.Tag,
+				ExpiresAt: m.ExpiresAt,
+				MutedAt:   m.CreatedAt,
+			})
+		}
+	}
+
+	response.Success(c, resp)
+}
+
+// currentUser returns the authenticated user, writing an error response if there is none
+func currentUser(c *gin.Context) (*auth.User, bool) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return nil, false
+	}
+	user, ok := val.(*auth.User)
+	if !ok {
+		response.InternalServerError(c, "User context error", "INTERNAL_ERROR")
+		return nil, false
+	}
+	return user, true
+}
+
+// expiryFrom converts an optional duration in hours to an expiry time
+func expiryFrom(hours *int) *time.Time {
+	if hours == nil {
+		return nil
+	}
+	expiresAt := time.Now().Add(time.Duration(*hours) * time.Hour)
+	return &expiresAt
+}
@@ -0,0 +1,58 @@
+package mutes
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Mute types
+const (
+	TypeUser = "user" // Hide a user's anchors and notifications without unfollowing
+	TypeTag  = "tag"  // Hide anchors carrying a tag from discovery
+)
+
+// Mute represents a user or tag the user does not want to see
+type Mute struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"userId" json:"userId"` // User who muted
+	Type        string              `bson:"type" json:"type"`
+	MutedUserID *primitive.ObjectID `bson:"mutedUserId,omitempty" json:"mutedUserId,omitempty"`
+	Tag         string              `bson:"tag,omitempty" json:"tag,omitempty"`
+	ExpiresAt   *time.Time          `bson:"expiresAt,omitempty" json:"expiresAt"` // nil = until unmuted
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+// MuteUserRequest for POST /users/:id/mute (body optional)
+type MuteUserRequest struct {
+	ExpiresInHours *int `json:"expiresInHours" binding:"omitempty,min=1,max=8760"`
+}
+
+// MuteTagRequest for POST /users/me/muted-tags
+type MuteTagRequest struct {
+	Tag            string `json:"tag" binding:"required,min=2,max=50"`
+	ExpiresInHours *int   `json:"expiresInHours" binding:"omitempty,min=1,max=8760"`
+}
+
+// MutedUserResponse for items in the muted users list
+type MutedUserResponse struct {
+	UserID         primitive.ObjectID `json:"userId"`
+	Username       string             `json:"username"`
+	DisplayName    string             `json:"displayName"`
+	ProfilePicture *string            `json:"profilePicture"`
+	ExpiresAt      *time.Time         `json:"expiresAt"`
+	MutedAt        time.Time          `json:"mutedAt"`
+}
+
+// MutedTagResponse for items in the muted tags list
+type MutedTagResponse struct {
+	Tag       string     `json:"tag"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	MutedAt   time.Time  `json:"mutedAt"`
+}
+
+// MutesResponse for GET /users/me/mutes
+type MutesResponse struct {
+	Users []MutedUserResponse `json:"users"`
+	Tags  []MutedTagResponse  `json:"tags"`
+}
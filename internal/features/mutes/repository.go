@@ -0,0 +1,204 @@
+package mutes
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository handles database interactions for mutes
+type Repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates repository and ensures indexes
+func NewRepository(db *mongo.Database) *Repository {
+	collection := db.Collection("mutes")
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// One mute per user/target pair
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "type", Value: 1},
+				{Key: "mutedUserId", Value: 1},
+				{Key: "tag", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Who muted a user (notification fan-out)
+			Keys: bson.D{{Key: "mutedUserId", Value: 1}, {Key: "userId", Value: 1}},
+		},
+		{
+			// Remove time-limited mutes once they lapse
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+
+	return &Repository{
+		collection: collection,
+	}
+}
+
+// NormalizeTag returns the stored form of a tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(tag, "#")))
+}
+
+// activeFilter matches mutes that have not lapsed yet. The TTL monitor runs
+// only once a minute, so expired mutes are filtered explicitly as well.
+func activeFilter(filter bson.M) bson.M {
+	filter["$or"] = []bson.M{
+		{"expiresAt": nil},
+		{"expiresAt": bson.M{"$gt": time.Now()}},
+	}
+	return filter
+}
+
+// MuteUser mutes a user, replacing the expiry of an existing mute
+func (r *Repository) MuteUser(ctx context.Context, userID, mutedUserID primitive.ObjectID, expiresAt *time.Time) error {
+	return r.upsert(ctx, bson.M{"userId": userID, "type": TypeUser, "mutedUserId": mutedUserID}, expiresAt)
+}
+
+// UnmuteUser removes a user mute (idempotent)
+func (r *Repository) UnmuteUser(ctx context.Context, userID, mutedUserID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"userId": userID, "type": TypeUser, "mutedUserId": mutedUserID})
+	return err
+}
+
+// MuteTag mutes a tag, replacing the expiry of an existing mute
+func (r *Repository) MuteTag(ctx context.Context, userID primitive.ObjectID, tag string, expiresAt *time.Time) error {
+	return r.upsert(ctx, bson.M{"userId": userID, "type": TypeTag, "tag": NormalizeTag(tag)}, expiresAt)
+}
+
+// UnmuteTag removes a tag mute (idempotent)
+func (r *Repository) UnmuteTag(ctx context.Context, userID primitive.ObjectID, tag string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"userId": userID, "type": TypeTag, "tag": NormalizeTag(tag)})
+	return err
+}
+
+func (r *Repository) upsert(ctx context.Context, filter bson.M, expiresAt *time.Time) error {
+	set := bson.M{"createdAt": time.Now()}
+	update := bson.M{"$set": set}
+	if expiresAt != nil {
+		set["expiresAt"] = *expiresAt
+	} else {
+		update["$unset"] = bson.M{"expiresAt": ""}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetActiveMutes returns the user's mutes that have not lapsed, newest first
+func (r *Repository) GetActiveMutes(ctx context.Context, userID primitive.ObjectID) ([]Mute, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, activeFilter(bson.M{"userId": userID}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	mutes := []Mute{}
+	if err = cursor.All(ctx, &mutes); err != nil {
+		return nil, err
+	}
+	return mutes, nil
+}
+
+// GetMutedUserIDs returns the users the user currently mutes
+func (r *Repository) GetMutedUserIDs(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	mutes, err := r.find(ctx, bson.M{"userId": userID, "type": TypeUser})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(mutes))
+	for _, m := range mutes {
+		if m.MutedUserID != nil {
+			ids = append(ids, *m.MutedUserID)
+		}
+	}
+	return ids, nil
+}
+
+// GetMutedTags returns the tags the user currently mutes
+func (r *Repository) GetMutedTags(ctx context.Context, userID primitive.ObjectID) ([]string, error) {
+	mutes, err := r.find(ctx, bson.M{"userId": userID, "type": TypeTag})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(mutes))
+	for _, m := range mutes {
+		tags = append(tags, m.Tag)
+	}
+	return tags, nil
+}
+
+// IsUserMuted checks if userID currently mutes mutedUserID
+func (r *Repository) IsUserMuted(ctx context.Context, userID, mutedUserID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, activeFilter(bson.M{
+		"userId":      userID,
+		"type":        TypeUser,
+		"mutedUserId": mutedUserID,
+	}))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetUsersMuting returns which of userIDs currently mute mutedUserID
+func (r *Repository) GetUsersMuting(ctx context.Context, mutedUserID primitive.ObjectID, userIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	result := make(map[primitive.ObjectID]bool)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	mutes, err := r.find(ctx, bson.M{
+		"mutedUserId": mutedUserID,
+		"type":        TypeUser,
+		"userId":      bson.M{"$in": userIDs},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range mutes {
+		result[m.UserID] = true
+	}
+	return result, nil
+}
+
+// DeleteAllForUser removes the user's mutes and mutes of the user by others
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{
+		"$or": []bson.M{
+			{"userId": userID},
+			{"mutedUserId": userID},
+		},
+	})
+	return err
+}
+
+func (r *Repository) find(ctx context.Context, filter bson.M) ([]Mute, error) {
+	cursor, err := r.collection.Find(ctx, activeFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mutes []Mute
+	if err = cursor.All(ctx, &mutes); err != nil {
+		return nil, err
+	}
+	return mutes, nil
+}
@@ -0,0 +1,28 @@
+package mutes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RegisterRoutes registers the mute routes
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+	repo := NewRepository(db)
+	authRepo := auth.NewRepository(db)
+	handler := NewHandler(repo, authRepo)
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+
+	// Mutes - mounted under /users
+	users := router.Group("/users")
+	users.Use(authMiddleware)
+	{
+		users.POST("/:id/mute", handler.MuteUser)
+		users.DELETE("/:id/mute", handler.UnmuteUser)
+		users.GET("/me/mutes", handler.GetMutes)
+		users.POST("/me/muted-tags", handler.MuteTag)
+		users.DELETE("/me/muted-tags/:tag", handler.UnmuteTag)
+	}
+}
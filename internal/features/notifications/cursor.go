@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EncodeCursor creates a base64 encoded cursor string from a notification's timestamp and ID
+func EncodeCursor(timestamp time.Time, notificationID primitive.ObjectID) string {
+	jsonBytes, _ := json.Marshal(NotificationCursor{
+		Timestamp:      timestamp,
+		NotificationID: notificationID,
+	})
+	return base64.StdEncoding.EncodeToString(jsonBytes)
+}
+
+// DecodeCursor decodes a base64 encoded cursor string into a NotificationCursor struct
+func DecodeCursor(cursor string) (*NotificationCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	jsonBytes, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor format: not base64")
+	}
+
+	var cursorData NotificationCursor
+	if err := json.Unmarshal(jsonBytes, &cursorData); err != nil {
+		return nil, errors.New("invalid cursor format: invalid json")
+	}
+
+	if cursorData.Timestamp.IsZero() {
+		return nil, errors.New("invalid cursor: missing timestamp")
+	}
+	if cursorData.NotificationID.IsZero() {
+		return nil, errors.New("invalid cursor: missing notification id")
+	}
+
+	return &cursorData, nil
+}
+
+// EncodeEventID returns the stream event ID of a notification. Groups keep their ID but move
+// to a new createdAt when they change, so the ID carries both; the time is truncated to the
+// millisecond precision it is stored with.
+func EncodeEventID(n *Notification) string {
+	return EncodeCursor(n.CreatedAt.Truncate(time.Millisecond), n.ID)
+}
+
+// DecodeEventID decodes a stream event ID. IDs sent before event IDs carried a timestamp
+// were bare notification IDs; they resume from the second the notification was created.
+func DecodeEventID(id string) (*NotificationCursor, error) {
+	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+		return &NotificationCursor{Timestamp: oid.Timestamp(), NotificationID: oid}, nil
+	}
+	return DecodeCursor(id)
+}
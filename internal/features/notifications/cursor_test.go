@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	id := primitive.NewObjectID()
+
+	cursor, err := DecodeCursor(EncodeCursor(at, id))
+	require.NoError(t, err)
+	require.True(t, cursor.Timestamp.Equal(at))
+	require.Equal(t, id, cursor.NotificationID)
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, in := range []string{"not base64!", "bm90IGpzb24=", "e30="} {
+		_, err := DecodeCursor(in)
+		require.Error(t, err, in)
+	}
+
+	cursor, err := DecodeCursor("")
+	require.NoError(t, err)
+	require.Nil(t, cursor)
+}
+
+func TestEventIDTracksGroupUpdates(t *testing.T) {
+	n := Notification{ID: primitive.NewObjectID(), CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)}
+	first := EncodeEventID(&n)
+
+	after, err := DecodeEventID(first)
+	require.NoError(t, err)
+	require.Equal(t, n.ID, after.NotificationID)
+	require.True(t, after.Timestamp.Equal(n.CreatedAt.Truncate(time.Millisecond)))
+
+	// A group update keeps the ID but moves the event ID on
+	n.CreatedAt = n.CreatedAt.Add(time.Minute)
+	require.NotEqual(t, first, EncodeEventID(&n))
+}
+
+func TestDecodeEventIDAcceptsNotificationID(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	after, err := DecodeEventID(id.Hex())
+	require.NoError(t, err)
+	require.Equal(t, id, after.NotificationID)
+	require.True(t, after.Timestamp.Equal(id.Timestamp()))
+}
@@ -0,0 +1,24 @@
+package notifications
+
+// groupKey returns the key notifications are aggregated under, and false for types
+// that are never grouped. Entries with the same recipient and key arriving within
+// GroupWindow of the group's start share one row ("Alice and 12 others liked your anchor").
+func groupKey(n *Notification) (string, bool) {
+	switch n.Type {
+	case TypeLike, TypeComment, TypeAnchorUpdate:
+		// Per anchor
+		return n.Type + ":" + n.ResourceID.Hex(), true
+	case TypeClone:
+		// ResourceID is each new copy; group by the original
+		if n.AnchorID == nil {
+			return "", false
+		}
+		return n.Type + ":" + n.AnchorID.Hex(), true
+	case TypeFollow:
+		// ResourceID is each follower; all new followers share one row
+		return n.Type + ":" + n.RecipientID.Hex(), true
+	default:
+		// Mentions, replies and follow requests each need their own row
+		return "", false
+	}
+}
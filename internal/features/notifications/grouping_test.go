@@ -0,0 +1,29 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGroupKey(t *testing.T) {
+	recipient := primitive.NewObjectID()
+	anchorID := primitive.NewObjectID()
+
+	like := func() *Notification {
+		return &Notification{RecipientID: recipient, ActorID: primitive.NewObjectID(), Type: TypeLike, ResourceID: anchorID}
+	}
+	first, ok := groupKey(like())
+	require.True(t, ok)
+	second, _ := groupKey(like())
+	require.Equal(t, first, second, "likes on one anchor share a group")
+
+	// Each follower is its own resource, but follows still group per recipient
+	a, _ := groupKey(&Notification{RecipientID: recipient, Type: TypeFollow, ResourceID: primitive.NewObjectID()})
+	b, _ := groupKey(&Notification{RecipientID: recipient, Type: TypeFollow, ResourceID: primitive.NewObjectID()})
+	require.Equal(t, a, b)
+
+	_, ok = groupKey(&Notification{RecipientID: recipient, Type: TypeMention, ResourceID: primitive.NewObjectID()})
+	require.False(t, ok, "mentions are never grouped")
+}
@@ -34,14 +34,17 @@ func NewHandler(repo *Repository, authRepo *auth.Repository, contentProvider Con
 
 // ListNotifications godoc
 // @Summary List notifications
-// @Description Get paginated list of user's notifications
+// @Description Get the user's notifications, newest first. Pass pagination.nextCursor as cursor for the next page.
+// @Description The page parameter (unread first, with totals) is deprecated.
 // @Tags notifications
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "Page number (default 1)"
+// @Param cursor query string false "Pagination cursor"
+// @Param page query int false "Deprecated: page number"
 // @Param limit query int false "Items per page (default 20, max 50)"
 // @Param unreadOnly query bool false "Only show unread"
 // @Success 200 {object} response.APIResponse{data=PaginatedNotificationsResponse}
+// @Failure 400 {object} response.APIResponse
 // @Failure 401 {object} response.APIResponse
 // @Router /notifications [get]
 func (h *Handler) ListNotifications(c *gin.Context) {
@@ -63,10 +66,55 @@ func (h *Handler) ListNotifications(c *gin.Context) {
 		return
 	}
 
-	notifications, total, err := h.repo.GetUserNotifications(
+	if query.Page > 0 && query.Cursor == "" {
+		h.listNotificationsByPage(c, currentUser.ID, query)
+		return
+	}
+
+	cursor, err := DecodeCursor(query.Cursor)
+	if err != nil {
+		response.BadRequest(c, "INVALID_CURSOR", err.Error())
+		return
+	}
+
+	// Fetch one extra to know if there are more
+	notifications, err := h.repo.GetUserNotificationsPage(
 		c.Request.Context(),
 		currentUser.ID,
 		query.UnreadOnly,
+		cursor,
+		query.Limit+1,
+	)
+	if err != nil {
+		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch notifications")
+		return
+	}
+
+	hasMore := len(notifications) > query.Limit
+	if hasMore {
+		notifications = notifications[:query.Limit]
+	}
+
+	resp := PaginatedNotificationsResponse{
+		Notifications: h.enrichNotifications(c.Request.Context(), notifications),
+	}
+	resp.Pagination.Limit = query.Limit
+	resp.Pagination.HasMore = hasMore
+	if hasMore {
+		last := notifications[len(notifications)-1]
+		nextCursor := EncodeCursor(last.CreatedAt, last.ID)
+		resp.Pagination.NextCursor = &nextCursor
+	}
+
+	response.Success(c, resp)
+}
+
+// listNotificationsByPage serves the deprecated page parameter
+func (h *Handler) listNotificationsByPage(c *gin.Context, userID primitive.ObjectID, query NotificationListQuery) {
+	notifications, total, err := h.repo.GetUserNotifications(
+		c.Request.Context(),
+		userID,
+		query.UnreadOnly,
 		query.Page,
 		query.Limit,
 	)
@@ -163,6 +211,7 @@ func (h *Handler) MarkAsRead(c *gin.Context) {
 		response.InternalServerError(c, "UPDATE_FAILED", "Failed to mark as read")
 		return
 	}
+	DefaultHub().Publish(currentUser.ID, Event{Type: EventUnreadCount})
 
 	response.Success(c, MarkReadResponse{
 		ID:     notificationID,
@@ -192,10 +241,180 @@ func (h *Handler) MarkAllAsRead(c *gin.Context) {
 		response.InternalServerError(c, "UPDATE_FAILED", "Failed to mark all as read")
 		return
 	}
+	DefaultHub().Publish(currentUser.ID, Event{Type: EventUnreadCount})
 
 	response.Success(c, MarkAllReadResponse{MarkedCount: count})
 }
 
+// DeleteNotification godoc
+// @Summary Delete a notification
+// @Description Delete one of the user's notifications
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /notifications/{id} [delete]
+func (h *Handler) DeleteNotification(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+
+	notificationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "Invalid notification ID")
+		return
+	}
+
+	// Get notification to verify ownership
+	notification, err := h.repo.GetNotificationByID(c.Request.Context(), notificationID)
+	if err != nil {
+		response.NotFound(c, "NOT_FOUND", "Notification not found")
+		return
+	}
+	if notification.RecipientID != currentUser.ID {
+		response.Forbidden(c, "FORBIDDEN", "Cannot delete others' notifications")
+		return
+	}
+
+	if err := h.repo.DeleteNotification(c.Request.Context(), currentUser.ID, notificationID); err != nil {
+		response.NotFound(c, "NOT_FOUND", "Notification not found")
+		return
+	}
+	if !notification.IsRead {
+		DefaultHub().Publish(currentUser.ID, Event{Type: EventUnreadCount})
+	}
+
+	response.Success(c, nil, "Notification deleted")
+}
+
+// DeleteNotifications godoc
+// @Summary Delete notifications
+// @Description Delete the listed notifications (up to 100) and/or, with read=true, every read notification
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteNotificationsRequest true "Notifications to delete"
+// @Success 200 {object} response.APIResponse{data=DeleteNotificationsResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /notifications [delete]
+func (h *Handler) DeleteNotifications(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+
+	var req DeleteNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if len(req.IDs) == 0 && !req.Read {
+		response.BadRequest(c, "INVALID_REQUEST", "Provide ids or read")
+		return
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(req.IDs))
+	for _, idStr := range req.IDs {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			response.BadRequest(c, "INVALID_ID", "Invalid notification ID")
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	// Only the caller's own notifications match, so foreign IDs are skipped
+	count, err := h.repo.DeleteNotifications(c.Request.Context(), currentUser.ID, ids, req.Read)
+	if err != nil {
+		response.InternalServerError(c, "DELETE_FAILED", "Failed to delete notifications")
+		return
+	}
+	if count > 0 {
+		DefaultHub().Publish(currentUser.ID, Event{Type: EventUnreadCount})
+	}
+
+	response.Success(c, DeleteNotificationsResponse{DeletedCount: count})
+}
+
+// RegisterDevice godoc
+// @Summary Register a device for push notifications
+// @Description Store an FCM registration token for the current user. Registering a token again refreshes it.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterDeviceRequest true "Device token"
+// @Success 201 {object} response.APIResponse{data=DeviceResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /notifications/devices [post]
+func (h *Handler) RegisterDevice(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	device, err := h.repo.RegisterDevice(c.Request.Context(), currentUser.ID, req.Token, req.Platform)
+	if err != nil {
+		response.InternalServerError(c, "REGISTER_FAILED", "Failed to register device")
+		return
+	}
+
+	response.Created(c, DeviceResponse{
+		ID:         device.ID,
+		Platform:   device.Platform,
+		CreatedAt:  device.CreatedAt,
+		LastSeenAt: device.LastSeenAt,
+	})
+}
+
+// UnregisterDevice godoc
+// @Summary Unregister a push device
+// @Description Stop sending push notifications to an FCM registration token (e.g. on logout)
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "FCM registration token"
+// @Success 200 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /notifications/devices/{token} [delete]
+func (h *Handler) UnregisterDevice(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+
+	if err := h.repo.UnregisterDevice(c.Request.Context(), currentUser.ID, c.Param("token")); err != nil {
+		response.NotFound(c, "NOT_FOUND", "Device not found")
+		return
+	}
+
+	response.Success(c, nil, "Device unregistered")
+}
+
 // Helper to enrich notifications with actor and anchor data
 func (h *Handler) enrichNotifications(ctx context.Context, notifications []Notification) []NotificationResponse {
 	if len(notifications) == 0 {
@@ -209,9 +428,11 @@ func (h *Handler) enrichNotifications(ctx context.Context, notifications []Notif
 	anchorIDSet := make(map[primitive.ObjectID]bool)
 
 	for _, n := range notifications {
-		if !actorIDSet[n.ActorID] {
-			actorIDSet[n.ActorID] = true
-			actorIDs = append(actorIDs, n.ActorID)
+		for _, id := range append([]primitive.ObjectID{n.ActorID}, n.RecentActorIDs...) {
+			if !actorIDSet[id] {
+				actorIDSet[id] = true
+				actorIDs = append(actorIDs, id)
+			}
 		}
 		if n.AnchorID != nil && !anchorIDSet[*n.AnchorID] {
 			anchorIDSet[*n.AnchorID] = true
@@ -244,28 +465,20 @@ func (h *Handler) enrichNotifications(ctx context.Context, notifications []Notif
 			ResourceType: n.ResourceType,
 			ResourceID:   n.ResourceID,
 			AnchorID:     n.AnchorID,
+			ItemID:       n.ItemID,
 			Preview:      n.Preview,
 			IsRead:       n.IsRead,
 			CreatedAt:    n.CreatedAt,
+			ActorCount:   n.TotalActors(),
+			IsGrouped:    n.GroupKey != "",
 		}
 
 		// Add actor
-		if actor, ok := actorMap[n.ActorID]; ok {
-			var profilePic *string
-			if actor.ProfilePictureURL != "" {
-				profilePic = &actor.ProfilePictureURL
-			}
-			resp.Actor = NotificationActor{
-				ID:             actor.ID,
-				Username:       actor.Username,
-				DisplayName:    actor.DisplayName,
-				ProfilePicture: profilePic,
-			}
-		} else {
-			resp.Actor = NotificationActor{
-				ID:          n.ActorID,
-				Username:    "deleted",
-				DisplayName: "Deleted User",
+		resp.Actor = buildNotificationActor(actorMap, n.ActorID)
+		if resp.IsGrouped {
+			resp.Actors = make([]NotificationActor, len(n.RecentActorIDs))
+			for j, id := range n.RecentActorIDs {
+				resp.Actors[j] = buildNotificationActor(actorMap, id)
 			}
 		}
 
@@ -284,3 +497,25 @@ func (h *Handler) enrichNotifications(ctx context.Context, notifications []Notif
 
 	return responses
 }
+
+func buildNotificationActor(actorMap map[primitive.ObjectID]*auth.User, actorID primitive.ObjectID) NotificationActor {
+	actor, ok := actorMap[actorID]
+	if !ok {
+		return NotificationActor{
+			ID:          actorID,
+			Username:    "deleted",
+			DisplayName: "Deleted User",
+		}
+	}
+
+	var profilePic *string
+	if actor.ProfilePictureURL != "" {
+		profilePic = &actor.ProfilePictureURL
+	}
+	return NotificationActor{
+		ID:             actor.ID,
+		Username:       actor.Username,
+		DisplayName:    actor.DisplayName,
+		ProfilePicture: profilePic,
+	}
+}
@@ -0,0 +1,161 @@
+package notifications
+
+import (
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Stream event types
+const (
+	EventNotification = "notification" // A new notification was created
+	EventUnreadCount  = "unread_count" // The unread count changed
+	EventResync       = "resync"       // Too much was missed to replay; the client should reload its list
+)
+
+// subscriberBuffer is how many events a subscriber can fall behind before it is dropped
+const subscriberBuffer = 32
+
+// Event is a change to a user's notifications, pushed to their open streams
+type Event struct {
+	Type         string        `json:"type"`
+	Notification *Notification `json:"notification,omitempty"` // Set for EventNotification
+}
+
+// Hub fans notification events out to each user's open streams. MemoryHub serves a
+// single API instance; a broker-backed Hub can replace it via SetHub when the API
+// runs on several.
+type Hub interface {
+	// Publish delivers an event to every subscription of the user without blocking
+	Publish(userID primitive.ObjectID, event Event)
+	// Subscribe opens a subscription to the user's events
+	Subscribe(userID primitive.ObjectID) *Subscription
+	// Close ends every subscription and rejects new ones
+	Close()
+}
+
+// Subscription receives one user's events. Events is closed when the subscription
+// ends, either through Unsubscribe, because the hub closed, or because the
+// subscriber fell too far behind and should reconnect.
+type Subscription struct {
+	Events <-chan Event
+	cancel func()
+}
+
+// NewSubscription wraps an event channel and the function that ends it, for Hub implementations
+func NewSubscription(events <-chan Event, cancel func()) *Subscription {
+	return &Subscription{Events: events, cancel: cancel}
+}
+
+// Unsubscribe ends the subscription; it is safe to call more than once
+func (s *Subscription) Unsubscribe() {
+	s.cancel()
+}
+
+var (
+	hubMu      sync.RWMutex
+	defaultHub Hub = NewMemoryHub()
+)
+
+// SetHub replaces the hub notifications are published to; call it before routes are registered
+func SetHub(hub Hub) {
+	hubMu.Lock()
+	defer hubMu.Unlock()
+	defaultHub = hub
+}
+
+// DefaultHub returns the hub notifications are published to
+func DefaultHub() Hub {
+	hubMu.RLock()
+	defer hubMu.RUnlock()
+	return defaultHub
+}
+
+// MemoryHub is an in-process Hub
+type MemoryHub struct {
+	mu          sync.Mutex
+	subscribers map[primitive.ObjectID]map[*memorySubscriber]struct{}
+	closed      bool
+}
+
+type memorySubscriber struct {
+	events chan Event
+	once   sync.Once
+}
+
+func (s *memorySubscriber) close() {
+	s.once.Do(func() { close(s.events) })
+}
+
+func NewMemoryHub() *MemoryHub {
+	return &MemoryHub{
+		subscribers: make(map[primitive.ObjectID]map[*memorySubscriber]struct{}),
+	}
+}
+
+func (h *MemoryHub) Publish(userID primitive.ObjectID, event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub.events <- event:
+		default:
+			// Too far behind: drop it so the client reconnects and resumes from its last event
+			h.remove(userID, sub)
+		}
+	}
+}
+
+func (h *MemoryHub) Subscribe(userID primitive.ObjectID) *Subscription {
+	sub := &memorySubscriber{events: make(chan Event, subscriberBuffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		sub.close()
+		return NewSubscription(sub.events, func() {})
+	}
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*memorySubscriber]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+
+	return NewSubscription(sub.events, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(userID, sub)
+	})
+}
+
+func (h *MemoryHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, subs := range h.subscribers {
+		for sub := range subs {
+			sub.close()
+		}
+		delete(h.subscribers, userID)
+	}
+}
+
+// remove ends a subscriber; the caller holds h.mu
+func (h *MemoryHub) remove(userID primitive.ObjectID, sub *memorySubscriber) {
+	subs, ok := h.subscribers[userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	sub.close()
+	if len(subs) == 0 {
+		delete(h.subscribers, userID)
+	}
+}
@@ -0,0 +1,197 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xyz-asif/gotodo/internal/pkg/jobqueue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Job kinds run by the background worker pool
+const (
+	JobAnchorUpdate      = "notifications.anchor_update"       // Splits an anchor update's followers into chunks
+	JobAnchorUpdateChunk = "notifications.anchor_update_chunk" // Notifies one chunk of followers
+	JobLike              = "notifications.like"
+	JobClone             = "notifications.clone"
+	JobFollow            = "notifications.follow"
+	JobFollowRequest     = "notifications.follow_request"
+	JobFollowAccept      = "notifications.follow_accept"
+)
+
+// fanOutChunkSize is how many followers one anchor update chunk job notifies
+const fanOutChunkSize = 500
+
+type anchorUpdateJob struct {
+	AnchorID     primitive.ObjectID   `bson:"anchorId"`
+	Version      int                  `bson:"version"`
+	Title        string               `bson:"title"`
+	AuthorID     primitive.ObjectID   `bson:"authorId"`
+	RecipientIDs []primitive.ObjectID `bson:"recipientIds,omitempty"` // Chunk jobs only
+}
+
+type likeJob struct {
+	AnchorID primitive.ObjectID `bson:"anchorId"`
+	Title    string             `bson:"title"`
+	ActorID  primitive.ObjectID `bson:"actorId"`
+	OwnerID  primitive.ObjectID `bson:"ownerId"`
+}
+
+type cloneJob struct {
+	ClonedAnchorID   primitive.ObjectID `bson:"clonedAnchorId"`
+	OriginalAnchorID primitive.ObjectID `bson:"originalAnchorId"`
+	Title            string             `bson:"title"`
+	ActorID          primitive.ObjectID `bson:"actorId"`
+	OwnerID          primitive.ObjectID `bson:"ownerId"`
+}
+
+// followJob covers follows, follow requests and accepted requests
+type followJob struct {
+	ActorID     primitive.ObjectID `bson:"actorId"`
+	RecipientID primitive.ObjectID `bson:"recipientId"`
+}
+
+// anchorUpdateDedupeKey identifies one version's fan-out, or one chunk of it
+func anchorUpdateDedupeKey(anchorID primitive.ObjectID, version int, chunk int) string {
+	if chunk < 0 {
+		return fmt.Sprintf("notifications.anchor_update:%s:%d", anchorID.Hex(), version)
+	}
+	return fmt.Sprintf("notifications.anchor_update:%s:%d:%d", anchorID.Hex(), version, chunk)
+}
+
+// EnqueueAnchorUpdateNotifications queues notifying an anchor's followers that it reached
+// the given version. Queueing the same version again does nothing.
+func (s *Service) EnqueueAnchorUpdateNotifications(ctx context.Context, anchorID primitive.ObjectID, version int, anchorTitle string, authorID primitive.ObjectID) error {
+	_, err := s.queue.Enqueue(ctx, JobAnchorUpdate, anchorUpdateJob{
+		AnchorID: anchorID,
+		Version:  version,
+		Title:    anchorTitle,
+		AuthorID: authorID,
+	}, anchorUpdateDedupeKey(anchorID, version, -1))
+	return err
+}
+
+// EnqueueLikeNotification queues the notification for a new like
+func (s *Service) EnqueueLikeNotification(ctx context.Context, anchorID primitive.ObjectID, anchorTitle string, actorID, ownerID primitive.ObjectID) error {
+	if actorID == ownerID {
+		return nil
+	}
+	_, err := s.queue.Enqueue(ctx, JobLike, likeJob{AnchorID: anchorID, Title: anchorTitle, ActorID: actorID, OwnerID: ownerID}, "")
+	return err
+}
+
+// EnqueueCloneNotification queues the notification for a new clone
+func (s *Service) EnqueueCloneNotification(ctx context.Context, clonedAnchorID, originalAnchorID primitive.ObjectID, anchorTitle string, actorID, ownerID primitive.ObjectID) error {
+	if actorID == ownerID {
+		return nil
+	}
+	_, err := s.queue.Enqueue(ctx, JobClone, cloneJob{
+		ClonedAnchorID:   clonedAnchorID,
+		OriginalAnchorID: originalAnchorID,
+		Title:            anchorTitle,
+		ActorID:          actorID,
+		OwnerID:          ownerID,
+	}, "")
+	return err
+}
+
+// EnqueueFollowNotification queues the notification for a new follower
+func (s *Service) EnqueueFollowNotification(ctx context.Context, actorID, targetUserID primitive.ObjectID) error {
+	return s.enqueueFollow(ctx, JobFollow, actorID, targetUserID)
+}
+
+// EnqueueFollowRequestNotification queues the notification for a follow request to a private account
+func (s *Service) EnqueueFollowRequestNotification(ctx context.Context, actorID, targetUserID primitive.ObjectID) error {
+	return s.enqueueFollow(ctx, JobFollowRequest, actorID, targetUserID)
+}
+
+// EnqueueFollowAcceptNotification queues telling the requester their follow request was approved
+func (s *Service) EnqueueFollowAcceptNotification(ctx context.Context, actorID, requesterID primitive.ObjectID) error {
+	return s.enqueueFollow(ctx, JobFollowAccept, actorID, requesterID)
+}
+
+func (s *Service) enqueueFollow(ctx context.Context, kind string, actorID, recipientID primitive.ObjectID) error {
+	if actorID == recipientID {
+		return nil
+	}
+	_, err := s.queue.Enqueue(ctx, kind, followJob{ActorID: actorID, RecipientID: recipientID}, "")
+	return err
+}
+
+// RegisterJobs adds the notification job handlers to a worker pool. Retries are safe:
+// groupable notifications ignore an actor already in the group, and chunks are enqueued
+// under stable dedupe keys.
+func (s *Service) RegisterJobs(pool *jobqueue.Pool) {
+	pool.Handle(JobAnchorUpdate, s.fanOutAnchorUpdate)
+	pool.Handle(JobAnchorUpdateChunk, s.notifyAnchorUpdateChunk)
+
+	pool.Handle(JobLike, func(ctx context.Context, job *jobqueue.Job) error {
+		var p likeJob
+		if err := job.Decode(&p); err != nil {
+			return err
+		}
+		return s.CreateLikeNotification(ctx, p.AnchorID, p.Title, p.ActorID, p.OwnerID)
+	})
+	pool.Handle(JobClone, func(ctx context.Context, job *jobqueue.Job) error {
+		var p cloneJob
+		if err := job.Decode(&p); err != nil {
+			return err
+		}
+		return s.CreateCloneNotification(ctx, p.ClonedAnchorID, p.OriginalAnchorID, p.Title, p.ActorID, p.OwnerID)
+	})
+	pool.Handle(JobFollow, s.followHandler(s.CreateFollowNotification))
+	pool.Handle(JobFollowRequest, s.followHandler(s.CreateFollowRequestNotification))
+	pool.Handle(JobFollowAccept, s.followHandler(s.CreateFollowAcceptNotification))
+}
+
+func (s *Service) followHandler(create func(ctx context.Context, actorID, recipientID primitive.ObjectID) error) jobqueue.Handler {
+	return func(ctx context.Context, job *jobqueue.Job) error {
+		var p followJob
+		if err := job.Decode(&p); err != nil {
+			return err
+		}
+		return create(ctx, p.ActorID, p.RecipientID)
+	}
+}
+
+// fanOutAnchorUpdate pages through the anchor's followers and queues a chunk job per page
+func (s *Service) fanOutAnchorUpdate(ctx context.Context, job *jobqueue.Job) error {
+	if s.followerProvider == nil {
+		return nil
+	}
+
+	var p anchorUpdateJob
+	if err := job.Decode(&p); err != nil {
+		return err
+	}
+
+	var after primitive.ObjectID
+	for chunk := 0; ; chunk++ {
+		followerIDs, last, err := s.followerProvider.GetFollowersWithNotificationsAfter(ctx, p.AnchorID, after, fanOutChunkSize)
+		if err != nil {
+			return err
+		}
+		if len(followerIDs) == 0 {
+			return nil
+		}
+
+		chunkJob := p
+		chunkJob.RecipientIDs = followerIDs
+		if _, err := s.queue.Enqueue(ctx, JobAnchorUpdateChunk, chunkJob, anchorUpdateDedupeKey(p.AnchorID, p.Version, chunk)); err != nil {
+			return err
+		}
+
+		if len(followerIDs) < fanOutChunkSize {
+			return nil
+		}
+		after = last
+	}
+}
+
+func (s *Service) notifyAnchorUpdateChunk(ctx context.Context, job *jobqueue.Job) error {
+	var p anchorUpdateJob
+	if err := job.Decode(&p); err != nil {
+		return err
+	}
+	return s.CreateAnchorUpdateNotifications(ctx, p.AnchorID, p.Title, p.AuthorID, p.RecipientIDs)
+}
@@ -8,12 +8,15 @@ import (
 
 // Notification type constants
 const (
-	TypeMention      = "mention"
-	TypeComment      = "comment"
-	TypeLike         = "like"
-	TypeFollow       = "follow"
-	TypeClone        = "clone"
-	TypeAnchorUpdate = "anchor_update" // When a followed anchor gets new content
+	TypeMention       = "mention"
+	TypeComment       = "comment"
+	TypeReply         = "reply" // When someone replies to your comment
+	TypeLike          = "like"
+	TypeFollow        = "follow"
+	TypeClone         = "clone"
+	TypeAnchorUpdate  = "anchor_update"  // When a followed anchor gets new content
+	TypeFollowRequest = "follow_request" // When someone asks to follow a private account
+	TypeFollowAccept  = "follow_accept"  // When a private account approves a follow request
 )
 
 // Notification represents a user notification
@@ -25,17 +28,132 @@ type Notification struct {
 	ResourceType string              `bson:"resourceType" json:"resourceType"`
 	ResourceID   primitive.ObjectID  `bson:"resourceId" json:"resourceId"`
 	AnchorID     *primitive.ObjectID `bson:"anchorId,omitempty" json:"anchorId,omitempty"`
+	ItemID       *primitive.ObjectID `bson:"itemId,omitempty" json:"itemId,omitempty"` // Item a comment targets, if any
 	Preview      string              `bson:"preview" json:"preview"`
 	IsRead       bool                `bson:"isRead" json:"isRead"`
-	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
+	ReadAt       *time.Time          `bson:"readAt,omitempty" json:"-"`  // Read notifications expire a retention period after this
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"` // For groups, when the latest actor joined
+
+	// Grouping (see groupKey): ActorID is the latest actor of the group
+	GroupKey       string               `bson:"groupKey,omitempty" json:"-"`
+	ActorCount     int                  `bson:"actorCount,omitempty" json:"actorCount,omitempty"`         // Size of ActorIDs
+	ActorIDs       []primitive.ObjectID `bson:"actorIds,omitempty" json:"-"`                              // Every distinct actor of the group
+	RecentActorIDs []primitive.ObjectID `bson:"recentActorIds,omitempty" json:"recentActorIds,omitempty"` // Newest first, for display
+	GroupStartedAt *time.Time           `bson:"groupStartedAt,omitempty" json:"-"`
+}
+
+// Grouping limits
+const (
+	GroupWindow     = 24 * time.Hour // How long a group keeps absorbing new actors
+	MaxRecentActors = 3              // Actors kept (and shown) per group
+)
+
+// TotalActors returns how many users the entry stands for
+func (n *Notification) TotalActors() int {
+	if n.ActorCount < 1 {
+		return 1
+	}
+	return n.ActorCount
+}
+
+// NotificationTypes lists every type a user can set preferences for
+var NotificationTypes = []string{
+	TypeMention,
+	TypeComment,
+	TypeReply,
+	TypeLike,
+	TypeFollow,
+	TypeClone,
+	TypeAnchorUpdate,
+	TypeFollowRequest,
+	TypeFollowAccept,
+}
+
+// ChannelSettings says where one type of notification is delivered
+type ChannelSettings struct {
+	InApp bool `bson:"inApp" json:"inApp"` // Stored in the notification list and streamed
+	Push  bool `bson:"push" json:"push"`   // Sent to registered devices
+	Email bool `bson:"email" json:"email"` // Included in the email digest
+}
+
+// Email digest frequencies
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// QuietHours is a daily window, in the user's timezone, when pushes are suppressed; notifications
+// arriving then are not pushed later, but still reach the in-app list if it is on.
+// Start and End are "HH:MM"; a window whose End is before its Start spans midnight.
+type QuietHours struct {
+	Enabled  bool   `bson:"enabled" json:"enabled"`
+	Start    string `bson:"start" json:"start"`
+	End      string `bson:"end" json:"end"`
+	Timezone string `bson:"timezone" json:"timezone"` // IANA name, e.g. "Europe/Berlin"
+}
+
+// NotificationSettings holds a user's delivery preferences; users without a stored
+// document get DefaultNotificationSettings
+type NotificationSettings struct {
+	UserID      primitive.ObjectID         `bson:"_id" json:"-"`
+	Types       map[string]ChannelSettings `bson:"types" json:"types"`
+	QuietHours  QuietHours                 `bson:"quietHours" json:"quietHours"`
+	EmailDigest string                     `bson:"emailDigest" json:"emailDigest"` // DigestOff, DigestDaily or DigestWeekly
+	UpdatedAt   time.Time                  `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Device platforms
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWeb     = "web"
+)
+
+// MaxDevicesPerUser caps registered devices; registering another drops the least recently seen
+const MaxDevicesPerUser = 10
+
+// Device is a push token registered by one of a user's app installs
+type Device struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"userId" json:"userId"`
+	Token      string             `bson:"token" json:"-"`
+	Platform   string             `bson:"platform" json:"platform"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	LastSeenAt time.Time          `bson:"lastSeenAt" json:"lastSeenAt"`
 }
 
 // Request DTOs
 
 type NotificationListQuery struct {
-	Page       int  `form:"page,default=1" binding:"min=1"`
-	Limit      int  `form:"limit,default=20" binding:"min=1,max=50"`
-	UnreadOnly bool `form:"unreadOnly"`
+	Cursor     string `form:"cursor"`
+	Page       int    `form:"page" binding:"omitempty,min=1"` // Deprecated: offset pagination, use cursor
+	Limit      int    `form:"limit,default=20" binding:"min=1,max=50"`
+	UnreadOnly bool   `form:"unreadOnly"`
+}
+
+// NotificationCursor is the decoded position in a notification list
+type NotificationCursor struct {
+	Timestamp      time.Time          `json:"t"`
+	NotificationID primitive.ObjectID `json:"i"`
+}
+
+// DeleteNotificationsRequest deletes either the listed notifications or all read ones
+type DeleteNotificationsRequest struct {
+	IDs  []string `json:"ids" binding:"omitempty,max=100,dive,len=24,hexadecimal"`
+	Read bool     `json:"read"` // Delete every read notification
+}
+
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" binding:"required,max=4096"`
+	Platform string `json:"platform" binding:"required,oneof=ios android web"`
+}
+
+// UpdateNotificationSettingsRequest replaces a user's settings; types left out go back to their defaults
+type UpdateNotificationSettingsRequest struct {
+	Types       map[string]ChannelSettings `json:"types"`
+	QuietHours  *QuietHours                `json:"quietHours"`
+	EmailDigest *string                    `json:"emailDigest" binding:"omitempty,oneof=off daily weekly"`
 }
 
 // Response DTOs
@@ -58,24 +176,35 @@ type NotificationResponse struct {
 	ResourceType string              `json:"resourceType"`
 	ResourceID   primitive.ObjectID  `json:"resourceId"`
 	AnchorID     *primitive.ObjectID `json:"anchorId,omitempty"`
+	ItemID       *primitive.ObjectID `json:"itemId,omitempty"`
 	Preview      string              `json:"preview"`
 	IsRead       bool                `json:"isRead"`
 	CreatedAt    time.Time           `json:"createdAt"`
-	Actor        NotificationActor   `json:"actor"`
+	Actor        NotificationActor   `json:"actor"`            // Latest actor
+	Actors       []NotificationActor `json:"actors,omitempty"` // Most recent actors of a group, newest first
+	ActorCount   int                 `json:"actorCount"`       // Total actors; "Alice and {actorCount-1} others"
+	IsGrouped    bool                `json:"isGrouped"`
 	Anchor       *NotificationAnchor `json:"anchor,omitempty"`
 }
 
 type PaginatedNotificationsResponse struct {
 	Notifications []NotificationResponse `json:"notifications"`
 	Pagination    struct {
-		Page       int   `json:"page"`
-		Limit      int   `json:"limit"`
-		Total      int64 `json:"total"`
-		TotalPages int   `json:"totalPages"`
-		HasMore    bool  `json:"hasMore"`
+		Limit      int     `json:"limit"`
+		HasMore    bool    `json:"hasMore"`
+		NextCursor *string `json:"nextCursor"`
+
+		// Only for the deprecated page parameter
+		Page       int   `json:"page,omitempty"`
+		Total      int64 `json:"total,omitempty"`
+		TotalPages int   `json:"totalPages,omitempty"`
 	} `json:"pagination"`
 }
 
+type DeleteNotificationsResponse struct {
+	DeletedCount int64 `json:"deletedCount"`
+}
+
 type UnreadCountResponse struct {
 	UnreadCount int64 `json:"unreadCount"`
 }
@@ -88,3 +217,17 @@ type MarkReadResponse struct {
 type MarkAllReadResponse struct {
 	MarkedCount int64 `json:"markedCount"`
 }
+
+type DeviceResponse struct {
+	ID         primitive.ObjectID `json:"id"`
+	Platform   string             `json:"platform"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	LastSeenAt time.Time          `json:"lastSeenAt"`
+}
+
+type NotificationSettingsResponse struct {
+	Types       map[string]ChannelSettings `json:"types"`
+	QuietHours  QuietHours                 `json:"quietHours"`
+	EmailDigest string                     `json:"emailDigest"`
+	UpdatedAt   *time.Time                 `json:"updatedAt,omitempty"` // Nil until the user saves settings
+}
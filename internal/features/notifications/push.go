@@ -0,0 +1,300 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/api/option"
+)
+
+// PushMessage is what a device shows for a notification
+type PushMessage struct {
+	Title string
+	Body  string
+	Data  map[string]string // Lets the app open the right screen
+}
+
+// PushSender delivers a message to device tokens
+type PushSender interface {
+	// Send returns the tokens the push service rejected as invalid or unregistered;
+	// they should no longer be used
+	Send(ctx context.Context, tokens []string, msg PushMessage) (invalid []string, err error)
+}
+
+// FCMSender sends pushes through Firebase Cloud Messaging
+type FCMSender struct {
+	client *messaging.Client
+}
+
+// NewFCMSender initializes a messaging client from the Firebase service account
+func NewFCMSender(ctx context.Context, cfg *config.Config) (*FCMSender, error) {
+	opt := option.WithCredentialsFile(cfg.FirebaseServiceAccountPath)
+	app, err := firebase.NewApp(ctx, nil, opt)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing firebase app: %v", err)
+	}
+
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting firebase messaging client: %v", err)
+	}
+
+	return &FCMSender{client: client}, nil
+}
+
+func (s *FCMSender) Send(ctx context.Context, tokens []string, msg PushMessage) ([]string, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	batch, err := s.client.SendEachForMulticast(ctx, &messaging.MulticastMessage{
+		Tokens: tokens,
+		Notification: &messaging.Notification{
+			Title: msg.Title,
+			Body:  msg.Body,
+		},
+		Data: msg.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var invalid []string
+	for i, resp := range batch.Responses {
+		switch {
+		case resp.Error == nil:
+		case messaging.IsUnregistered(resp.Error) || messaging.IsSenderIDMismatch(resp.Error):
+			invalid = append(invalid, tokens[i])
+		case messaging.IsInvalidArgument(resp.Error):
+			// Usually a problem with the message (e.g. an oversized payload), not the token
+			log.Printf("Push: FCM rejected message for token %s: %v", tokenSuffix(tokens[i]), resp.Error)
+		}
+	}
+	return invalid, nil
+}
+
+// tokenSuffix shortens a push token for logs
+func tokenSuffix(token string) string {
+	if len(token) <= 8 {
+		return token
+	}
+	return "..." + token[len(token)-8:]
+}
+
+// RecordedPush is one call to a RecordingPushSender
+type RecordedPush struct {
+	Tokens  []string
+	Message PushMessage
+}
+
+// RecordingPushSender is a PushSender for tests: it records every send and reports
+// the tokens listed in Invalid as rejected
+type RecordingPushSender struct {
+	mu      sync.Mutex
+	Sent    []RecordedPush
+	Invalid map[string]bool
+}
+
+func (s *RecordingPushSender) Send(ctx context.Context, tokens []string, msg PushMessage) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Sent = append(s.Sent, RecordedPush{Tokens: append([]string(nil), tokens...), Message: msg})
+
+	var invalid []string
+	for _, t := range tokens {
+		if s.Invalid[t] {
+			invalid = append(invalid, t)
+		}
+	}
+	return invalid, nil
+}
+
+// Pushes returns a copy of what has been sent so far
+func (s *RecordingPushSender) Pushes() []RecordedPush {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedPush(nil), s.Sent...)
+}
+
+// DeviceStore looks up and prunes push tokens (implemented by Repository)
+type DeviceStore interface {
+	GetDeviceTokens(ctx context.Context, userIDs []primitive.ObjectID) (map[primitive.ObjectID][]string, error)
+	DeleteDeviceTokens(ctx context.Context, tokens []string) error
+}
+
+// ActorLookup resolves the users who triggered notifications (implemented by auth.Repository)
+type ActorLookup interface {
+	GetUsersByIDs(ctx context.Context, userIDs []primitive.ObjectID) ([]auth.User, error)
+}
+
+// PushDispatcher fans created notifications out to their recipients' devices
+type PushDispatcher struct {
+	devices DeviceStore
+	actors  ActorLookup
+	sender  PushSender
+}
+
+func NewPushDispatcher(devices DeviceStore, actors ActorLookup, sender PushSender) *PushDispatcher {
+	return &PushDispatcher{
+		devices: devices,
+		actors:  actors,
+		sender:  sender,
+	}
+}
+
+var (
+	pushMu            sync.RWMutex
+	defaultDispatcher *PushDispatcher
+)
+
+// SetPushDispatcher enables push delivery for every notification created from now on
+func SetPushDispatcher(dispatcher *PushDispatcher) {
+	pushMu.Lock()
+	defer pushMu.Unlock()
+	defaultDispatcher = dispatcher
+}
+
+// DefaultPushDispatcher returns the configured dispatcher, or nil when push is disabled
+func DefaultPushDispatcher() *PushDispatcher {
+	pushMu.RLock()
+	defer pushMu.RUnlock()
+	return defaultDispatcher
+}
+
+// dispatchPush hands notifications to the configured dispatcher in the background
+func dispatchPush(notifications []Notification) {
+	dispatcher := DefaultPushDispatcher()
+	if dispatcher == nil || len(notifications) == 0 {
+		return
+	}
+
+	go func() {
+		_ = dispatcher.Dispatch(context.Background(), notifications)
+	}()
+}
+
+// Dispatch sends a push for each notification to every device of its recipient and
+// prunes tokens the push service rejected
+func (d *PushDispatcher) Dispatch(ctx context.Context, notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	recipientIDs := make([]primitive.ObjectID, 0, len(notifications))
+	actorIDs := make([]primitive.ObjectID, 0, len(notifications))
+	for _, n := range notifications {
+		recipientIDs = append(recipientIDs, n.RecipientID)
+		actorIDs = append(actorIDs, n.ActorID)
+	}
+
+	tokens, err := d.devices.GetDeviceTokens(ctx, recipientIDs)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	actorNames := make(map[primitive.ObjectID]string)
+	if users, err := d.actors.GetUsersByIDs(ctx, actorIDs); err == nil {
+		for _, u := range users {
+			name := u.DisplayName
+			if name == "" {
+				name = u.Username
+			}
+			actorNames[u.ID] = name
+		}
+	}
+
+	var invalid []string
+	var firstErr error
+	for _, n := range notifications {
+		userTokens := tokens[n.RecipientID]
+		if len(userTokens) == 0 {
+			continue
+		}
+
+		rejected, err := d.sender.Send(ctx, userTokens, buildPushMessage(n, actorNames[n.ActorID]))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		invalid = append(invalid, rejected...)
+	}
+
+	if err := d.devices.DeleteDeviceTokens(ctx, invalid); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// Headline describes a notification in one sentence, e.g. "Alice and 12 others liked
+// your anchor" (push titles, email digests). actorName is the latest actor's name.
+func Headline(n Notification, actorName string) string {
+	if actorName == "" {
+		actorName = "Someone"
+	}
+	switch others := n.TotalActors() - 1; {
+	case others == 1:
+		actorName += " and 1 other"
+	case others > 1:
+		actorName = fmt.Sprintf("%s and %d others", actorName, others)
+	}
+
+	switch n.Type {
+	case TypeLike:
+		return actorName + " liked your anchor"
+	case TypeComment:
+		return actorName + " commented on your anchor"
+	case TypeReply:
+		return actorName + " replied to your comment"
+	case TypeMention:
+		return actorName + " mentioned you"
+	case TypeFollow:
+		return actorName + " started following you"
+	case TypeClone:
+		return actorName + " cloned your anchor"
+	case TypeAnchorUpdate:
+		return actorName + " updated an anchor you follow"
+	case TypeFollowRequest:
+		return actorName + " requested to follow you"
+	case TypeFollowAccept:
+		return actorName + " accepted your follow request"
+	default:
+		return "New notification"
+	}
+}
+
+// buildPushMessage renders a notification as device-facing text
+func buildPushMessage(n Notification, actorName string) PushMessage {
+	title := Headline(n, actorName)
+
+	data := map[string]string{
+		"notificationId": n.ID.Hex(),
+		"type":           n.Type,
+		"resourceType":   n.ResourceType,
+		"resourceId":     n.ResourceID.Hex(),
+	}
+	if n.AnchorID != nil {
+		data["anchorId"] = n.AnchorID.Hex()
+	}
+	if n.ItemID != nil {
+		data["itemId"] = n.ItemID.Hex()
+	}
+
+	return PushMessage{
+		Title: title,
+		Body:  n.Preview,
+		Data:  data,
+	}
+}
@@ -0,0 +1,127 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryDeviceStore struct {
+	tokens map[primitive.ObjectID][]string
+}
+
+func (s *memoryDeviceStore) GetDeviceTokens(ctx context.Context, userIDs []primitive.ObjectID) (map[primitive.ObjectID][]string, error) {
+	result := make(map[primitive.ObjectID][]string)
+	for _, id := range userIDs {
+		if tokens, ok := s.tokens[id]; ok {
+			result[id] = tokens
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryDeviceStore) DeleteDeviceTokens(ctx context.Context, tokens []string) error {
+	drop := make(map[string]bool)
+	for _, t := range tokens {
+		drop[t] = true
+	}
+	for id, userTokens := range s.tokens {
+		kept := []string{}
+		for _, t := range userTokens {
+			if !drop[t] {
+				kept = append(kept, t)
+			}
+		}
+		s.tokens[id] = kept
+	}
+	return nil
+}
+
+type memoryActors []auth.User
+
+func (a memoryActors) GetUsersByIDs(ctx context.Context, userIDs []primitive.ObjectID) ([]auth.User, error) {
+	return a, nil
+}
+
+func TestPushDispatcher_SendsToRecipientDevices(t *testing.T) {
+	recipient := primitive.NewObjectID()
+	bystander := primitive.NewObjectID()
+	actor := auth.User{ID: primitive.NewObjectID(), Username: "alice", DisplayName: "Alice"}
+	anchorID := primitive.NewObjectID()
+
+	store := &memoryDeviceStore{tokens: map[primitive.ObjectID][]string{
+		recipient: {"phone", "tablet"},
+		bystander: {"other"},
+	}}
+	sender := &RecordingPushSender{}
+	dispatcher := NewPushDispatcher(store, memoryActors{actor}, sender)
+
+	err := dispatcher.Dispatch(context.Background(), []Notification{{
+		ID:          primitive.NewObjectID(),
+		RecipientID: recipient,
+		ActorID:     actor.ID,
+		Type:        TypeLike,
+		ResourceID:  anchorID,
+		AnchorID:    &anchorID,
+		Preview:     "Weekend hikes",
+	}})
+	require.NoError(t, err)
+
+	pushes := sender.Pushes()
+	require.Len(t, pushes, 1)
+	require.Equal(t, []string{"phone", "tablet"}, pushes[0].Tokens)
+	require.Equal(t, "Alice liked your anchor", pushes[0].Message.Title)
+	require.Equal(t, "Weekend hikes", pushes[0].Message.Body)
+	require.Equal(t, anchorID.Hex(), pushes[0].Message.Data["anchorId"])
+}
+
+func TestPushDispatcher_PrunesInvalidTokens(t *testing.T) {
+	recipient := primitive.NewObjectID()
+	store := &memoryDeviceStore{tokens: map[primitive.ObjectID][]string{
+		recipient: {"stale", "fresh"},
+	}}
+	sender := &RecordingPushSender{Invalid: map[string]bool{"stale": true}}
+	dispatcher := NewPushDispatcher(store, memoryActors{}, sender)
+
+	err := dispatcher.Dispatch(context.Background(), []Notification{{
+		ID:          primitive.NewObjectID(),
+		RecipientID: recipient,
+		ActorID:     primitive.NewObjectID(),
+		Type:        TypeFollow,
+	}})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"fresh"}, store.tokens[recipient])
+	require.Equal(t, "Someone started following you", sender.Pushes()[0].Message.Title)
+}
+
+func TestPushDispatcher_SkipsUsersWithoutDevices(t *testing.T) {
+	sender := &RecordingPushSender{}
+	dispatcher := NewPushDispatcher(&memoryDeviceStore{tokens: map[primitive.ObjectID][]string{}}, memoryActors{}, sender)
+
+	err := dispatcher.Dispatch(context.Background(), []Notification{{
+		ID:          primitive.NewObjectID(),
+		RecipientID: primitive.NewObjectID(),
+		Type:        TypeComment,
+	}})
+	require.NoError(t, err)
+	require.Empty(t, sender.Pushes())
+}
+
+func TestBuildPushMessage_Grouped(t *testing.T) {
+	anchorID := primitive.NewObjectID()
+	n := Notification{
+		Type:       TypeLike,
+		ResourceID: anchorID,
+		AnchorID:   &anchorID,
+		GroupKey:   TypeLike + ":" + anchorID.Hex(),
+		ActorCount: 13,
+	}
+	require.Equal(t, "Alice and 12 others liked your anchor", buildPushMessage(n, "Alice").Title)
+
+	n.ActorCount = 2
+	require.Equal(t, "Alice and 1 other liked your anchor", buildPushMessage(n, "Alice").Title)
+}
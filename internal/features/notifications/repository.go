@@ -11,8 +11,13 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// readAtIndex is the TTL index that expires read notifications
+const readAtIndex = "readAt_ttl"
+
 type Repository struct {
 	collection *mongo.Collection
+	devices    *mongo.Collection
+	settings   *mongo.Collection
 }
 
 func NewRepository(db *mongo.Database) *Repository {
@@ -36,9 +41,58 @@ func NewRepository(db *mongo.Database) *Repository {
 		{
 			Keys: bson.D{{Key: "createdAt", Value: 1}},
 		},
+		{
+			// Cursor pagination
+			Keys: bson.D{
+				{Key: "recipientId", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			// Notifications triggered by a user (account deletion)
+			Keys: bson.D{{Key: "actorId", Value: 1}},
+		},
+		{
+			// Open group lookup
+			Keys: bson.D{
+				{Key: "recipientId", Value: 1},
+				{Key: "groupKey", Value: 1},
+				{Key: "groupStartedAt", Value: -1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			// Groups a user appears in (account deletion)
+			Keys:    bson.D{{Key: "recentActorIds", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "actorIds", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	})
+
+	devices := db.Collection("devices")
+	devices.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// A push token belongs to one install, so to at most one user
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "lastSeenAt", Value: -1},
+			},
+		},
 	})
 
-	return &Repository{collection: collection}
+	return &Repository{
+		collection: collection,
+		devices:    devices,
+		settings:   db.Collection("notificationSettings"), // Keyed by user ID
+	}
 }
 
 // CreateNotification creates a single notification
@@ -47,8 +101,12 @@ func (r *Repository) CreateNotification(ctx context.Context, notification *Notif
 	notification.CreatedAt = time.Now()
 	notification.IsRead = false
 
-	_, err := r.collection.InsertOne(ctx, notification)
-	return err
+	if _, err := r.collection.InsertOne(ctx, notification); err != nil {
+		return err
+	}
+
+	publishCreated([]Notification{*notification})
+	return nil
 }
 
 // CreateMany creates multiple notifications
@@ -59,14 +117,20 @@ func (r *Repository) CreateMany(ctx context.Context, notifications []Notificatio
 
 	docs := make([]interface{}, len(notifications))
 	for i := range notifications {
-		notifications[i].ID = primitive.NewObjectID()
+		if notifications[i].ID.IsZero() {
+			notifications[i].ID = primitive.NewObjectID()
+		}
 		notifications[i].CreatedAt = time.Now()
 		notifications[i].IsRead = false
 		docs[i] = notifications[i]
 	}
 
-	_, err := r.collection.InsertMany(ctx, docs)
-	return err
+	if _, err := r.collection.InsertMany(ctx, docs); err != nil {
+		return err
+	}
+
+	publishCreated(notifications)
+	return nil
 }
 
 // CreateNotifications creates multiple notifications at once
@@ -80,8 +144,105 @@ func (r *Repository) CreateNotifications(ctx context.Context, notifications []No
 		docs[i] = n
 	}
 
-	_, err := r.collection.InsertMany(ctx, docs)
-	return err
+	if _, err := r.collection.InsertMany(ctx, docs); err != nil {
+		return err
+	}
+
+	publishCreated(notifications)
+	return nil
+}
+
+// CreateGrouped adds a notification to the recipient's open group for its key, or
+// starts a new group when none is open. The group moves to the top of the list and
+// becomes unread again. It returns the group as stored, and false if the actor was
+// already in the group (nothing changed).
+func (r *Repository) CreateGrouped(ctx context.Context, notification *Notification, key string) (*Notification, bool, error) {
+	now := time.Now()
+	open := bson.M{
+		"recipientId":    notification.RecipientID,
+		"groupKey":       key,
+		"groupStartedAt": bson.M{"$gt": now.Add(-GroupWindow)},
+	}
+
+	// Join an open group the actor is not already in. recentActorIds is checked too for
+	// groups started before actorIds was kept.
+	joinFilter := bson.M{
+		"actorIds":       bson.M{"$ne": notification.ActorID},
+		"recentActorIds": bson.M{"$ne": notification.ActorID},
+	}
+	for k, v := range open {
+		joinFilter[k] = v
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "groupStartedAt", Value: -1}}).
+		SetReturnDocument(options.After)
+
+	var group Notification
+	err := r.collection.FindOneAndUpdate(ctx, joinFilter, bson.M{
+		"$set": bson.M{
+			"actorId":   notification.ActorID,
+			"preview":   notification.Preview,
+			"isRead":    false,
+			"createdAt": now,
+		},
+		"$unset":    bson.M{"readAt": ""},
+		"$inc":      bson.M{"actorCount": 1},
+		"$addToSet": bson.M{"actorIds": notification.ActorID},
+		"$push": bson.M{"recentActorIds": bson.M{
+			"$each":     []primitive.ObjectID{notification.ActorID},
+			"$position": 0,
+			"$slice":    MaxRecentActors,
+		}},
+	}, opts).Decode(&group)
+	if err == nil {
+		publishCreated([]Notification{group})
+		return &group, true, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, false, err
+	}
+
+	// The actor repeated themselves (e.g. unlike then like again)
+	repeatFilter := bson.M{"$or": bson.A{
+		bson.M{"actorIds": notification.ActorID},
+		bson.M{"recentActorIds": notification.ActorID},
+	}}
+	for k, v := range open {
+		repeatFilter[k] = v
+	}
+	if err := r.collection.FindOne(ctx, repeatFilter).Err(); err == nil {
+		return nil, false, nil
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, false, err
+	}
+
+	// Start a new group
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	notification.CreatedAt = now
+	notification.IsRead = false
+	notification.GroupKey = key
+	notification.ActorCount = 1
+	notification.ActorIDs = []primitive.ObjectID{notification.ActorID}
+	notification.RecentActorIDs = []primitive.ObjectID{notification.ActorID}
+	notification.GroupStartedAt = &now
+
+	if _, err := r.collection.InsertOne(ctx, notification); err != nil {
+		return nil, false, err
+	}
+
+	publishCreated([]Notification{*notification})
+	return notification, true, nil
+}
+
+// publishCreated pushes freshly inserted notifications to their recipients' open streams
+func publishCreated(notifications []Notification) {
+	hub := DefaultHub()
+	for i := range notifications {
+		n := notifications[i]
+		hub.Publish(n.RecipientID, Event{Type: EventNotification, Notification: &n})
+	}
 }
 
 // GetNotificationByID retrieves a notification by ID
@@ -97,7 +258,39 @@ func (r *Repository) GetNotificationByID(ctx context.Context, notificationID pri
 	return &notification, nil
 }
 
-// GetUserNotifications retrieves notifications for a user
+// GetUserNotificationsPage retrieves notifications for a user, newest first, starting after the cursor
+func (r *Repository) GetUserNotificationsPage(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, cursor *NotificationCursor, limit int) ([]Notification, error) {
+	conditions := bson.A{bson.M{"recipientId": userID}}
+	if unreadOnly {
+		conditions = append(conditions, bson.M{"isRead": false})
+	}
+	if cursor != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"createdAt": bson.M{"$lt": cursor.Timestamp}},
+			bson.M{"createdAt": cursor.Timestamp, "_id": bson.M{"$lt": cursor.NotificationID}},
+		}})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cur, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	notifications := []Notification{}
+	if err = cur.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// GetUserNotifications retrieves a page of notifications for a user, unread first
+//
+// Deprecated: offset pagination skips or repeats items as notifications arrive; use GetUserNotificationsPage
 func (r *Repository) GetUserNotifications(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, page, limit int) ([]Notification, int64, error) {
 	filter := bson.M{"recipientId": userID}
 	if unreadOnly {
@@ -132,6 +325,66 @@ func (r *Repository) GetUserNotifications(ctx context.Context, userID primitive.
 	return notifications, total, nil
 }
 
+// GetNotificationsAfter returns up to limit notifications a user received, or whose group was
+// updated, after the given position, oldest first (used to resume a notification stream)
+func (r *Repository) GetNotificationsAfter(ctx context.Context, userID primitive.ObjectID, after *NotificationCursor, limit int) ([]Notification, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"recipientId": userID,
+		"$or": bson.A{
+			bson.M{"createdAt": bson.M{"$gt": after.Timestamp}},
+			bson.M{"createdAt": after.Timestamp, "_id": bson.M{"$gt": after.NotificationID}},
+		},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []Notification{}
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// GetUnreadSince returns a user's unread notifications of the given types that arrived
+// (or, for groups, last changed) after since, newest first, with their total count
+func (r *Repository) GetUnreadSince(ctx context.Context, userID primitive.ObjectID, types []string, since time.Time, limit int) ([]Notification, int64, error) {
+	filter := bson.M{
+		"recipientId": userID,
+		"isRead":      false,
+		"type":        bson.M{"$in": types},
+		"createdAt":   bson.M{"$gt": since},
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []Notification{}, 0, nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []Notification{}
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
 // CountUnread counts unread notifications for a user
 func (r *Repository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
 	return r.collection.CountDocuments(ctx, bson.M{
@@ -145,7 +398,7 @@ func (r *Repository) MarkAsRead(ctx context.Context, notificationID primitive.Ob
 	result, err := r.collection.UpdateOne(
 		ctx,
 		bson.M{"_id": notificationID},
-		bson.M{"$set": bson.M{"isRead": true}},
+		bson.M{"$set": bson.M{"isRead": true, "readAt": time.Now()}},
 	)
 	if err != nil {
 		return err
@@ -161,10 +414,302 @@ func (r *Repository) MarkAllAsRead(ctx context.Context, userID primitive.ObjectI
 	result, err := r.collection.UpdateMany(
 		ctx,
 		bson.M{"recipientId": userID, "isRead": false},
-		bson.M{"$set": bson.M{"isRead": true}},
+		bson.M{"$set": bson.M{"isRead": true, "readAt": time.Now()}},
 	)
 	if err != nil {
 		return 0, err
 	}
 	return result.ModifiedCount, nil
 }
+
+// DeleteNotification deletes one of a user's notifications
+func (r *Repository) DeleteNotification(ctx context.Context, userID, notificationID primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": notificationID, "recipientId": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+// DeleteNotifications deletes the given notifications of a user and, if read is set,
+// all of their read notifications. It returns how many were deleted.
+func (r *Repository) DeleteNotifications(ctx context.Context, userID primitive.ObjectID, notificationIDs []primitive.ObjectID, read bool) (int64, error) {
+	var targets bson.A
+	if len(notificationIDs) > 0 {
+		targets = append(targets, bson.M{"_id": bson.M{"$in": notificationIDs}})
+	}
+	if read {
+		targets = append(targets, bson.M{"isRead": true})
+	}
+	if len(targets) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"recipientId": userID, "$or": targets})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// EnsureRetention makes read notifications expire days after they were read, or keeps
+// them forever when days is 0. Read notifications from before readAt existed count as
+// read now.
+func (r *Repository) EnsureRetention(ctx context.Context, days int) error {
+	if days <= 0 {
+		_, err := r.collection.Indexes().DropOne(ctx, readAtIndex)
+		var cmdErr mongo.CommandError
+		if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound") {
+			return err
+		}
+		return nil
+	}
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"isRead": true, "readAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"readAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	expireAfter := int32(days * 24 * 60 * 60)
+	_, err = r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "readAt", Value: 1}},
+		Options: options.Index().SetName(readAtIndex).SetExpireAfterSeconds(expireAfter),
+	})
+	var cmdErr mongo.CommandError
+	if err != nil && errors.As(err, &cmdErr) && cmdErr.Name == "IndexOptionsConflict" {
+		// The retention period changed; update the existing index in place
+		return r.collection.Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: r.collection.Name()},
+			{Key: "index", Value: bson.D{
+				{Key: "name", Value: readAtIndex},
+				{Key: "expireAfterSeconds", Value: expireAfter},
+			}},
+		}).Err()
+	}
+	return err
+}
+
+// DeleteAllForUser removes every notification a user received or triggered, their devices and settings
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	// Drop them from groups someone else now leads
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{
+			"$or":     bson.A{bson.M{"actorIds": userID}, bson.M{"recentActorIds": userID}},
+			"actorId": bson.M{"$ne": userID},
+		},
+		bson.M{
+			"$pull": bson.M{"actorIds": userID, "recentActorIds": userID},
+			"$inc":  bson.M{"actorCount": -1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{
+		"$or": []bson.M{
+			{"recipientId": userID},
+			{"actorId": userID},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = r.devices.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return err
+	}
+
+	_, err = r.settings.DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
+
+// GetAllForRecipient returns every notification a user received, newest first (for data export)
+func (r *Repository) GetAllForRecipient(ctx context.Context, userID primitive.ObjectID) ([]Notification, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"recipientId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []Notification{}
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// RegisterDevice stores a push token for a user. A token already registered (even by
+// another user, e.g. after switching accounts on the same phone) moves to this user.
+func (r *Repository) RegisterDevice(ctx context.Context, userID primitive.ObjectID, token, platform string) (*Device, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var device Device
+	err := r.devices.FindOneAndUpdate(ctx,
+		bson.M{"token": token},
+		bson.M{
+			"$set": bson.M{
+				"userId":     userID,
+				"platform":   platform,
+				"lastSeenAt": now,
+			},
+			"$setOnInsert": bson.M{"createdAt": now},
+		},
+		opts,
+	).Decode(&device)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.trimDevices(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// trimDevices drops a user's least recently seen devices beyond MaxDevicesPerUser
+func (r *Repository) trimDevices(ctx context.Context, userID primitive.ObjectID) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "lastSeenAt", Value: -1}}).
+		SetSkip(MaxDevicesPerUser).
+		SetProjection(bson.M{"_id": 1})
+
+	cursor, err := r.devices.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var stale []Device
+	if err = cursor.All(ctx, &stale); err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, len(stale))
+	for i, d := range stale {
+		ids[i] = d.ID
+	}
+	_, err = r.devices.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// GetUserDevices returns a user's registered devices, oldest first (for data export)
+func (r *Repository) GetUserDevices(ctx context.Context, userID primitive.ObjectID) ([]Device, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.devices.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	devices := []Device{}
+	if err = cursor.All(ctx, &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// UnregisterDevice removes one of a user's push tokens
+func (r *Repository) UnregisterDevice(ctx context.Context, userID primitive.ObjectID, token string) error {
+	result, err := r.devices.DeleteOne(ctx, bson.M{"userId": userID, "token": token})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("device not found")
+	}
+	return nil
+}
+
+// GetDeviceTokens returns the push tokens of each of the given users
+func (r *Repository) GetDeviceTokens(ctx context.Context, userIDs []primitive.ObjectID) (map[primitive.ObjectID][]string, error) {
+	tokens := make(map[primitive.ObjectID][]string)
+	if len(userIDs) == 0 {
+		return tokens, nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"userId": 1, "token": 1})
+	cursor, err := r.devices.Find(ctx, bson.M{"userId": bson.M{"$in": userIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var devices []Device
+	if err = cursor.All(ctx, &devices); err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		tokens[d.UserID] = append(tokens[d.UserID], d.Token)
+	}
+	return tokens, nil
+}
+
+// DeleteDeviceTokens removes push tokens the push service rejected
+func (r *Repository) DeleteDeviceTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	_, err := r.devices.DeleteMany(ctx, bson.M{"token": bson.M{"$in": tokens}})
+	return err
+}
+
+// GetNotificationSettings returns a user's stored settings, or nil if they never saved any
+func (r *Repository) GetNotificationSettings(ctx context.Context, userID primitive.ObjectID) (*NotificationSettings, error) {
+	var settings NotificationSettings
+	err := r.settings.FindOne(ctx, bson.M{"_id": userID}).Decode(&settings)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetNotificationSettingsForUsers returns the stored settings of each given user that has any
+func (r *Repository) GetNotificationSettingsForUsers(ctx context.Context, userIDs []primitive.ObjectID) (map[primitive.ObjectID]*NotificationSettings, error) {
+	result := make(map[primitive.ObjectID]*NotificationSettings)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	cursor, err := r.settings.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var settings []NotificationSettings
+	if err = cursor.All(ctx, &settings); err != nil {
+		return nil, err
+	}
+	for i := range settings {
+		result[settings[i].UserID] = &settings[i]
+	}
+	return result, nil
+}
+
+// SaveNotificationSettings replaces a user's settings
+func (r *Repository) SaveNotificationSettings(ctx context.Context, settings *NotificationSettings) error {
+	settings.UpdatedAt = time.Now()
+	_, err := r.settings.ReplaceOne(ctx,
+		bson.M{"_id": settings.UserID},
+		settings,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
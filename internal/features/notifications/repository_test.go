@@ -0,0 +1,44 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/pkg/testdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateGroupedCountsDistinctActors(t *testing.T) {
+	repo := NewRepository(testdb.New(t))
+	ctx := context.Background()
+
+	recipient := primitive.NewObjectID()
+	anchorID := primitive.NewObjectID()
+	like := func(actor primitive.ObjectID) (*Notification, bool) {
+		n := &Notification{RecipientID: recipient, ActorID: actor, Type: TypeLike, ResourceID: anchorID}
+		key, _ := groupKey(n)
+		group, changed, err := repo.CreateGrouped(ctx, n, key)
+		require.NoError(t, err)
+		return group, changed
+	}
+
+	actors := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	var group *Notification
+	for _, actor := range actors {
+		group, _ = like(actor)
+	}
+	require.Equal(t, 4, group.TotalActors())
+	require.Len(t, group.RecentActorIDs, MaxRecentActors)
+	require.NotContains(t, group.RecentActorIDs, actors[0])
+
+	// The first actor dropped out of the recent actors but is still in the group
+	again, changed := like(actors[0])
+	require.False(t, changed)
+	require.Nil(t, again)
+
+	stored, err := repo.GetNotificationByID(ctx, group.ID)
+	require.NoError(t, err)
+	require.Equal(t, 4, stored.TotalActors())
+	require.Len(t, stored.ActorIDs, 4)
+}
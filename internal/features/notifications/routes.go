@@ -1,6 +1,9 @@
 package notifications
 
 import (
+	"context"
+	"log"
+
 	"github.com/gin-gonic/gin"
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
@@ -16,8 +19,24 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	// Initialize handler
 	handler := NewHandler(repo, authRepo, contentProvider, cfg)
 
-	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+	// Expire read notifications after the retention period
+	if err := repo.EnsureRetention(context.Background(), cfg.NotificationRetentionDays); err != nil {
+		log.Printf("Failed to configure notification retention: %v", err)
+	}
+
+	// Push delivery to registered devices
+	if cfg.PushEnabled {
+		sender, err := NewFCMSender(context.Background(), cfg)
+		if err != nil {
+			log.Printf("Failed to initialize push notifications: %v", err)
+		} else {
+			SetPushDispatcher(NewPushDispatcher(repo, authRepo, sender))
+		}
+	}
+
+	// Initialize middleware (personal access tokens need notifications:read / notifications:write)
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg, auth.ScopeNotificationsRead)
+	writeMiddleware := middleware.NewAuthMiddleware(authRepo, cfg, auth.ScopeNotificationsWrite)
 
 	// Notification routes
 	notifications := router.Group("/notifications")
@@ -25,8 +44,41 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	{
 		notifications.GET("", handler.ListNotifications)
 		notifications.GET("/unread-count", handler.GetUnreadCount)
-		notifications.PATCH("/:id/read", handler.MarkAsRead)
-		notifications.PATCH("/read-all", handler.MarkAllAsRead)
+	}
+
+	// Marking read and managing push devices change state
+	updates := router.Group("/notifications")
+	updates.Use(writeMiddleware)
+	{
+		updates.PATCH("/:id/read", handler.MarkAsRead)
+		updates.PATCH("/read-all", handler.MarkAllAsRead)
+		updates.POST("/devices", handler.RegisterDevice)
+		updates.DELETE("/devices/:token", handler.UnregisterDevice)
+	}
+
+	// Deleting needs a session, not a personal access token
+	sessionMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+	deletions := router.Group("/notifications")
+	deletions.Use(sessionMiddleware)
+	{
+		deletions.DELETE("", handler.DeleteNotifications)
+		deletions.DELETE("/:id", handler.DeleteNotification)
+	}
+
+	// Delivery preferences; changing them needs a session, not a personal access token
+	users := router.Group("/users")
+	{
+		users.GET("/me/notification-settings", authMiddleware, handler.GetNotificationSettings)
+		users.PUT("/me/notification-settings", sessionMiddleware, handler.UpdateNotificationSettings)
+	}
+
+	// Real-time streams; EventSource and WebSocket clients cannot set headers, so the
+	// token may also come from ?access_token=
+	streams := router.Group("/notifications")
+	streams.Use(middleware.TokenFromQuery(), authMiddleware)
+	{
+		streams.GET("/stream", handler.StreamNotifications)
+		streams.GET("/ws", handler.StreamNotificationsWS)
 	}
 }
 
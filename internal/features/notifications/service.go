@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"github.com/xyz-asif/gotodo/internal/pkg/jobqueue"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -12,21 +15,27 @@ import (
 // FollowerProvider defines an interface to fetch anchor followers
 // This helps break the circular dependency with anchor_follows package
 type FollowerProvider interface {
-	GetFollowersWithNotifications(ctx context.Context, anchorID primitive.ObjectID) ([]primitive.ObjectID, error)
+	GetFollowersWithNotificationsAfter(ctx context.Context, anchorID, afterID primitive.ObjectID, limit int) ([]primitive.ObjectID, primitive.ObjectID, error)
 }
 
 type Service struct {
 	repo             *Repository
 	authRepo         *auth.Repository
+	mutesRepo        *mutes.Repository
+	blockPolicy      *safety.BlockPolicy
 	db               *mongo.Database
+	queue            *jobqueue.Queue
 	followerProvider FollowerProvider
 }
 
 func NewService(repo *Repository, authRepo *auth.Repository, db *mongo.Database) *Service {
 	return &Service{
-		repo:     repo,
-		authRepo: authRepo,
-		db:       db,
+		repo:        repo,
+		authRepo:    authRepo,
+		mutesRepo:   mutes.NewRepository(db),
+		blockPolicy: safety.NewBlockPolicy(db),
+		db:          db,
+		queue:       jobqueue.NewQueue(db),
 	}
 }
 
@@ -38,8 +47,11 @@ func (s *Service) SetFollowerProvider(provider FollowerProvider) {
 type CommentData struct {
 	ID       primitive.ObjectID
 	AnchorID primitive.ObjectID
+	ItemID   *primitive.ObjectID // Item the comment targets, nil for anchor-level comments
 	Content  string
 	Mentions []primitive.ObjectID
+	// ParentAuthorID is the author of the comment being replied to, nil for top-level comments
+	ParentAuthorID *primitive.ObjectID
 }
 
 // CreateCommentNotifications creates notifications for a new comment
@@ -53,8 +65,8 @@ func (s *Service) CreateCommentNotifications(ctx context.Context, comment *Comme
 			continue
 		}
 
-		// Check if Recipient has blocked Actor
-		if s.isBlocked(ctx, mentionedUserID, actor.ID) {
+		// Check if Recipient has blocked or muted Actor
+		if s.isBlocked(ctx, mentionedUserID, actor.ID) || s.isMuted(ctx, mentionedUserID, actor.ID) {
 			continue
 		}
 
@@ -65,17 +77,40 @@ func (s *Service) CreateCommentNotifications(ctx context.Context, comment *Comme
 			ResourceType: "comment",
 			ResourceID:   comment.ID,
 			AnchorID:     &comment.AnchorID,
+			ItemID:       comment.ItemID,
 			Preview:      truncate(comment.Content, 100),
 		})
 	}
 
-	// 2. Comment notification to anchor owner
+	// 2. Reply notification to the parent comment's author
+	// Skip if:
+	// - Replying to own comment
+	// - Parent author was already mentioned (avoid duplicate)
+	repliedTo := comment.ParentAuthorID != nil && !comment.ParentAuthorID.IsZero()
+	if repliedTo && *comment.ParentAuthorID != actor.ID && !containsID(comment.Mentions, *comment.ParentAuthorID) {
+		recipientID := *comment.ParentAuthorID
+		if !(s.isBlocked(ctx, recipientID, actor.ID) || s.isMuted(ctx, recipientID, actor.ID)) {
+			notifications = append(notifications, Notification{
+				RecipientID:  recipientID,
+				ActorID:      actor.ID,
+				Type:         TypeReply,
+				ResourceType: "comment",
+				ResourceID:   comment.ID,
+				AnchorID:     &comment.AnchorID,
+				ItemID:       comment.ItemID,
+				Preview:      truncate(comment.Content, 100),
+			})
+		}
+	}
+
+	// 3. Comment notification to anchor owner
 	// Skip if:
 	// - Commenter is anchor owner (self-comment)
-	// - Anchor owner was already mentioned (avoid duplicate)
-	if anchorUserID != actor.ID && !containsID(comment.Mentions, anchorUserID) {
-		// Check if Owner has blocked Actor
-		if !s.isBlocked(ctx, anchorUserID, actor.ID) {
+	// - Anchor owner was already mentioned or replied to (avoid duplicate)
+	ownerRepliedTo := repliedTo && *comment.ParentAuthorID == anchorUserID
+	if anchorUserID != actor.ID && !containsID(comment.Mentions, anchorUserID) && !ownerRepliedTo {
+		// Check if Owner has blocked or muted Actor
+		if !(s.isBlocked(ctx, anchorUserID, actor.ID) || s.isMuted(ctx, anchorUserID, actor.ID)) {
 			notifications = append(notifications, Notification{
 				RecipientID:  anchorUserID,
 				ActorID:      actor.ID,
@@ -83,6 +118,7 @@ func (s *Service) CreateCommentNotifications(ctx context.Context, comment *Comme
 				ResourceType: "anchor",
 				ResourceID:   anchorID,
 				AnchorID:     &anchorID,
+				ItemID:       comment.ItemID,
 				Preview:      truncate(comment.Content, 100),
 			})
 		}
@@ -90,7 +126,7 @@ func (s *Service) CreateCommentNotifications(ctx context.Context, comment *Comme
 
 	// Batch insert
 	if len(notifications) > 0 {
-		return s.repo.CreateMany(ctx, notifications)
+		return s.deliver(ctx, notifications)
 	}
 
 	return nil
@@ -122,8 +158,8 @@ func (s *Service) CreateEditCommentNotifications(ctx context.Context, comment *C
 			continue
 		}
 
-		// Check if Recipient has blocked Actor
-		if s.isBlocked(ctx, mentionedUserID, actor.ID) {
+		// Check if Recipient has blocked or muted Actor
+		if s.isBlocked(ctx, mentionedUserID, actor.ID) || s.isMuted(ctx, mentionedUserID, actor.ID) {
 			continue
 		}
 
@@ -134,12 +170,13 @@ func (s *Service) CreateEditCommentNotifications(ctx context.Context, comment *C
 			ResourceType: "comment",
 			ResourceID:   comment.ID,
 			AnchorID:     &comment.AnchorID,
+			ItemID:       comment.ItemID,
 			Preview:      truncate(comment.Content, 100),
 		})
 	}
 
 	if len(notifications) > 0 {
-		return s.repo.CreateMany(ctx, notifications)
+		return s.deliver(ctx, notifications)
 	}
 
 	return nil
@@ -170,8 +207,8 @@ func (s *Service) CreateLikeNotification(ctx context.Context, anchorID primitive
 		return nil
 	}
 
-	// Check if Owner has blocked Actor
-	if s.isBlocked(ctx, ownerID, actorID) {
+	// Check if Owner has blocked or muted Actor
+	if s.isBlocked(ctx, ownerID, actorID) || s.isMuted(ctx, ownerID, actorID) {
 		return nil
 	}
 
@@ -185,7 +222,7 @@ func (s *Service) CreateLikeNotification(ctx context.Context, anchorID primitive
 		Preview:      truncate(anchorTitle, 100),
 	}
 
-	return s.repo.CreateNotification(ctx, &notification)
+	return s.deliver(ctx, []Notification{notification})
 }
 
 // CreateFollowNotification creates notification when someone follows a user
@@ -195,8 +232,8 @@ func (s *Service) CreateFollowNotification(ctx context.Context, actorID, targetU
 		return nil
 	}
 
-	// Check if Target (Recipient) has blocked Actor
-	if s.isBlocked(ctx, targetUserID, actorID) {
+	// Check if Target (Recipient) has blocked or muted Actor
+	if s.isBlocked(ctx, targetUserID, actorID) || s.isMuted(ctx, targetUserID, actorID) {
 		return nil
 	}
 
@@ -210,7 +247,52 @@ func (s *Service) CreateFollowNotification(ctx context.Context, actorID, targetU
 		Preview:      "",
 	}
 
-	return s.repo.CreateNotification(ctx, &notification)
+	return s.deliver(ctx, []Notification{notification})
+}
+
+// CreateFollowRequestNotification creates notification when someone asks to follow a private account
+func (s *Service) CreateFollowRequestNotification(ctx context.Context, actorID, targetUserID primitive.ObjectID) error {
+	// No self-notification
+	if actorID == targetUserID {
+		return nil
+	}
+
+	// Check if Target (Recipient) has blocked or muted Actor
+	if s.isBlocked(ctx, targetUserID, actorID) || s.isMuted(ctx, targetUserID, actorID) {
+		return nil
+	}
+
+	notification := Notification{
+		RecipientID:  targetUserID,
+		ActorID:      actorID,
+		Type:         TypeFollowRequest,
+		ResourceType: "user",
+		ResourceID:   actorID, // The requester is the resource
+		AnchorID:     nil,
+		Preview:      "",
+	}
+
+	return s.deliver(ctx, []Notification{notification})
+}
+
+// CreateFollowAcceptNotification notifies the requester that their follow request was approved
+func (s *Service) CreateFollowAcceptNotification(ctx context.Context, actorID, requesterID primitive.ObjectID) error {
+	// No self-notification
+	if actorID == requesterID {
+		return nil
+	}
+
+	notification := Notification{
+		RecipientID:  requesterID,
+		ActorID:      actorID,
+		Type:         TypeFollowAccept,
+		ResourceType: "user",
+		ResourceID:   actorID, // The approving account is the resource
+		AnchorID:     nil,
+		Preview:      "",
+	}
+
+	return s.deliver(ctx, []Notification{notification})
 }
 
 // CreateCloneNotification creates notification when someone clones an anchor
@@ -220,8 +302,8 @@ func (s *Service) CreateCloneNotification(ctx context.Context, clonedAnchorID, o
 		return nil
 	}
 
-	// Check if Owner has blocked Actor
-	if s.isBlocked(ctx, ownerID, actorID) {
+	// Check if Owner has blocked or muted Actor
+	if s.isBlocked(ctx, ownerID, actorID) || s.isMuted(ctx, ownerID, actorID) {
 		return nil
 	}
 
@@ -235,45 +317,131 @@ func (s *Service) CreateCloneNotification(ctx context.Context, clonedAnchorID, o
 		Preview:      truncate(anchorTitle, 100),
 	}
 
-	return s.repo.CreateNotification(ctx, &notification)
+	return s.deliver(ctx, []Notification{notification})
 }
 
-func (s *Service) isBlocked(ctx context.Context, recipientID, actorID primitive.ObjectID) bool {
-	user, err := s.authRepo.GetUserByObjectID(ctx, recipientID)
-	if err != nil || user == nil {
-		return false // Default to not blocked if error
+// deliver routes notifications by each recipient's settings: stored (and streamed) if
+// in-app is on for the type, pushed if push is on and the recipient is not in quiet hours
+// (suppressed pushes are not retried once quiet hours end).
+// Groupable types are folded into the recipient's open group (see groupKey).
+func (s *Service) deliver(ctx context.Context, notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
 	}
-	for _, id := range user.BlockedUsers {
-		if id == actorID {
-			return true
+
+	recipientIDs := make([]primitive.ObjectID, 0, len(notifications))
+	for _, n := range notifications {
+		recipientIDs = append(recipientIDs, n.RecipientID)
+	}
+
+	// Fall back to the defaults rather than dropping notifications if settings can't be read
+	stored, err := s.repo.GetNotificationSettingsForUsers(ctx, recipientIDs)
+	if err != nil {
+		stored = nil
+	}
+
+	now := time.Now()
+	var inApp, push []Notification
+	for i := range notifications {
+		n := notifications[i]
+		if n.ID.IsZero() {
+			n.ID = primitive.NewObjectID() // Shared by the stored copy and the push
+		}
+
+		settings := settingsFor(stored, n.RecipientID)
+		channels := settings.Channels(n.Type)
+		if channels.InApp {
+			inApp = append(inApp, n)
+		}
+		if channels.Push && !settings.InQuietHours(now) {
+			push = append(push, n)
 		}
 	}
-	return false
+
+	var single []Notification
+	for i := range inApp {
+		key, ok := groupKey(&inApp[i])
+		if !ok {
+			single = append(single, inApp[i])
+			continue
+		}
+
+		group, changed, err := s.repo.CreateGrouped(ctx, &inApp[i], key)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			// Repeated by the same actor: don't push it again either
+			push = withoutNotification(push, inApp[i].ID)
+			continue
+		}
+		// Push the group so the device reads "Alice and 12 others..."
+		push = replaceNotification(push, inApp[i].ID, *group)
+	}
+
+	if len(single) > 0 {
+		if err := s.repo.CreateMany(ctx, single); err != nil {
+			return err
+		}
+	}
+
+	dispatchPush(push)
+	return nil
 }
 
-// CreateAnchorUpdateNotifications notifies followers about anchor updates
-func (s *Service) CreateAnchorUpdateNotifications(ctx context.Context, anchorID primitive.ObjectID, anchorTitle string, authorID primitive.ObjectID) error {
-	if s.followerProvider == nil {
-		return nil
+func withoutNotification(notifications []Notification, id primitive.ObjectID) []Notification {
+	kept := notifications[:0]
+	for _, n := range notifications {
+		if n.ID != id {
+			kept = append(kept, n)
+		}
 	}
+	return kept
+}
 
-	// Get all follower IDs with notifyOnUpdate=true
-	followerIDs, err := s.followerProvider.GetFollowersWithNotifications(ctx, anchorID)
-	if err != nil {
-		return err
+func replaceNotification(notifications []Notification, id primitive.ObjectID, replacement Notification) []Notification {
+	for i := range notifications {
+		if notifications[i].ID == id {
+			notifications[i] = replacement
+		}
+	}
+	return notifications
+}
+
+func (s *Service) isBlocked(ctx context.Context, recipientID, actorID primitive.ObjectID) bool {
+	return s.blockPolicy.IsBlocked(ctx, recipientID, actorID)
+}
+
+// isMuted reports whether the recipient currently mutes the actor
+func (s *Service) isMuted(ctx context.Context, recipientID, actorID primitive.ObjectID) bool {
+	if s.mutesRepo == nil {
+		return false
 	}
+	muted, err := s.mutesRepo.IsUserMuted(ctx, recipientID, actorID)
+	return err == nil && muted // Default to not muted if error
+}
 
+// CreateAnchorUpdateNotifications notifies the given followers about an anchor update
+func (s *Service) CreateAnchorUpdateNotifications(ctx context.Context, anchorID primitive.ObjectID, anchorTitle string, authorID primitive.ObjectID, followerIDs []primitive.ObjectID) error {
+	// Followers who blocked the author, or were blocked by them, don't hear about updates
+	followerIDs = s.blockPolicy.FilterBlocked(ctx, authorID, followerIDs)
 	if len(followerIDs) == 0 {
 		return nil
 	}
 
+	// Neither do followers who muted the author
+	mutedBy := make(map[primitive.ObjectID]bool)
+	if s.mutesRepo != nil {
+		mutedBy, _ = s.mutesRepo.GetUsersMuting(ctx, authorID, followerIDs)
+	}
+
 	// Create notifications in batch
 	now := time.Now()
 	notificationsList := make([]Notification, 0)
 
 	for _, followerID := range followerIDs {
-		// Don't notify the author
-		if followerID == authorID {
+		// Don't notify the author, or followers who muted them
+		if followerID == authorID || mutedBy[followerID] {
 			continue
 		}
 
@@ -296,7 +464,7 @@ func (s *Service) CreateAnchorUpdateNotifications(ctx context.Context, anchorID
 		return nil
 	}
 
-	return s.repo.CreateNotifications(ctx, notificationsList)
+	return s.deliver(ctx, notificationsList)
 }
 
 // Helper function
@@ -0,0 +1,45 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/testdb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAnchorUpdateSkipsBlockedFollowers(t *testing.T) {
+	db := testdb.New(t)
+	repo := NewRepository(db)
+	service := NewService(repo, auth.NewRepository(db), db)
+	ctx := context.Background()
+
+	author := primitive.NewObjectID()
+	follower := primitive.NewObjectID()
+	blockedByAuthor := primitive.NewObjectID()
+	blockingAuthor := primitive.NewObjectID()
+	_, err := db.Collection("users").InsertMany(ctx, []interface{}{
+		bson.M{"_id": author, "blockedUsers": bson.A{blockedByAuthor}},
+		bson.M{"_id": follower},
+		bson.M{"_id": blockedByAuthor},
+		bson.M{"_id": blockingAuthor, "blockedUsers": bson.A{author}},
+	})
+	require.NoError(t, err)
+
+	anchorID := primitive.NewObjectID()
+	followers := []primitive.ObjectID{follower, blockedByAuthor, blockingAuthor}
+	require.NoError(t, service.CreateAnchorUpdateNotifications(ctx, anchorID, "Reading list", author, followers))
+
+	for _, id := range followers {
+		_, total, err := repo.GetUserNotifications(ctx, id, false, 1, 20)
+		require.NoError(t, err)
+		if id == follower {
+			require.EqualValues(t, 1, total)
+		} else {
+			require.Zero(t, total, "blocks apply both ways")
+		}
+	}
+}
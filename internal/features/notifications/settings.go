@@ -0,0 +1,206 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // Quiet hours timezones must resolve on hosts without a zoneinfo database
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultNotificationSettings delivers every type on every channel, with a weekly
+// email digest and no quiet hours
+func DefaultNotificationSettings(userID primitive.ObjectID) *NotificationSettings {
+	types := make(map[string]ChannelSettings, len(NotificationTypes))
+	for _, t := range NotificationTypes {
+		types[t] = ChannelSettings{InApp: true, Push: true, Email: true}
+	}
+
+	return &NotificationSettings{
+		UserID:      userID,
+		Types:       types,
+		QuietHours:  QuietHours{Timezone: "UTC"},
+		EmailDigest: DigestWeekly,
+	}
+}
+
+// Channels returns where a notification type is delivered; types without a stored
+// preference use the default
+func (s *NotificationSettings) Channels(notificationType string) ChannelSettings {
+	if channels, ok := s.Types[notificationType]; ok {
+		return channels
+	}
+	return ChannelSettings{InApp: true, Push: true, Email: true}
+}
+
+// DigestFrequency returns how often the user gets an email digest
+func (s *NotificationSettings) DigestFrequency() string {
+	if s.EmailDigest == "" {
+		return DigestWeekly // Saved before digests existed
+	}
+	return s.EmailDigest
+}
+
+// InQuietHours reports whether t falls inside the user's quiet hours
+func (s *NotificationSettings) InQuietHours(t time.Time) bool {
+	q := s.QuietHours
+	if !q.Enabled {
+		return false
+	}
+
+	start, err1 := parseClock(q.Start)
+	end, err2 := parseClock(q.End)
+	loc, err3 := time.LoadLocation(q.Timezone)
+	if err1 != nil || err2 != nil || err3 != nil || start == end {
+		return false
+	}
+
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end // Spans midnight
+}
+
+// parseClock converts "HH:MM" to minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// settingsFor returns the user's stored settings or the defaults
+func settingsFor(stored map[primitive.ObjectID]*NotificationSettings, userID primitive.ObjectID) *NotificationSettings {
+	if settings, ok := stored[userID]; ok {
+		return settings
+	}
+	return DefaultNotificationSettings(userID)
+}
+
+// buildNotificationSettings validates a request and merges it over the defaults
+func buildNotificationSettings(userID primitive.ObjectID, req *UpdateNotificationSettingsRequest) (*NotificationSettings, error) {
+	settings := DefaultNotificationSettings(userID)
+
+	for notificationType, channels := range req.Types {
+		if _, known := settings.Types[notificationType]; !known {
+			return nil, fmt.Errorf("unknown notification type %q", notificationType)
+		}
+		settings.Types[notificationType] = channels
+	}
+
+	if req.QuietHours != nil {
+		q := *req.QuietHours
+		if q.Timezone == "" {
+			q.Timezone = "UTC"
+		}
+		if _, err := time.LoadLocation(q.Timezone); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", q.Timezone)
+		}
+		if q.Enabled {
+			if _, err := parseClock(q.Start); err != nil {
+				return nil, fmt.Errorf("quiet hours start must be HH:MM")
+			}
+			if _, err := parseClock(q.End); err != nil {
+				return nil, fmt.Errorf("quiet hours end must be HH:MM")
+			}
+			if q.Start == q.End {
+				return nil, fmt.Errorf("quiet hours start and end must differ")
+			}
+		}
+		settings.QuietHours = q
+	}
+
+	if req.EmailDigest != nil {
+		settings.EmailDigest = *req.EmailDigest
+	}
+
+	return settings, nil
+}
+
+func buildNotificationSettingsResponse(settings *NotificationSettings) NotificationSettingsResponse {
+	resp := NotificationSettingsResponse{
+		Types:       settings.Types,
+		QuietHours:  settings.QuietHours,
+		EmailDigest: settings.DigestFrequency(),
+	}
+	if !settings.UpdatedAt.IsZero() {
+		updatedAt := settings.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+// GetNotificationSettings godoc
+// @Summary Get notification settings
+// @Description Get the current user's per-type channel preferences (in-app, push, email), quiet hours and email digest frequency
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=NotificationSettingsResponse}
+// @Failure 401 {object} response.APIResponse
+// @Router /users/me/notification-settings [get]
+func (h *Handler) GetNotificationSettings(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+
+	settings, err := h.repo.GetNotificationSettings(c.Request.Context(), currentUser.ID)
+	if err != nil {
+		response.InternalServerError(c, "FETCH_FAILED", "Failed to fetch notification settings")
+		return
+	}
+	if settings == nil {
+		settings = DefaultNotificationSettings(currentUser.ID)
+	}
+
+	response.Success(c, buildNotificationSettingsResponse(settings))
+}
+
+// UpdateNotificationSettings godoc
+// @Summary Update notification settings
+// @Description Replace the current user's notification settings. Types left out go back to their defaults. Pushes during quiet hours are suppressed, not delivered later; in-app notifications are unaffected.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateNotificationSettingsRequest true "Settings"
+// @Success 200 {object} response.APIResponse{data=NotificationSettingsResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 401 {object} response.APIResponse
+// @Router /users/me/notification-settings [put]
+func (h *Handler) UpdateNotificationSettings(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+
+	var req UpdateNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	settings, err := buildNotificationSettings(currentUser.ID, &req)
+	if err != nil {
+		response.BadRequest(c, "INVALID_SETTINGS", err.Error())
+		return
+	}
+
+	if err := h.repo.SaveNotificationSettings(c.Request.Context(), settings); err != nil {
+		response.InternalServerError(c, "UPDATE_FAILED", "Failed to save notification settings")
+		return
+	}
+
+	response.Success(c, buildNotificationSettingsResponse(settings))
+}
@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInQuietHours_SpansMidnight(t *testing.T) {
+	settings := DefaultNotificationSettings(primitive.NewObjectID())
+	settings.QuietHours = QuietHours{Enabled: true, Start: "22:00", End: "07:00", Timezone: "America/New_York"}
+
+	// 03:00 UTC is 23:00 the previous evening in New York (EDT)
+	require.True(t, settings.InQuietHours(time.Date(2026, 7, 1, 3, 0, 0, 0, time.UTC)))
+	// 16:00 UTC is noon in New York
+	require.False(t, settings.InQuietHours(time.Date(2026, 7, 1, 16, 0, 0, 0, time.UTC)))
+}
+
+func TestInQuietHours_Disabled(t *testing.T) {
+	settings := DefaultNotificationSettings(primitive.NewObjectID())
+	settings.QuietHours = QuietHours{Start: "00:00", End: "23:59", Timezone: "UTC"}
+
+	require.False(t, settings.InQuietHours(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestBuildNotificationSettings(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	settings, err := buildNotificationSettings(userID, &UpdateNotificationSettingsRequest{
+		Types: map[string]ChannelSettings{TypeLike: {InApp: true}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ChannelSettings{InApp: true}, settings.Channels(TypeLike))
+	require.Equal(t, ChannelSettings{InApp: true, Push: true, Email: true}, settings.Channels(TypeFollow))
+	require.Equal(t, DigestWeekly, settings.DigestFrequency())
+
+	_, err = buildNotificationSettings(userID, &UpdateNotificationSettingsRequest{
+		Types: map[string]ChannelSettings{"poke": {InApp: true}},
+	})
+	require.Error(t, err)
+
+	_, err = buildNotificationSettings(userID, &UpdateNotificationSettingsRequest{
+		QuietHours: &QuietHours{Enabled: true, Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"},
+	})
+	require.Error(t, err)
+}
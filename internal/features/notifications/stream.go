@@ -0,0 +1,270 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// heartbeatInterval keeps idle streams alive through proxies and load balancers
+	heartbeatInterval = 25 * time.Second
+	// replayLimit caps how many missed notifications are replayed on reconnect
+	replayLimit = 100
+	// wsWriteTimeout bounds a single WebSocket write to a stalled client
+	wsWriteTimeout = 10 * time.Second
+)
+
+// streamWriter sends events over one transport (SSE or WebSocket)
+type streamWriter interface {
+	// send writes an event; id is empty for events that cannot be resumed from
+	send(id, eventType string, data interface{}) error
+	heartbeat() error
+}
+
+// StreamNotifications godoc
+// @Summary Stream notifications (SSE)
+// @Description Server-Sent Events stream of new notifications ("notification") and unread count changes ("unread_count").
+// @Description Reconnect with Last-Event-ID to replay missed notifications; "resync" means too many were missed and the list should be reloaded.
+// @Description Browsers that cannot set headers may pass the token as access_token.
+// @Tags notifications
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param lastEventId query string false "Alternative to the Last-Event-ID header"
+// @Param access_token query string false "Bearer token, for clients that cannot set headers"
+// @Success 200 {string} string "event stream"
+// @Failure 401 {object} response.APIResponse
+// @Router /notifications/stream [get]
+func (h *Handler) StreamNotifications(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		response.InternalServerError(c, "STREAMING_UNSUPPORTED", "Streaming is not supported")
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	c.Status(http.StatusOK)
+	flusher.Flush()
+
+	h.runStream(c.Request.Context(), currentUser.ID, lastEventID, &sseWriter{w: c.Writer, flusher: flusher})
+}
+
+// StreamNotificationsWS godoc
+// @Summary Stream notifications (WebSocket)
+// @Description WebSocket carrying the same events as /notifications/stream as JSON messages: {"id", "type", "data"}.
+// @Description Browsers cannot set headers on the upgrade request, so pass the token as access_token.
+// @Tags notifications
+// @Security BearerAuth
+// @Param lastEventId query string false "ID of the last notification received"
+// @Param access_token query string false "Bearer token, for clients that cannot set headers"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} response.APIResponse
+// @Router /notifications/ws [get]
+func (h *Handler) StreamNotificationsWS(c *gin.Context) {
+	usr, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "UNAUTHORIZED", "Authentication required")
+		return
+	}
+	currentUser := usr.(*auth.User)
+	lastEventID := c.Query("lastEventId")
+
+	server := websocket.Server{
+		Handshake: h.checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			ctx, cancel := context.WithCancel(c.Request.Context())
+			defer cancel()
+
+			// The stream is one-way; reading only detects the client going away
+			go func() {
+				defer cancel()
+				buf := make([]byte, 512)
+				for {
+					if _, err := ws.Read(buf); err != nil {
+						return
+					}
+				}
+			}()
+
+			h.runStream(ctx, currentUser.ID, lastEventID, &wsWriter{conn: ws})
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin accepts non-browser clients and the configured frontend
+func (h *Handler) checkOrigin(cfg *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" || h.config.FrontendURL == "*" || origin == h.config.FrontendURL {
+		return nil
+	}
+	return fmt.Errorf("origin %q not allowed", origin)
+}
+
+// runStream replays what the client missed, then forwards hub events until the
+// client disconnects or the hub closes
+func (h *Handler) runStream(ctx context.Context, userID primitive.ObjectID, lastEventID string, w streamWriter) {
+	// Subscribe before replaying so nothing created in between is lost
+	sub := DefaultHub().Subscribe(userID)
+	defer sub.Unsubscribe()
+
+	// Events already covered by the replay; a group updated later arrives again with a newer time
+	replayed := make(map[primitive.ObjectID]time.Time)
+	if after, err := DecodeEventID(lastEventID); err == nil && after != nil {
+		missed, err := h.repo.GetNotificationsAfter(ctx, userID, after, replayLimit+1)
+		if err != nil {
+			return
+		}
+
+		if len(missed) > replayLimit {
+			if err := w.send("", EventResync, nil); err != nil {
+				return
+			}
+		} else {
+			for _, n := range missed {
+				replayed[n.ID] = n.CreatedAt
+			}
+			if err := h.sendNotifications(ctx, w, missed); err != nil {
+				return
+			}
+		}
+	}
+
+	if err := h.sendUnreadCount(ctx, w, userID); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.heartbeat(); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+
+			if event.Type == EventNotification && event.Notification != nil {
+				at, ok := replayed[event.Notification.ID]
+				if ok && !event.Notification.CreatedAt.Truncate(time.Millisecond).After(at) {
+					continue
+				}
+				if err := h.sendNotifications(ctx, w, []Notification{*event.Notification}); err != nil {
+					return
+				}
+			}
+
+			if err := h.sendUnreadCount(ctx, w, userID); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *Handler) sendNotifications(ctx context.Context, w streamWriter, notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	for i, n := range h.enrichNotifications(ctx, notifications) {
+		if err := w.send(EncodeEventID(&notifications[i]), EventNotification, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Handler) sendUnreadCount(ctx context.Context, w streamWriter, userID primitive.ObjectID) error {
+	count, err := h.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return w.send("", EventUnreadCount, UnreadCountResponse{UnreadCount: count})
+}
+
+// sseWriter writes Server-Sent Events
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s *sseWriter) send(id, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		if _, err := fmt.Fprintf(s.w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", eventType, payload); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseWriter) heartbeat() error {
+	if _, err := fmt.Fprint(s.w, ": heartbeat\n\n"); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// wsMessage is the JSON frame sent over the WebSocket
+type wsMessage struct {
+	ID   string      `json:"id,omitempty"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// wsWriter writes JSON frames to a WebSocket
+type wsWriter struct {
+	conn *websocket.Conn
+}
+
+func (s *wsWriter) send(id, eventType string, data interface{}) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(s.conn, wsMessage{ID: id, Type: eventType, Data: data})
+}
+
+func (s *wsWriter) heartbeat() error {
+	return s.send("", "heartbeat", nil)
+}
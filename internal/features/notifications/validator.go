@@ -1,8 +1,8 @@
 package notifications
 
 func ValidateNotificationListQuery(query *NotificationListQuery) error {
-	if query.Page < 1 {
-		query.Page = 1
+	if query.Page < 0 {
+		query.Page = 0
 	}
 
 	if query.Limit < 1 {
@@ -12,5 +12,9 @@ func ValidateNotificationListQuery(query *NotificationListQuery) error {
 		query.Limit = 50
 	}
 
+	if _, err := DecodeCursor(query.Cursor); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -1,6 +1,8 @@
 package safety
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
@@ -8,17 +10,24 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// FollowRemover defines the interface for removing follows when users block each other
+type FollowRemover interface {
+	DeleteFollowsBetween(ctx context.Context, userA, userB primitive.ObjectID) (aFollowedB bool, bFollowedA bool, err error)
+}
+
 type Handler struct {
-	repo     *Repository
-	authRepo *auth.Repository
-	cfg      *config.Config
+	repo          *Repository
+	authRepo      *auth.Repository
+	followRemover FollowRemover // Interface to avoid cycle
+	cfg           *config.Config
 }
 
-func NewHandler(repo *Repository, authRepo *auth.Repository, cfg *config.Config) *Handler {
+func NewHandler(repo *Repository, authRepo *auth.Repository, followRemover FollowRemover, cfg *config.Config) *Handler {
 	return &Handler{
-		repo:     repo,
-		authRepo: authRepo,
-		cfg:      cfg,
+		repo:          repo,
+		authRepo:      authRepo,
+		followRemover: followRemover,
+		cfg:           cfg,
 	}
 }
 
@@ -70,6 +79,7 @@ func (h *Handler) CreateReport(c *gin.Context) {
 }
 
 // @Summary Block a user
+// @Description Block a user. Blocks apply both ways and remove existing follows in both directions.
 // @Tags safety
 // @Produce json
 // @Security BearerAuth
@@ -95,11 +105,33 @@ func (h *Handler) BlockUser(c *gin.Context) {
 		return
 	}
 
+	if targetID == user.ID {
+		response.BadRequest(c, "Cannot block yourself", "INVALID_ACTION")
+		return
+	}
+
 	if err := h.repo.BlockUser(c.Request.Context(), user.ID, targetID); err != nil {
 		response.InternalServerError(c, "Failed to block user", "DATABASE_ERROR")
 		return
 	}
 
+	// Blocked users can no longer follow each other
+	if h.followRemover != nil {
+		userFollowedTarget, targetFollowedUser, err := h.followRemover.DeleteFollowsBetween(c.Request.Context(), user.ID, targetID)
+		if err != nil {
+			response.InternalServerError(c, "Failed to remove follows", "DATABASE_ERROR")
+			return
+		}
+		if userFollowedTarget {
+			_ = h.authRepo.IncrementFollowingCount(c.Request.Context(), user.ID, -1)
+			_ = h.authRepo.IncrementFollowerCount(c.Request.Context(), targetID, -1)
+		}
+		if targetFollowedUser {
+			_ = h.authRepo.IncrementFollowingCount(c.Request.Context(), targetID, -1)
+			_ = h.authRepo.IncrementFollowerCount(c.Request.Context(), user.ID, -1)
+		}
+	}
+
 	response.Success(c, "User blocked successfully")
 }
 
@@ -0,0 +1,107 @@
+package safety
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BlockPolicy decides whether two users may see and interact with each other.
+// Blocks apply in both directions: once either user blocks the other, neither
+// sees the other's profile or content, and neither can comment, like, follow
+// or mention the other.
+//
+// Lookup errors are treated as "not blocked", matching the notification checks.
+type BlockPolicy struct {
+	usersCollection *mongo.Collection
+}
+
+// NewBlockPolicy creates the block policy
+func NewBlockPolicy(db *mongo.Database) *BlockPolicy {
+	return &BlockPolicy{
+		usersCollection: db.Collection("users"),
+	}
+}
+
+// IsBlocked reports whether either user has blocked the other
+func (p *BlockPolicy) IsBlocked(ctx context.Context, userA, userB primitive.ObjectID) bool {
+	if userA == userB {
+		return false
+	}
+
+	count, err := p.usersCollection.CountDocuments(ctx, bson.M{
+		"$or": []bson.M{
+			{"_id": userA, "blockedUsers": userB},
+			{"_id": userB, "blockedUsers": userA},
+		},
+	})
+	return err == nil && count > 0
+}
+
+// BlockedUserIDs returns every user the given user has blocked or been blocked by
+func (p *BlockPolicy) BlockedUserIDs(ctx context.Context, userID primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool)
+	var ids []primitive.ObjectID
+	add := func(id primitive.ObjectID) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	// Users this user blocked
+	var self struct {
+		BlockedUsers []primitive.ObjectID `bson:"blockedUsers"`
+	}
+	err := p.usersCollection.FindOne(ctx, bson.M{"_id": userID},
+		options.FindOne().SetProjection(bson.M{"blockedUsers": 1}),
+	).Decode(&self)
+	if err == nil {
+		for _, id := range self.BlockedUsers {
+			add(id)
+		}
+	}
+
+	// Users who blocked this user
+	cursor, err := p.usersCollection.Find(ctx, bson.M{"blockedUsers": userID},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return ids
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err == nil {
+			add(doc.ID)
+		}
+	}
+
+	return ids
+}
+
+// FilterBlocked drops the candidates that the given user has blocked or been blocked by
+func (p *BlockPolicy) FilterBlocked(ctx context.Context, userID primitive.ObjectID, candidates []primitive.ObjectID) []primitive.ObjectID {
+	if len(candidates) == 0 {
+		return candidates
+	}
+
+	blocked := make(map[primitive.ObjectID]bool)
+	for _, id := range p.BlockedUserIDs(ctx, userID) {
+		blocked[id] = true
+	}
+
+	allowed := make([]primitive.ObjectID, 0, len(candidates))
+	for _, id := range candidates {
+		if !blocked[id] {
+			allowed = append(allowed, id)
+		}
+	}
+	return allowed
+}
@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Repository struct {
@@ -16,8 +17,21 @@ type Repository struct {
 }
 
 func NewRepository(db *mongo.Database) *Repository {
+	reportsCollection := db.Collection("reports")
+
+	_, _ = reportsCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// Moderation queue: reports by status, newest first
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			// Reports filed by a user
+			Keys: bson.D{{Key: "reporterId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+	})
+
 	return &Repository{
-		reportsCollection: db.Collection("reports"),
+		reportsCollection: reportsCollection,
 		usersCollection:   db.Collection("users"),
 	}
 }
@@ -62,3 +76,50 @@ func (r *Repository) RemoveBlock(ctx context.Context, blockerID, blockedID primi
 	_, err := r.usersCollection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+// ListReports returns reports newest first, optionally filtered by status
+func (r *Repository) ListReports(ctx context.Context, status string, page, limit int) ([]Report, int64, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	total, err := r.reportsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.reportsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []Report{}
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// GetReportsByReporter returns every report filed by a user, newest first (for data export)
+func (r *Repository) GetReportsByReporter(ctx context.Context, reporterID primitive.ObjectID) ([]Report, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.reportsCollection.Find(ctx, bson.M{"reporterId": reporterID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []Report{}
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
@@ -8,10 +8,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config, followRemover FollowRemover) {
 	repo := NewRepository(db)
 	authRepo := auth.NewRepository(db)
-	handler := NewHandler(repo, authRepo, cfg)
+	handler := NewHandler(repo, authRepo, followRemover, cfg)
 	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
 
 	// Reports
@@ -8,6 +8,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -16,14 +18,18 @@ type Handler struct {
 	repo        *Repository
 	authRepo    *auth.Repository
 	followsRepo *follows.Repository
+	mutesRepo   *mutes.Repository
+	blockPolicy *safety.BlockPolicy
 	config      *config.Config
 }
 
-func NewHandler(repo *Repository, authRepo *auth.Repository, followsRepo *follows.Repository, cfg *config.Config) *Handler {
+func NewHandler(repo *Repository, authRepo *auth.Repository, followsRepo *follows.Repository, mutesRepo *mutes.Repository, blockPolicy *safety.BlockPolicy, cfg *config.Config) *Handler {
 	return &Handler{
 		repo:        repo,
 		authRepo:    authRepo,
 		followsRepo: followsRepo,
+		mutesRepo:   mutesRepo,
+		blockPolicy: blockPolicy,
 		config:      cfg,
 	}
 }
@@ -65,7 +71,7 @@ func (h *Handler) UnifiedSearch(c *gin.Context) {
 
 	// Search anchors
 	if query.Type == TypeAll || query.Type == TypeAnchors {
-		anchors, total, err := h.repo.SearchAnchors(c.Request.Context(), query.Q, nil, SortRelevant, 1, query.Limit)
+		anchors, total, err := h.repo.SearchAnchors(c.Request.Context(), query.Q, nil, SortRelevant, 1, query.Limit, h.viewerFilter(c.Request.Context(), currentUserID))
 		if err == nil {
 			anchorResults := h.enrichAnchorResults(c.Request.Context(), anchors)
 			resp.Anchors = &UnifiedSearchAnchorsResult{
@@ -84,7 +90,7 @@ func (h *Handler) UnifiedSearch(c *gin.Context) {
 
 	// Search users
 	if query.Type == TypeAll || query.Type == TypeUsers {
-		users, total, err := h.repo.SearchUsers(c.Request.Context(), query.Q, 1, query.Limit)
+		users, total, err := h.repo.SearchUsers(c.Request.Context(), query.Q, 1, query.Limit, h.blockedUserIDs(c.Request.Context(), currentUserID))
 		if err == nil {
 			userResults := h.enrichUserResults(c.Request.Context(), users, currentUserID)
 			resp.Users = &UnifiedSearchUsersResult{
@@ -135,8 +141,17 @@ func (h *Handler) SearchAnchors(c *gin.Context) {
 		tagFilter = &query.Tag
 	}
 
+	// Get current user if authenticated
+	var currentUserID *primitive.ObjectID
+	if usr, exists := c.Get("user"); exists {
+		if user, ok := usr.(*auth.User); ok {
+			currentUserID = &user.ID
+		}
+	}
+
 	// Search
-	anchors, total, err := h.repo.SearchAnchors(c.Request.Context(), query.Q, tagFilter, query.Sort, query.Page, query.Limit)
+	viewer := h.viewerFilter(c.Request.Context(), currentUserID)
+	anchors, total, err := h.repo.SearchAnchors(c.Request.Context(), query.Q, tagFilter, query.Sort, query.Page, query.Limit, viewer)
 	if err != nil {
 		response.InternalServerError(c, "SEARCH_FAILED", "Failed to search anchors")
 		return
@@ -196,7 +211,7 @@ func (h *Handler) SearchUsers(c *gin.Context) {
 	}
 
 	// Search
-	users, total, err := h.repo.SearchUsers(c.Request.Context(), query.Q, query.Page, query.Limit)
+	users, total, err := h.repo.SearchUsers(c.Request.Context(), query.Q, query.Page, query.Limit, h.blockedUserIDs(c.Request.Context(), currentUserID))
 	if err != nil {
 		response.InternalServerError(c, "SEARCH_FAILED", "Failed to search users")
 		return
@@ -259,6 +274,33 @@ func (h *Handler) SearchTags(c *gin.Context) {
 
 // Helper methods
 
+// viewerFilter gathers the viewer's follows, mutes and blocks for anchor search, nil for anonymous viewers
+func (h *Handler) viewerFilter(ctx context.Context, currentUserID *primitive.ObjectID) *ViewerFilter {
+	if currentUserID == nil {
+		return nil
+	}
+
+	filter := &ViewerFilter{}
+
+	// Private accounts are visible to themselves and their approved followers
+	followingIDs, _ := h.followsRepo.GetAllFollowingIDs(ctx, *currentUserID)
+	filter.ApprovedUserIDs = append(followingIDs, *currentUserID)
+
+	filter.MutedUserIDs, _ = h.mutesRepo.GetMutedUserIDs(ctx, *currentUserID)
+	filter.MutedTags, _ = h.mutesRepo.GetMutedTags(ctx, *currentUserID)
+	filter.BlockedUserIDs = h.blockedUserIDs(ctx, currentUserID)
+
+	return filter
+}
+
+// blockedUserIDs returns the users hidden from the viewer by a block in either direction
+func (h *Handler) blockedUserIDs(ctx context.Context, currentUserID *primitive.ObjectID) []primitive.ObjectID {
+	if currentUserID == nil {
+		return nil
+	}
+	return h.blockPolicy.BlockedUserIDs(ctx, *currentUserID)
+}
+
 func (h *Handler) enrichAnchorResults(ctx context.Context, anchors []AnchorSearchDoc) []SearchAnchorResult {
 	if len(anchors) == 0 {
 		return []SearchAnchorResult{}
@@ -401,7 +443,7 @@ func (h *Handler) CombinedSearch(c *gin.Context) {
 	}
 
 	// 1. Anchors
-	anchors, totalAnchors, err := h.repo.SearchAnchors(c.Request.Context(), q, nil, SortRelevant, 1, limit)
+	anchors, totalAnchors, err := h.repo.SearchAnchors(c.Request.Context(), q, nil, SortRelevant, 1, limit, h.viewerFilter(c.Request.Context(), currentUserID))
 	if err == nil {
 		resp.Anchors = &UnifiedSearchAnchorsResult{
 			Items:   h.enrichAnchorResults(c.Request.Context(), anchors),
@@ -413,7 +455,7 @@ func (h *Handler) CombinedSearch(c *gin.Context) {
 	}
 
 	// 2. Users
-	users, totalUsers, err := h.repo.SearchUsers(c.Request.Context(), q, 1, limit)
+	users, totalUsers, err := h.repo.SearchUsers(c.Request.Context(), q, 1, limit, h.blockedUserIDs(c.Request.Context(), currentUserID))
 	if err == nil {
 		resp.Users = &UnifiedSearchUsersResult{
 			Items:   h.enrichUserResults(c.Request.Context(), users, currentUserID),
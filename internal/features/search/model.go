@@ -13,6 +13,14 @@ const (
 	SortPopular  = "popular"
 )
 
+// ViewerFilter holds the per-viewer exclusions applied to anchor search
+type ViewerFilter struct {
+	ApprovedUserIDs []primitive.ObjectID // Private accounts whose anchors the viewer may see (themselves and accounts they follow)
+	MutedUserIDs    []primitive.ObjectID
+	BlockedUserIDs  []primitive.ObjectID // Users the viewer blocked or was blocked by
+	MutedTags       []string
+}
+
 // Search type constants
 const (
 	TypeAll     = "all"
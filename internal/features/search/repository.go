@@ -22,13 +22,39 @@ func NewRepository(db *mongo.Database) *Repository {
 	}
 }
 
-// SearchAnchors performs text search on anchors
-func (r *Repository) SearchAnchors(ctx context.Context, query string, tag *string, sort string, page, limit int) ([]AnchorSearchDoc, int64, error) {
-	// Build filter
+// SearchAnchors performs text search on anchors. viewer is nil for anonymous searches.
+func (r *Repository) SearchAnchors(ctx context.Context, query string, tag *string, sort string, page, limit int, viewer *ViewerFilter) ([]AnchorSearchDoc, int64, error) {
+	if viewer == nil {
+		viewer = &ViewerFilter{}
+	}
+
+	approvedIDs := viewer.ApprovedUserIDs
+	if approvedIDs == nil {
+		approvedIDs = []primitive.ObjectID{}
+	}
+
+	// Build filter, hiding anchors owned by deactivated accounts and by private accounts the
+	// viewer does not follow (author state is copied onto anchors by auth)
 	filter := bson.M{
-		"$text":      bson.M{"$search": query},
-		"visibility": "public",
-		"deletedAt":  nil,
+		"$text":             bson.M{"$search": query},
+		"visibility":        "public",
+		"deletedAt":         nil,
+		"authorDeactivated": bson.M{"$ne": true},
+		"$or": []bson.M{
+			{"authorPrivate": bson.M{"$ne": true}},
+			{"userId": bson.M{"$in": approvedIDs}},
+		},
+	}
+
+	// Hide anchors by muted and blocked users
+	hiddenUserIDs := append(append([]primitive.ObjectID{}, viewer.MutedUserIDs...), viewer.BlockedUserIDs...)
+	if len(hiddenUserIDs) > 0 {
+		filter["userId"] = bson.M{"$nin": hiddenUserIDs}
+	}
+
+	// Hide anchors carrying a muted tag
+	if len(viewer.MutedTags) > 0 {
+		filter["$nor"] = []bson.M{{"tags": bson.M{"$in": viewer.MutedTags}}}
 	}
 
 	// Add tag filter if provided
@@ -78,10 +104,14 @@ func (r *Repository) SearchAnchors(ctx context.Context, query string, tag *strin
 	return anchors, total, nil
 }
 
-// SearchUsers performs text search on users
-func (r *Repository) SearchUsers(ctx context.Context, query string, page, limit int) ([]UserSearchDoc, int64, error) {
+// SearchUsers performs text search on users, leaving out excludeUserIDs
+func (r *Repository) SearchUsers(ctx context.Context, query string, page, limit int, excludeUserIDs []primitive.ObjectID) ([]UserSearchDoc, int64, error) {
 	filter := bson.M{
-		"$text": bson.M{"$search": query},
+		"$text":         bson.M{"$search": query},
+		"deactivatedAt": nil,
+	}
+	if len(excludeUserIDs) > 0 {
+		filter["_id"] = bson.M{"$nin": excludeUserIDs}
 	}
 
 	opts := options.Find().
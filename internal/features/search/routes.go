@@ -5,6 +5,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/config"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -14,9 +16,10 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	repo := NewRepository(db)
 	authRepo := auth.NewRepository(db)
 	followsRepo := follows.NewRepository(db)
+	mutesRepo := mutes.NewRepository(db)
 
 	// Initialize handler
-	handler := NewHandler(repo, authRepo, followsRepo, cfg)
+	handler := NewHandler(repo, authRepo, followsRepo, mutesRepo, safety.NewBlockPolicy(db), cfg)
 
 	// Initialize middleware
 	optionalAuth := middleware.OptionalAuthMiddleware(authRepo, cfg)
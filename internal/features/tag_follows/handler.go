@@ -0,0 +1,131 @@
+package tag_follows
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+)
+
+type Handler struct {
+	repo *Repository
+}
+
+func NewHandler(repo *Repository) *Handler {
+	return &Handler{repo: repo}
+}
+
+// FollowTag godoc
+// @Summary Follow a tag
+// @Description Follow a tag to get new public anchors carrying it in your home feed
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Param tagName path string true "Tag to follow"
+// @Success 200 {object} response.APIResponse{data=TagFollowResponse}
+// @Failure 400 {object} response.APIResponse
+// @Router /tags/{tagName}/follow [post]
+func (h *Handler) FollowTag(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	tag := NormalizeTag(c.Param("tagName"))
+	if len(tag) < 2 || len(tag) > 50 {
+		response.BadRequest(c, "Invalid tag", "INVALID_TAG")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	following, err := h.repo.IsFollowing(ctx, user.ID, tag)
+	if err != nil {
+		response.InternalServerError(c, "Failed to follow tag", "DATABASE_ERROR")
+		return
+	}
+	if !following {
+		count, err := h.repo.CountFollowedTags(ctx, user.ID)
+		if err != nil {
+			response.InternalServerError(c, "Failed to follow tag", "DATABASE_ERROR")
+			return
+		}
+		if count >= MaxFollowedTags {
+			response.BadRequest(c, "You can follow up to 100 tags", "TAG_FOLLOW_LIMIT")
+			return
+		}
+	}
+
+	if _, err := h.repo.FollowTag(ctx, user.ID, tag); err != nil {
+		response.InternalServerError(c, "Failed to follow tag", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, TagFollowResponse{Tag: tag, IsFollowing: true}, "Tag followed")
+}
+
+// UnfollowTag godoc
+// @Summary Unfollow a tag
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Param tagName path string true "Tag to unfollow"
+// @Success 200 {object} response.APIResponse{data=TagFollowResponse}
+// @Router /tags/{tagName}/follow [delete]
+func (h *Handler) UnfollowTag(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	tag := NormalizeTag(c.Param("tagName"))
+	if err := h.repo.UnfollowTag(c.Request.Context(), user.ID, tag); err != nil {
+		response.InternalServerError(c, "Failed to unfollow tag", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, TagFollowResponse{Tag: tag, IsFollowing: false}, "Tag unfollowed")
+}
+
+// GetFollowedTags godoc
+// @Summary List followed tags
+// @Description Get the tags the current user follows, newest first
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=FollowedTagsResponse}
+// @Failure 401 {object} response.APIResponse
+// @Router /users/me/followed-tags [get]
+func (h *Handler) GetFollowedTags(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	follows, err := h.repo.GetFollowedTags(c.Request.Context(), user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch followed tags", "DATABASE_ERROR")
+		return
+	}
+
+	tags := make([]FollowedTagResponse, len(follows))
+	for i, f := range follows {
+		tags[i] = FollowedTagResponse{Tag: f.Tag, FollowedAt: f.CreatedAt}
+	}
+
+	response.Success(c, FollowedTagsResponse{Tags: tags, Total: len(tags)})
+}
+
+// currentUser returns the authenticated user, writing an error response if there is none
+func currentUser(c *gin.Context) (*auth.User, bool) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return nil, false
+	}
+	user, ok := val.(*auth.User)
+	if !ok {
+		response.InternalServerError(c, "User context error", "INTERNAL_ERROR")
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,36 @@
+package tag_follows
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxFollowedTags caps how many tags a user can follow
+const MaxFollowedTags = 100
+
+// TagFollow represents a user following a tag
+type TagFollow struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Tag       string             `bson:"tag" json:"tag"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// TagFollowResponse for follow/unfollow actions
+type TagFollowResponse struct {
+	Tag         string `json:"tag"`
+	IsFollowing bool   `json:"isFollowing"`
+}
+
+// FollowedTagResponse for items in the followed tags list
+type FollowedTagResponse struct {
+	Tag        string    `json:"tag"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+// FollowedTagsResponse for GET /users/me/followed-tags
+type FollowedTagsResponse struct {
+	Tags  []FollowedTagResponse `json:"tags"`
+	Total int                   `json:"total"`
+}
@@ -0,0 +1,111 @@
+package tag_follows
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository handles database interactions for tag follows
+type Repository struct {
+	collection *mongo.Collection
+}
+
+// NewRepository creates repository and ensures indexes
+func NewRepository(db *mongo.Database) *Repository {
+	collection := db.Collection("tag_follows")
+
+	_, _ = collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// One follow per user/tag pair; also lists a user's tags
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "tag", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
+	return &Repository{
+		collection: collection,
+	}
+}
+
+// NormalizeTag returns the stored form of a tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(tag, "#")))
+}
+
+// FollowTag follows a tag (idempotent). The boolean reports whether a new follow was created.
+func (r *Repository) FollowTag(ctx context.Context, userID primitive.ObjectID, tag string) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"userId": userID, "tag": NormalizeTag(tag)},
+		bson.M{"$setOnInsert": bson.M{"createdAt": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// UnfollowTag removes a tag follow (idempotent)
+func (r *Repository) UnfollowTag(ctx context.Context, userID primitive.ObjectID, tag string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"userId": userID, "tag": NormalizeTag(tag)})
+	return err
+}
+
+// IsFollowing checks if the user follows a tag
+func (r *Repository) IsFollowing(ctx context.Context, userID primitive.ObjectID, tag string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"userId": userID, "tag": NormalizeTag(tag)})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountFollowedTags returns how many tags the user follows
+func (r *Repository) CountFollowedTags(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"userId": userID})
+}
+
+// GetFollowedTags returns the user's tag follows, newest first
+func (r *Repository) GetFollowedTags(ctx context.Context, userID primitive.ObjectID) ([]TagFollow, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	follows := []TagFollow{}
+	if err = cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+// GetFollowedTagNames returns the tags the user follows
+func (r *Repository) GetFollowedTagNames(ctx context.Context, userID primitive.ObjectID) ([]string, error) {
+	follows, err := r.GetFollowedTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, len(follows))
+	for i, f := range follows {
+		tags[i] = f.Tag
+	}
+	return tags, nil
+}
+
+// DeleteAllForUser removes every tag the user follows
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
@@ -0,0 +1,30 @@
+package tag_follows
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RegisterRoutes registers the tag follow routes
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+	repo := NewRepository(db)
+	authRepo := auth.NewRepository(db)
+	handler := NewHandler(repo)
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+
+	tags := router.Group("/tags/:tagName")
+	tags.Use(authMiddleware)
+	{
+		tags.POST("/follow", handler.FollowTag)
+		tags.DELETE("/follow", handler.UnfollowTag)
+	}
+
+	users := router.Group("/users/me")
+	users.Use(authMiddleware)
+	{
+		users.GET("/followed-tags", handler.GetFollowedTags)
+	}
+}
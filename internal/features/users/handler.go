@@ -10,6 +10,7 @@ import (
 	"github.com/xyz-asif/gotodo/internal/features/anchors"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -24,17 +25,58 @@ type Handler struct {
 	likesRepo     *likes.Repository
 	anchorsRepo   *anchors.Repository
 	followService FollowService
+	blockPolicy   *safety.BlockPolicy
+	suggestions   *SuggestionService
 }
 
-func NewHandler(authRepo *auth.Repository, likesRepo *likes.Repository, anchorsRepo *anchors.Repository, followService FollowService) *Handler {
+func NewHandler(authRepo *auth.Repository, likesRepo *likes.Repository, anchorsRepo *anchors.Repository, followService FollowService, blockPolicy *safety.BlockPolicy, suggestions *SuggestionService) *Handler {
 	return &Handler{
 		authRepo:      authRepo,
 		likesRepo:     likesRepo,
 		anchorsRepo:   anchorsRepo,
 		followService: followService,
+		blockPolicy:   blockPolicy,
+		suggestions:   suggestions,
 	}
 }
 
+// GetSuggestions godoc
+// @Summary Who to follow
+// @Description Get users to follow, ranked by mutual follows, shared interests and anchor engagement. Each suggestion explains why it was made.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Max suggestions (default 20, max 50)"
+// @Success 200 {object} response.APIResponse{data=SuggestionsResponse}
+// @Failure 401 {object} response.APIResponse
+// @Router /users/suggestions [get]
+func (h *Handler) GetSuggestions(c *gin.Context) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "UNAUTHORIZED")
+		return
+	}
+	user, ok := val.(*auth.User)
+	if !ok {
+		response.BadRequest(c, "User context error", "INTERNAL_ERROR")
+		return
+	}
+
+	var query SuggestionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "Invalid query parameters", "INVALID_QUERY")
+		return
+	}
+
+	suggestions, err := h.suggestions.GetSuggestions(c.Request.Context(), user, query.Limit)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch suggestions", "FETCH_FAILED")
+		return
+	}
+
+	response.Success(c, SuggestionsResponse{Suggestions: suggestions})
+}
+
 // GetUserByUsername godoc
 // @Summary Get user profile by username
 // @Description Get public profile of a user by their username
@@ -63,16 +105,29 @@ func (h *Handler) GetUserByUsername(c *gin.Context) {
 		return
 	}
 
+	var currentUser *auth.User
+	if val, exists := c.Get("user"); exists {
+		currentUser, _ = val.(*auth.User)
+	}
+
+	// Deactivated profiles are only visible to their owner
+	if user.IsDeactivated() && (currentUser == nil || currentUser.ID != user.ID) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
+	// Profiles are hidden between users who blocked each other
+	if h.isBlockedWith(c, user.ID) {
+		response.NotFound(c, "User not found", "USER_NOT_FOUND")
+		return
+	}
+
 	// Check follow status if authenticated
 	var isFollowing, isFollowedBy, isMutual bool
-	if val, exists := c.Get("user"); exists {
-		if currentUser, ok := val.(*auth.User); ok {
-			if currentUser.ID != user.ID && h.followService != nil {
-				isFollowing, isFollowedBy, err = h.followService.GetFollowStatus(ctx, currentUser.ID, user.ID)
-				if err == nil {
-					isMutual = isFollowing && isFollowedBy
-				}
-			}
+	if currentUser != nil && currentUser.ID != user.ID && h.followService != nil {
+		isFollowing, isFollowedBy, err = h.followService.GetFollowStatus(ctx, currentUser.ID, user.ID)
+		if err == nil {
+			isMutual = isFollowing && isFollowedBy
 		}
 	}
 
@@ -87,6 +142,7 @@ func (h *Handler) GetUserByUsername(c *gin.Context) {
 		FollowingCount:    user.FollowingCount,
 		AnchorCount:       user.AnchorCount,
 		IsVerified:        user.IsVerified,
+		IsPrivate:         user.IsPrivate,
 		JoinedAt:          user.JoinedAt,
 		IsFollowing:       isFollowing,
 		IsFollowedBy:      isFollowedBy,
@@ -139,9 +195,9 @@ func (h *Handler) GetUserLikes(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	// Check user exists
+	// Check user exists and is not blocked either way
 	user, err := h.authRepo.GetUserByID(ctx, userIDStr)
-	if err != nil || user == nil {
+	if err != nil || user == nil || h.isBlockedWith(c, user.ID) {
 		response.NotFound(c, "User not found", "USER_NOT_FOUND")
 		return
 	}
@@ -262,9 +318,9 @@ func (h *Handler) GetUserClones(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	// Check user exists
+	// Check user exists and is not blocked either way
 	user, err := h.authRepo.GetUserByID(ctx, userIDStr)
-	if err != nil || user == nil {
+	if err != nil || user == nil || h.isBlockedWith(c, user.ID) {
 		response.NotFound(c, "User not found", "USER_NOT_FOUND")
 		return
 	}
@@ -343,3 +399,16 @@ func (h *Handler) GetUserClones(c *gin.Context) {
 		},
 	})
 }
+
+// isBlockedWith reports whether the authenticated viewer and the user have blocked each other
+func (h *Handler) isBlockedWith(c *gin.Context, userID primitive.ObjectID) bool {
+	val, exists := c.Get("user")
+	if !exists {
+		return false
+	}
+	viewer, ok := val.(*auth.User)
+	if !ok {
+		return false
+	}
+	return h.blockPolicy.IsBlocked(c.Request.Context(), viewer.ID, userID)
+}
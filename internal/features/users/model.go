@@ -0,0 +1,43 @@
+package users
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Suggestion reason constants
+const (
+	ReasonMutualFollows = "mutual_follows"
+	ReasonInterests     = "interests"
+	ReasonPopular       = "popular"
+)
+
+// SuggestionsQuery for GET /users/suggestions
+type SuggestionsQuery struct {
+	Limit int `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// SuggestedUser is the profile summary shown on a suggestion card
+type SuggestedUser struct {
+	ID                primitive.ObjectID `json:"id"`
+	Username          string             `json:"username"`
+	DisplayName       string             `json:"displayName"`
+	Bio               string             `json:"bio"`
+	ProfilePictureURL string             `json:"profilePictureUrl"`
+	IsVerified        bool               `json:"isVerified"`
+	IsPrivate         bool               `json:"isPrivate"`
+	FollowerCount     int                `json:"followerCount"`
+}
+
+// UserSuggestion is a ranked "who to follow" candidate and why it was suggested
+type UserSuggestion struct {
+	User              SuggestedUser `json:"user"`
+	Reason            string        `json:"reason"`      // "mutual_follows", "interests", "popular"
+	Explanation       string        `json:"explanation"` // e.g. "Followed by alice and 3 others"
+	MutualFollowCount int           `json:"mutualFollowCount"`
+	FollowedBy        []string      `json:"followedBy"` // Usernames of a few accounts the viewer follows that follow this user
+	SharedTags        []string      `json:"sharedTags"`
+	Score             float64       `json:"score"`
+}
+
+// SuggestionsResponse for GET /users/suggestions
+type SuggestionsResponse struct {
+	Suggestions []UserSuggestion `json:"suggestions"`
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
 	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
 	"github.com/xyz-asif/gotodo/internal/middleware"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -16,13 +18,18 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 	likesRepo := likes.NewRepository(db)
 	anchorsRepo := anchors.NewRepository(db)
 	followsRepo := follows.NewRepository(db)
-	handler := NewHandler(authRepo, likesRepo, anchorsRepo, followsRepo)
+	blockPolicy := safety.NewBlockPolicy(db)
+	suggestions := NewSuggestionService(authRepo, followsRepo, anchorsRepo, mutes.NewRepository(db), blockPolicy)
+	handler := NewHandler(authRepo, likesRepo, anchorsRepo, followsRepo, blockPolicy, suggestions)
 	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
 	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(authRepo, cfg)
 
 	// User routes
 	users := router.Group("/users")
 	{
+		// Who to follow
+		users.GET("/suggestions", authMiddleware, handler.GetSuggestions)
+
 		// Profile by username - optional auth for follow status
 		users.GET("/username/:username", optionalAuthMiddleware, handler.GetUserByUsername)
 
@@ -31,10 +38,10 @@ func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Con
 
 		// Likes
 		users.GET("/me/likes", authMiddleware, handler.GetUserLikes)
-		users.GET("/:id/likes", handler.GetUserLikes)
+		users.GET("/:id/likes", optionalAuthMiddleware, handler.GetUserLikes)
 
 		// Clones
 		users.GET("/me/clones", authMiddleware, handler.GetUserClones)
-		users.GET("/:id/clones", handler.GetUserClones)
+		users.GET("/:id/clones", optionalAuthMiddleware, handler.GetUserClones)
 	}
 }
@@ -0,0 +1,286 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/xyz-asif/gotodo/internal/features/anchors"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/features/follows"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
+	"github.com/xyz-asif/gotodo/internal/features/safety"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Suggestion ranking weights
+const (
+	mutualFollowWeight = 3.0
+	sharedTagWeight    = 2.0
+	candidatePoolSize  = 3 // Candidates fetched per source, as a multiple of the requested limit
+)
+
+// SuggestionService ranks "who to follow" candidates for a user
+type SuggestionService struct {
+	authRepo    *auth.Repository
+	followsRepo *follows.Repository
+	anchorsRepo *anchors.Repository
+	mutesRepo   *mutes.Repository
+	blockPolicy *safety.BlockPolicy
+}
+
+func NewSuggestionService(authRepo *auth.Repository, followsRepo *follows.Repository, anchorsRepo *anchors.Repository, mutesRepo *mutes.Repository, blockPolicy *safety.BlockPolicy) *SuggestionService {
+	return &SuggestionService{
+		authRepo:    authRepo,
+		followsRepo: followsRepo,
+		anchorsRepo: anchorsRepo,
+		mutesRepo:   mutesRepo,
+		blockPolicy: blockPolicy,
+	}
+}
+
+// candidate accumulates the signals for one suggested user
+type candidate struct {
+	userID      primitive.ObjectID
+	mutualCount int
+	followedBy  []primitive.ObjectID
+	sharedTags  []string
+	engagement  int
+}
+
+// GetSuggestions ranks users by mutual follows, overlap between the user's interests and the
+// tags they publish, and anchor engagement. Falls back to popular creators when neither
+// signal yields anyone, e.g. for brand new accounts.
+func (s *SuggestionService) GetSuggestions(ctx context.Context, user *auth.User, limit int) ([]UserSuggestion, error) {
+	followingIDs, err := s.followsRepo.GetAllFollowingIDs(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeIDs := s.excludedUserIDs(ctx, user.ID, followingIDs)
+	poolSize := limit * candidatePoolSize
+
+	candidates := make(map[primitive.ObjectID]*candidate)
+	get := func(id primitive.ObjectID) *candidate {
+		if cand, ok := candidates[id]; ok {
+			return cand
+		}
+		cand := &candidate{userID: id}
+		candidates[id] = cand
+		return cand
+	}
+
+	// 1. Friends of friends
+	mutuals, err := s.followsRepo.GetMostFollowedBy(ctx, followingIDs, excludeIDs, poolSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mutuals {
+		cand := get(m.UserID)
+		cand.mutualCount = m.Count
+		cand.followedBy = m.FollowedBy
+	}
+
+	// 2. Creators publishing the user's interests
+	interests := normalizeTags(user.Interests)
+	if len(interests) > 0 {
+		matches, err := s.anchorsRepo.GetAuthorsByTags(ctx, interests, excludeIDs, poolSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			get(m.UserID).sharedTags = flattenTags(m.MatchedTags)
+		}
+	}
+
+	// 3. Nothing to go on yet - suggest popular creators
+	if len(candidates) == 0 {
+		popular, err := s.anchorsRepo.GetAuthorsByTags(ctx, nil, excludeIDs, poolSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range popular {
+			get(p.UserID)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return []UserSuggestion{}, nil
+	}
+
+	candidateIDs := make([]primitive.ObjectID, 0, len(candidates))
+	for id := range candidates {
+		candidateIDs = append(candidateIDs, id)
+	}
+
+	engagement, err := s.anchorsRepo.GetAuthorEngagement(ctx, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	for id, score := range engagement {
+		candidates[id].engagement = score
+	}
+
+	// Batch fetch candidates and the mutual follows named in explanations
+	lookupIDs := append([]primitive.ObjectID{}, candidateIDs...)
+	for _, cand := range candidates {
+		lookupIDs = append(lookupIDs, cand.followedBy...)
+	}
+	usersList, err := s.authRepo.GetUsersByIDs(ctx, lookupIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersMap := make(map[primitive.ObjectID]*auth.User, len(usersList))
+	for i := range usersList {
+		usersMap[usersList[i].ID] = &usersList[i]
+	}
+
+	suggestions := make([]UserSuggestion, 0, len(candidates))
+	for _, cand := range candidates {
+		u, ok := usersMap[cand.userID]
+		if !ok || u.IsDeactivated() || u.IsSuspended() {
+			continue
+		}
+		suggestions = append(suggestions, buildSuggestion(u, cand, usersMap))
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].User.FollowerCount > suggestions[j].User.FollowerCount
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// excludedUserIDs lists users never suggested: the user themselves, accounts already followed or
+// requested, blocks in either direction, mutes and deactivated accounts
+func (s *SuggestionService) excludedUserIDs(ctx context.Context, userID primitive.ObjectID, followingIDs []primitive.ObjectID) []primitive.ObjectID {
+	excluded := append([]primitive.ObjectID{userID}, followingIDs...)
+
+	if requested, err := s.followsRepo.GetRequestedTargetIDs(ctx, userID); err == nil {
+		excluded = append(excluded, requested...)
+	}
+	excluded = append(excluded, s.blockPolicy.BlockedUserIDs(ctx, userID)...)
+	if muted, err := s.mutesRepo.GetMutedUserIDs(ctx, userID); err == nil {
+		excluded = append(excluded, muted...)
+	}
+	if deactivated, err := s.authRepo.GetDeactivatedUserIDs(ctx); err == nil {
+		excluded = append(excluded, deactivated...)
+	}
+
+	return excluded
+}
+
+// buildSuggestion scores a candidate and explains the strongest signal
+func buildSuggestion(u *auth.User, cand *candidate, usersMap map[primitive.ObjectID]*auth.User) UserSuggestion {
+	followedBy := make([]string, 0, len(cand.followedBy))
+	for _, id := range cand.followedBy {
+		if f, ok := usersMap[id]; ok {
+			followedBy = append(followedBy, f.Username)
+		}
+	}
+
+	sharedTags := cand.sharedTags
+	if sharedTags == nil {
+		sharedTags = []string{}
+	}
+
+	score := float64(cand.mutualCount)*mutualFollowWeight +
+		float64(len(sharedTags))*sharedTagWeight +
+		math.Log1p(float64(max(cand.engagement, 0)))
+
+	suggestion := UserSuggestion{
+		User: SuggestedUser{
+			ID:                u.ID,
+			Username:          u.Username,
+			DisplayName:       u.DisplayName,
+			Bio:               u.Bio,
+			ProfilePictureURL: u.ProfilePictureURL,
+			IsVerified:        u.IsVerified,
+			IsPrivate:         u.IsPrivate,
+			FollowerCount:     u.FollowerCount,
+		},
+		MutualFollowCount: cand.mutualCount,
+		FollowedBy:        followedBy,
+		SharedTags:        sharedTags,
+		Score:             math.Round(score*100) / 100,
+	}
+
+	switch {
+	case cand.mutualCount > 0 && len(followedBy) > 0:
+		suggestion.Reason = ReasonMutualFollows
+		suggestion.Explanation = explainMutualFollows(followedBy[0], cand.mutualCount)
+	case len(sharedTags) > 0:
+		suggestion.Reason = ReasonInterests
+		suggestion.Explanation = explainSharedTags(sharedTags)
+	default:
+		suggestion.Reason = ReasonPopular
+		suggestion.Explanation = "Popular creator"
+	}
+
+	return suggestion
+}
+
+// explainMutualFollows renders "Followed by alice" or "Followed by alice and 3 others"
+func explainMutualFollows(first string, count int) string {
+	switch count {
+	case 1:
+		return "Followed by " + first
+	case 2:
+		return fmt.Sprintf("Followed by %s and 1 other", first)
+	default:
+		return fmt.Sprintf("Followed by %s and %d others", first, count-1)
+	}
+}
+
+// explainSharedTags renders "Posts about #go" or "Posts about #go, #design and 2 more"
+func explainSharedTags(tags []string) string {
+	const shown = 2
+	labels := make([]string, 0, shown)
+	for i := 0; i < len(tags) && i < shown; i++ {
+		labels = append(labels, "#"+tags[i])
+	}
+	explanation := "Posts about " + strings.Join(labels, ", ")
+	if len(tags) > shown {
+		explanation += fmt.Sprintf(" and %d more", len(tags)-shown)
+	}
+	return explanation
+}
+
+// normalizeTags lowercases and de-duplicates tags
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	return result
+}
+
+// flattenTags merges per-anchor tag matches into a sorted, de-duplicated list
+func flattenTags(perAnchor [][]string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, tags := range perAnchor {
+		for _, t := range tags {
+			if !seen[t] {
+				seen[t] = true
+				result = append(result, t)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
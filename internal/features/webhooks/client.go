@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	deliveryTimeout = 10 * time.Second
+	dialTimeout     = 5 * time.Second
+)
+
+// errPrivateAddress is returned when an endpoint resolves to a loopback, private,
+// link-local or other special-purpose address
+var errPrivateAddress = errors.New("webhook endpoint resolves to a non-public address")
+
+// newClient returns the HTTP client for deliveries. Unless allowPrivate is set it refuses
+// to connect to non-public addresses, so webhooks cannot probe the internal network.
+// Redirects are not followed.
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if allowPrivate {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: deliveryTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: dialTimeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// specialPurposeNets are non-public ranges the net.IP helpers don't cover (RFC 6890)
+var specialPurposeNets = mustParseCIDRs(
+	"0.0.0.0/8",       // "This network"
+	"100.64.0.0/10",   // Carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // TEST-NET-1
+	"198.18.0.0/15",   // Benchmarking
+	"198.51.100.0/24", // TEST-NET-2
+	"203.0.113.0/24",  // TEST-NET-3
+	"240.0.0.0/4",     // Reserved, including broadcast
+	"64:ff9b::/96",    // NAT64, may translate to an internal IPv4 address
+	"64:ff9b:1::/48",  // Local-use NAT64
+	"100::/64",        // Discard-only
+	"2001:db8::/32",   // Documentation
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range specialPurposeNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
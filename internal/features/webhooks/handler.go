@@ -0,0 +1,363 @@
+package webhooks
+
+import (
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Handler struct {
+	repo    *Repository
+	service *Service
+	config  *config.Config
+}
+
+func NewHandler(repo *Repository, service *Service, cfg *config.Config) *Handler {
+	return &Handler{
+		repo:    repo,
+		service: service,
+		config:  cfg,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook
+// @Description Register an HTTPS endpoint to receive events (item.added, anchor.liked, comment.created, follow.created) concerning you.
+// @Description Deliveries are signed with the returned secret, which is only shown once: X-Anchor-Signature is "t=<unix>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateWebhookRequest true "Webhook details"
+// @Success 201 {object} response.APIResponse{data=CreateWebhookResponse}
+// @Failure 400 {object} response.APIResponse
+// @Router /webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+	if err := ValidateURL(req.URL, h.config.DevMode); err != nil {
+		response.BadRequest(c, err.Error(), "INVALID_URL")
+		return
+	}
+	events, err := validateEvents(req.Events)
+	if err != nil {
+		response.BadRequest(c, err.Error(), "INVALID_EVENT")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	count, err := h.repo.CountUserWebhooks(ctx, user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to create webhook", "DATABASE_ERROR")
+		return
+	}
+	if count >= MaxWebhooksPerUser {
+		response.BadRequest(c, "You can have up to 10 webhooks", "WEBHOOK_LIMIT_REACHED")
+		return
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		response.InternalServerError(c, "Failed to create webhook", "INTERNAL_ERROR")
+		return
+	}
+
+	webhook := &Webhook{
+		UserID:      user.ID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      events,
+		Description: strings.TrimSpace(req.Description),
+	}
+	if err := h.repo.CreateWebhook(ctx, webhook); err != nil {
+		response.InternalServerError(c, "Failed to create webhook", "DATABASE_ERROR")
+		return
+	}
+
+	response.Created(c, CreateWebhookResponse{Webhook: webhook, Secret: secret}, "Webhook created")
+}
+
+// ListWebhooks godoc
+// @Summary List your webhooks
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.APIResponse{data=WebhooksResponse}
+// @Router /webhooks [get]
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.repo.GetUserWebhooks(c.Request.Context(), user.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch webhooks", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, WebhooksResponse{Webhooks: webhooks})
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.APIResponse{data=Webhook}
+// @Failure 404 {object} response.APIResponse
+// @Router /webhooks/{id} [get]
+func (h *Handler) GetWebhook(c *gin.Context) {
+	webhook, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+
+	response.Success(c, webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Change the URL, events or description, or pause and resume deliveries. Re-enabling a disabled webhook resets its failure count.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param request body UpdateWebhookRequest true "Fields to update"
+// @Success 200 {object} response.APIResponse{data=Webhook}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /webhooks/{id} [patch]
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	webhook, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error(), "VALIDATION_FAILED")
+		return
+	}
+
+	set := bson.M{}
+	if req.URL != nil {
+		if err := ValidateURL(*req.URL, h.config.DevMode); err != nil {
+			response.BadRequest(c, err.Error(), "INVALID_URL")
+			return
+		}
+		set["url"] = *req.URL
+	}
+	if req.Events != nil {
+		events, err := validateEvents(req.Events)
+		if err != nil {
+			response.BadRequest(c, err.Error(), "INVALID_EVENT")
+			return
+		}
+		set["events"] = events
+	}
+	if req.Description != nil {
+		set["description"] = strings.TrimSpace(*req.Description)
+	}
+
+	ctx := c.Request.Context()
+
+	if len(set) > 0 {
+		if err := h.repo.UpdateWebhook(ctx, webhook.ID, set); err != nil {
+			response.InternalServerError(c, "Failed to update webhook", "DATABASE_ERROR")
+			return
+		}
+	}
+	if req.Active != nil && *req.Active != webhook.Active {
+		var err error
+		if *req.Active {
+			err = h.repo.EnableWebhook(ctx, webhook.ID)
+		} else {
+			err = h.repo.DisableWebhook(ctx, webhook.ID, "Paused by owner")
+		}
+		if err != nil {
+			response.InternalServerError(c, "Failed to update webhook", "DATABASE_ERROR")
+			return
+		}
+	}
+
+	updated, err := h.repo.GetWebhook(ctx, webhook.ID)
+	if err != nil || updated == nil {
+		response.InternalServerError(c, "Failed to fetch webhook", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, updated, "Webhook updated")
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery log
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Router /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	webhook, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteWebhook(c.Request.Context(), webhook.ID); err != nil {
+		response.InternalServerError(c, "Failed to delete webhook", "DATABASE_ERROR")
+		return
+	}
+
+	response.Success(c, nil, "Webhook deleted")
+}
+
+// ListDeliveries godoc
+// @Summary List a webhook's deliveries
+// @Description Delivery log of the last 30 days, newest first, with the endpoint's response
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Items per page (default 20, max 50)"
+// @Success 200 {object} response.APIResponse{data=DeliveriesResponse}
+// @Failure 404 {object} response.APIResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	webhook, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+
+	var query DeliveryListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "Invalid query parameters", "INVALID_QUERY")
+		return
+	}
+
+	deliveries, total, err := h.repo.GetDeliveries(c.Request.Context(), webhook.ID, query.Page, query.Limit)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch deliveries", "DATABASE_ERROR")
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(query.Limit)))
+
+	resp := DeliveriesResponse{Deliveries: deliveries}
+	resp.Pagination.Page = query.Page
+	resp.Pagination.Limit = query.Limit
+	resp.Pagination.Total = total
+	resp.Pagination.TotalPages = totalPages
+	resp.Pagination.HasMore = query.Page < totalPages
+
+	response.Success(c, resp)
+}
+
+// ReplayDelivery godoc
+// @Summary Replay a delivery
+// @Description Send a logged event to the webhook again. The replay is a new delivery with the same event ID and body.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} response.APIResponse{data=Delivery}
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Router /webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (h *Handler) ReplayDelivery(c *gin.Context) {
+	webhook, ok := h.ownedWebhook(c)
+	if !ok {
+		return
+	}
+	if !webhook.Active {
+		response.Conflict(c, "Enable the webhook before replaying deliveries", "WEBHOOK_DISABLED")
+		return
+	}
+
+	deliveryID, err := primitive.ObjectIDFromHex(c.Param("deliveryId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid delivery ID", "INVALID_ID")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	original, err := h.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch delivery", "DATABASE_ERROR")
+		return
+	}
+	if original == nil || original.WebhookID != webhook.ID {
+		response.NotFound(c, "Delivery not found", "DELIVERY_NOT_FOUND")
+		return
+	}
+
+	delivery, err := h.service.Replay(ctx, original)
+	if err != nil {
+		response.InternalServerError(c, "Failed to replay delivery", "DATABASE_ERROR")
+		return
+	}
+
+	response.Respond(c, http.StatusAccepted, true, "Delivery queued", delivery)
+}
+
+// ownedWebhook loads the :id webhook if the current user owns it, writing the error response otherwise
+func (h *Handler) ownedWebhook(c *gin.Context) (*Webhook, bool) {
+	user, ok := currentUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	webhookID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid webhook ID", "INVALID_ID")
+		return nil, false
+	}
+
+	webhook, err := h.repo.GetWebhook(c.Request.Context(), webhookID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to fetch webhook", "DATABASE_ERROR")
+		return nil, false
+	}
+	if webhook == nil || webhook.UserID != user.ID {
+		response.NotFound(c, "Webhook not found", "WEBHOOK_NOT_FOUND")
+		return nil, false
+	}
+	return webhook, true
+}
+
+func currentUser(c *gin.Context) (*auth.User, bool) {
+	val, exists := c.Get("user")
+	if !exists {
+		response.Unauthorized(c, "Authentication required", "AUTH_FAILED")
+		return nil, false
+	}
+	user, ok := val.(*auth.User)
+	if !ok {
+		response.InternalServerError(c, "User context error", "INTERNAL_ERROR")
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,166 @@
+package webhooks
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Events a webhook can subscribe to. Each is delivered to the webhooks of the user it
+// concerns: the anchor owner for items, likes and comments, the followed user for follows.
+const (
+	EventItemAdded      = "item.added"
+	EventAnchorLiked    = "anchor.liked"
+	EventCommentCreated = "comment.created"
+	EventFollowCreated  = "follow.created"
+)
+
+// Events lists every event a webhook can subscribe to
+var Events = []string{
+	EventItemAdded,
+	EventAnchorLiked,
+	EventCommentCreated,
+	EventFollowCreated,
+}
+
+// Limits
+const (
+	MaxWebhooksPerUser = 10
+	// MaxConsecutiveFailures disables a webhook after this many deliveries in a row
+	// failed all their attempts
+	MaxConsecutiveFailures = 10
+)
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryRetrying  = "retrying" // Failed at least once, another attempt is scheduled
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"  // Out of attempts
+	DeliverySkipped   = "skipped" // The webhook was disabled or deleted before it ran
+)
+
+// Webhook is an endpoint a user registered to receive events
+type Webhook struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         primitive.ObjectID `bson:"userId" json:"userId"` // Owner; receives events concerning this user
+	URL            string             `bson:"url" json:"url"`
+	Secret         string             `bson:"secret" json:"-"` // Signs deliveries
+	Events         []string           `bson:"events" json:"events"`
+	Description    string             `bson:"description" json:"description"`
+	Active         bool               `bson:"active" json:"active"`
+	FailureCount   int                `bson:"failureCount" json:"failureCount"` // Consecutive failed deliveries
+	DisabledAt     *time.Time         `bson:"disabledAt,omitempty" json:"disabledAt,omitempty"`
+	DisabledReason string             `bson:"disabledReason,omitempty" json:"disabledReason,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Delivery is one event sent (or being sent) to one webhook
+type Delivery struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	WebhookID      primitive.ObjectID  `bson:"webhookId" json:"webhookId"`
+	UserID         primitive.ObjectID  `bson:"userId" json:"-"`
+	EventID        primitive.ObjectID  `bson:"eventId" json:"eventId"` // Shared by every delivery (and replay) of the event
+	Event          string              `bson:"event" json:"event"`
+	Payload        string              `bson:"payload" json:"payload"` // JSON body, sent unchanged on every attempt
+	Status         string              `bson:"status" json:"status"`
+	Attempts       int                 `bson:"attempts" json:"attempts"`
+	ResponseStatus int                 `bson:"responseStatus,omitempty" json:"responseStatus,omitempty"`
+	ResponseBody   string              `bson:"responseBody,omitempty" json:"responseBody,omitempty"` // Truncated
+	Error          string              `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs     int64               `bson:"durationMs,omitempty" json:"durationMs,omitempty"`
+	ReplayOf       *primitive.ObjectID `bson:"replayOf,omitempty" json:"replayOf,omitempty"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+	DeliveredAt    *time.Time          `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"` // Last attempt
+}
+
+// EventPayload is the JSON body of every delivery
+type EventPayload struct {
+	ID        primitive.ObjectID `json:"id"`
+	Type      string             `json:"type"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Data      interface{}        `json:"data"`
+}
+
+// Actor is the user who caused an event
+type Actor struct {
+	ID       primitive.ObjectID `json:"id"`
+	Username string             `json:"username"`
+}
+
+// ItemAddedData is the data of item.added
+type ItemAddedData struct {
+	AnchorID    primitive.ObjectID `json:"anchorId"`
+	AnchorTitle string             `json:"anchorTitle"`
+	ItemID      primitive.ObjectID `json:"itemId"`
+	ItemType    string             `json:"itemType"`
+	Actor       Actor              `json:"actor"`
+}
+
+// AnchorLikedData is the data of anchor.liked
+type AnchorLikedData struct {
+	AnchorID    primitive.ObjectID `json:"anchorId"`
+	AnchorTitle string             `json:"anchorTitle"`
+	Actor       Actor              `json:"actor"`
+}
+
+// CommentCreatedData is the data of comment.created
+type CommentCreatedData struct {
+	CommentID primitive.ObjectID  `json:"commentId"`
+	AnchorID  primitive.ObjectID  `json:"anchorId"`
+	ItemID    *primitive.ObjectID `json:"itemId,omitempty"`
+	ParentID  *primitive.ObjectID `json:"parentId,omitempty"`
+	Content   string              `json:"content"`
+	Actor     Actor               `json:"actor"`
+}
+
+// FollowCreatedData is the data of follow.created
+type FollowCreatedData struct {
+	FollowingID primitive.ObjectID `json:"followingId"`
+	Actor       Actor              `json:"actor"` // The new follower
+}
+
+// CreateWebhookRequest for POST /webhooks
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Events      []string `json:"events" binding:"required,min=1,dive,required"`
+	Description string   `json:"description" binding:"max=200"`
+}
+
+// UpdateWebhookRequest for PATCH /webhooks/:id
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" binding:"omitempty,url,max=2048"`
+	Events      []string `json:"events" binding:"omitempty,min=1,dive,required"`
+	Description *string  `json:"description" binding:"omitempty,max=200"`
+	Active      *bool    `json:"active"` // Re-enabling resets the failure count
+}
+
+// DeliveryListQuery for GET /webhooks/:id/deliveries
+type DeliveryListQuery struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// CreateWebhookResponse includes the signing secret, which is only shown once
+type CreateWebhookResponse struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
+// WebhooksResponse for GET /webhooks
+type WebhooksResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// DeliveriesResponse for GET /webhooks/:id/deliveries
+type DeliveriesResponse struct {
+	Deliveries []Delivery `json:"deliveries"`
+	Pagination struct {
+		Page       int   `json:"page"`
+		Limit      int   `json:"limit"`
+		Total      int64 `json:"total"`
+		TotalPages int   `json:"totalPages"`
+		HasMore    bool  `json:"hasMore"`
+	} `json:"pagination"`
+}
@@ -0,0 +1,266 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// deliveryRetention is how long the delivery log is kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+// Repository handles database interactions for webhooks and their deliveries
+type Repository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+// NewRepository creates repository and ensures indexes
+func NewRepository(db *mongo.Database) *Repository {
+	webhooks := db.Collection("webhooks")
+	_, _ = webhooks.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// Subscribers of an event
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "active", Value: 1}, {Key: "events", Value: 1}},
+		},
+	})
+
+	deliveries := db.Collection("webhookDeliveries")
+	_, _ = deliveries.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// A webhook's delivery log, newest first
+			Keys: bson.D{{Key: "webhookId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(deliveryRetention.Seconds())),
+		},
+		{
+			// Account purge
+			Keys: bson.D{{Key: "userId", Value: 1}},
+		},
+	})
+
+	return &Repository{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+	}
+}
+
+// CreateWebhook inserts a new active webhook
+func (r *Repository) CreateWebhook(ctx context.Context, webhook *Webhook) error {
+	now := time.Now()
+	webhook.ID = primitive.NewObjectID()
+	webhook.Active = true
+	webhook.FailureCount = 0
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	_, err := r.webhooks.InsertOne(ctx, webhook)
+	return err
+}
+
+// GetWebhook retrieves a webhook, nil if it does not exist
+func (r *Repository) GetWebhook(ctx context.Context, webhookID primitive.ObjectID) (*Webhook, error) {
+	var webhook Webhook
+	err := r.webhooks.FindOne(ctx, bson.M{"_id": webhookID}).Decode(&webhook)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetUserWebhooks returns a user's webhooks, oldest first
+func (r *Repository) GetUserWebhooks(ctx context.Context, userID primitive.ObjectID) ([]Webhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.webhooks.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CountUserWebhooks counts a user's webhooks
+func (r *Repository) CountUserWebhooks(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.webhooks.CountDocuments(ctx, bson.M{"userId": userID})
+}
+
+// GetSubscribers returns a user's active webhooks subscribed to the event
+func (r *Repository) GetSubscribers(ctx context.Context, userID primitive.ObjectID, event string) ([]Webhook, error) {
+	cursor, err := r.webhooks.Find(ctx, bson.M{"userId": userID, "active": true, "events": event})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook applies a $set to a webhook
+func (r *Repository) UpdateWebhook(ctx context.Context, webhookID primitive.ObjectID, set bson.M) error {
+	set["updatedAt"] = time.Now()
+	_, err := r.webhooks.UpdateOne(ctx, bson.M{"_id": webhookID}, bson.M{"$set": set})
+	return err
+}
+
+// EnableWebhook re-activates a webhook and forgets its failures
+func (r *Repository) EnableWebhook(ctx context.Context, webhookID primitive.ObjectID) error {
+	_, err := r.webhooks.UpdateOne(ctx, bson.M{"_id": webhookID}, bson.M{
+		"$set":   bson.M{"active": true, "failureCount": 0, "updatedAt": time.Now()},
+		"$unset": bson.M{"disabledAt": "", "disabledReason": ""},
+	})
+	return err
+}
+
+// DisableWebhook stops deliveries to a webhook
+func (r *Repository) DisableWebhook(ctx context.Context, webhookID primitive.ObjectID, reason string) error {
+	now := time.Now()
+	_, err := r.webhooks.UpdateOne(ctx, bson.M{"_id": webhookID}, bson.M{"$set": bson.M{
+		"active":         false,
+		"disabledAt":     now,
+		"disabledReason": reason,
+		"updatedAt":      now,
+	}})
+	return err
+}
+
+// RecordSuccess resets a webhook's consecutive failure count
+func (r *Repository) RecordSuccess(ctx context.Context, webhookID primitive.ObjectID) error {
+	_, err := r.webhooks.UpdateOne(ctx,
+		bson.M{"_id": webhookID, "failureCount": bson.M{"$gt": 0}},
+		bson.M{"$set": bson.M{"failureCount": 0}},
+	)
+	return err
+}
+
+// RecordFailure counts a failed delivery and returns the webhook's consecutive failures
+func (r *Repository) RecordFailure(ctx context.Context, webhookID primitive.ObjectID) (int, error) {
+	opts := options.FindOneAndUpdate().
+		SetProjection(bson.M{"failureCount": 1}).
+		SetReturnDocument(options.After)
+
+	var updated struct {
+		FailureCount int `bson:"failureCount"`
+	}
+	err := r.webhooks.FindOneAndUpdate(ctx, bson.M{"_id": webhookID}, bson.M{"$inc": bson.M{"failureCount": 1}}, opts).Decode(&updated)
+	if err != nil {
+		return 0, err
+	}
+	return updated.FailureCount, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (r *Repository) DeleteWebhook(ctx context.Context, webhookID primitive.ObjectID) error {
+	if _, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": webhookID}); err != nil {
+		return err
+	}
+	_, err := r.deliveries.DeleteMany(ctx, bson.M{"webhookId": webhookID})
+	return err
+}
+
+// DeleteAllForUser removes a user's webhooks and delivery log
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.webhooks.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return err
+	}
+	_, err := r.deliveries.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
+
+// CreateDelivery inserts a pending delivery
+func (r *Repository) CreateDelivery(ctx context.Context, delivery *Delivery) error {
+	delivery.ID = primitive.NewObjectID()
+	delivery.Status = DeliveryPending
+	delivery.Attempts = 0
+	delivery.CreatedAt = time.Now()
+
+	_, err := r.deliveries.InsertOne(ctx, delivery)
+	return err
+}
+
+// GetDelivery retrieves a delivery, nil if it does not exist
+func (r *Repository) GetDelivery(ctx context.Context, deliveryID primitive.ObjectID) (*Delivery, error) {
+	var delivery Delivery
+	err := r.deliveries.FindOne(ctx, bson.M{"_id": deliveryID}).Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetAllDeliveries returns a webhook's whole delivery log, newest first (for data export)
+func (r *Repository) GetAllDeliveries(ctx context.Context, webhookID primitive.ObjectID) ([]Delivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.deliveries.Find(ctx, bson.M{"webhookId": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []Delivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDeliveries returns a page of a webhook's delivery log, newest first, with the total count
+func (r *Repository) GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, page, limit int) ([]Delivery, int64, error) {
+	filter := bson.M{"webhookId": webhookID}
+
+	total, err := r.deliveries.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []Delivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt
+func (r *Repository) UpdateDelivery(ctx context.Context, delivery *Delivery) error {
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": delivery.ID}, bson.M{"$set": bson.M{
+		"status":         delivery.Status,
+		"attempts":       delivery.Attempts,
+		"responseStatus": delivery.ResponseStatus,
+		"responseBody":   delivery.ResponseBody,
+		"error":          delivery.Error,
+		"durationMs":     delivery.DurationMs,
+		"deliveredAt":    delivery.DeliveredAt,
+	}})
+	return err
+}
@@ -0,0 +1,32 @@
+package webhooks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/middleware"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RegisterRoutes registers the webhook management routes. Deliveries run on the
+// background job pool (see Service.RegisterJobs).
+func RegisterRoutes(router *gin.RouterGroup, db *mongo.Database, cfg *config.Config) {
+	repo := NewRepository(db)
+	authRepo := auth.NewRepository(db)
+	handler := NewHandler(repo, NewService(db, cfg), cfg)
+
+	// Webhooks receive the account's activity, so they need a session, not a personal access token
+	authMiddleware := middleware.NewAuthMiddleware(authRepo, cfg)
+
+	webhooks := router.Group("/webhooks")
+	webhooks.Use(authMiddleware)
+	{
+		webhooks.POST("", handler.CreateWebhook)
+		webhooks.GET("", handler.ListWebhooks)
+		webhooks.GET("/:id", handler.GetWebhook)
+		webhooks.PATCH("/:id", handler.UpdateWebhook)
+		webhooks.DELETE("/:id", handler.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", handler.ListDeliveries)
+		webhooks.POST("/:id/deliveries/:deliveryId/replay", handler.ReplayDelivery)
+	}
+}
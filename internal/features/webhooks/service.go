@@ -0,0 +1,213 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/pkg/jobqueue"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// JobDeliver sends one delivery; failed attempts are retried by the job queue with backoff
+const JobDeliver = "webhooks.deliver"
+
+// maxResponseBody caps how much of an endpoint's response is kept in the delivery log
+const maxResponseBody = 1024
+
+type deliveryJob struct {
+	DeliveryID primitive.ObjectID `bson:"deliveryId"`
+}
+
+// Service publishes events to webhooks and delivers them
+type Service struct {
+	repo   *Repository
+	queue  *jobqueue.Queue
+	client *http.Client
+}
+
+// NewService creates the webhook service. In DevMode endpoints may use plain HTTP and
+// private addresses, for testing against local servers.
+func NewService(db *mongo.Database, cfg *config.Config) *Service {
+	return &Service{
+		repo:   NewRepository(db),
+		queue:  jobqueue.NewQueue(db),
+		client: newClient(cfg.DevMode),
+	}
+}
+
+// Publish queues an event for the user's active webhooks subscribed to it
+func (s *Service) Publish(ctx context.Context, userID primitive.ObjectID, event string, data interface{}) error {
+	webhooks, err := s.repo.GetSubscribers(ctx, userID, event)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload := EventPayload{
+		ID:        primitive.NewObjectID(),
+		Type:      event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		delivery := &Delivery{
+			WebhookID: webhook.ID,
+			UserID:    userID,
+			EventID:   payload.ID,
+			Event:     event,
+			Payload:   string(body),
+		}
+		if err := s.enqueue(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replay sends a logged delivery's event to its webhook again, as a new delivery
+func (s *Service) Replay(ctx context.Context, original *Delivery) (*Delivery, error) {
+	originalID := original.ID
+	delivery := &Delivery{
+		WebhookID: original.WebhookID,
+		UserID:    original.UserID,
+		EventID:   original.EventID,
+		Event:     original.Event,
+		Payload:   original.Payload,
+		ReplayOf:  &originalID,
+	}
+	if err := s.enqueue(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (s *Service) enqueue(ctx context.Context, delivery *Delivery) error {
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+	_, err := s.queue.Enqueue(ctx, JobDeliver, deliveryJob{DeliveryID: delivery.ID}, JobDeliver+":"+delivery.ID.Hex())
+	return err
+}
+
+// RegisterJobs adds the delivery job handler to a worker pool
+func (s *Service) RegisterJobs(pool *jobqueue.Pool) {
+	pool.Handle(JobDeliver, s.deliver)
+}
+
+// deliver makes one attempt at a delivery. Returning an error has the queue retry it;
+// once out of attempts the delivery counts against the webhook, which is disabled after
+// MaxConsecutiveFailures.
+func (s *Service) deliver(ctx context.Context, job *jobqueue.Job) error {
+	var p deliveryJob
+	if err := job.Decode(&p); err != nil {
+		return err
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, p.DeliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery == nil {
+		return nil // Deleted along with its webhook
+	}
+
+	webhook, err := s.repo.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	if webhook == nil || !webhook.Active {
+		delivery.Status = DeliverySkipped
+		return s.repo.UpdateDelivery(ctx, delivery)
+	}
+
+	delivery.Attempts = job.Attempts
+	sendErr := s.send(ctx, webhook, delivery)
+	if sendErr != nil && ctx.Err() != nil {
+		return sendErr // Shutting down; the queue hands the job back without counting it
+	}
+
+	now := time.Now()
+	delivery.DeliveredAt = &now
+
+	if sendErr == nil {
+		delivery.Status = DeliverySucceeded
+		delivery.Error = ""
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+		return s.repo.RecordSuccess(ctx, webhook.ID)
+	}
+
+	delivery.Error = sendErr.Error()
+	if job.Attempts < job.MaxAttempts {
+		delivery.Status = DeliveryRetrying
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			log.Printf("Webhooks: failed to update delivery %s: %v", delivery.ID.Hex(), err)
+		}
+		return sendErr
+	}
+
+	delivery.Status = DeliveryFailed
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		log.Printf("Webhooks: failed to update delivery %s: %v", delivery.ID.Hex(), err)
+	}
+
+	failures, err := s.repo.RecordFailure(ctx, webhook.ID)
+	if err != nil {
+		log.Printf("Webhooks: failed to record failure for webhook %s: %v", webhook.ID.Hex(), err)
+	} else if failures >= MaxConsecutiveFailures {
+		reason := fmt.Sprintf("%d consecutive deliveries failed", failures)
+		if err := s.repo.DisableWebhook(ctx, webhook.ID, reason); err != nil {
+			log.Printf("Webhooks: failed to disable webhook %s: %v", webhook.ID.Hex(), err)
+		}
+	}
+	return sendErr
+}
+
+// send POSTs the delivery's payload, recording the response on the delivery
+func (s *Service) send(ctx context.Context, webhook *Webhook, delivery *Delivery) error {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Anchor-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, time.Now().Unix(), body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.ResponseStatus = 0
+		delivery.ResponseBody = ""
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(respBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
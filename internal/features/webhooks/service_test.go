@@ -0,0 +1,68 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSendSignsAndRecordsResponse(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got, gotBody = r, string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s := &Service{client: newClient(true)}
+	webhook := &Webhook{URL: server.URL, Secret: "whsec_test"}
+	delivery := &Delivery{ID: primitive.NewObjectID(), Event: EventAnchorLiked, Payload: `{"type":"anchor.liked"}`}
+
+	require.NoError(t, s.send(context.Background(), webhook, delivery))
+	require.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+
+	require.Equal(t, delivery.Payload, gotBody)
+	require.Equal(t, EventAnchorLiked, got.Header.Get(HeaderEvent))
+	require.Equal(t, delivery.ID.Hex(), got.Header.Get(HeaderDelivery))
+
+	// The signature covers the timestamp it carries and the exact body
+	sig := got.Header.Get(HeaderSignature)
+	ts := strings.TrimPrefix(strings.SplitN(sig, ",", 2)[0], "t=")
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	require.NoError(t, err)
+	require.Equal(t, Sign("whsec_test", unix, []byte(gotBody)), sig)
+}
+
+func TestSendFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, strings.Repeat("x", 4096), http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	s := &Service{client: newClient(true)}
+	delivery := &Delivery{ID: primitive.NewObjectID(), Event: EventFollowCreated, Payload: `{}`}
+
+	require.Error(t, s.send(context.Background(), &Webhook{URL: server.URL}, delivery))
+	require.Equal(t, http.StatusBadGateway, delivery.ResponseStatus)
+	require.Len(t, delivery.ResponseBody, maxResponseBody)
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	s := &Service{client: newClient(false)}
+	delivery := &Delivery{ID: primitive.NewObjectID(), Payload: `{}`}
+
+	err := s.send(context.Background(), &Webhook{URL: server.URL}, delivery)
+	require.ErrorIs(t, err, errPrivateAddress)
+}
@@ -0,0 +1,39 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Delivery headers
+const (
+	HeaderEvent     = "X-Anchor-Event"
+	HeaderDelivery  = "X-Anchor-Delivery"
+	HeaderSignature = "X-Anchor-Signature"
+)
+
+// Sign returns the X-Anchor-Signature value for a body sent at timestamp (Unix seconds):
+// "t=<timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret>".
+// Receivers recompute it and should reject old timestamps to prevent replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newSecret generates a webhook signing secret
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
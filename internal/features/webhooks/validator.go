@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/url"
+)
+
+// ValidateURL checks a webhook endpoint: HTTPS (or HTTP when allowInsecure), no
+// credentials, and not a literal non-public IP unless allowInsecure
+func ValidateURL(raw string, allowInsecure bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return errors.New("url must be an absolute URL")
+	}
+	if u.Scheme != "https" && !(allowInsecure && u.Scheme == "http") {
+		return errors.New("url must use https")
+	}
+	if u.User != nil {
+		return errors.New("url must not contain credentials")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !allowInsecure && !isPublicIP(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// validateEvents checks event names and removes duplicates
+func validateEvents(events []string) ([]string, error) {
+	known := make(map[string]bool, len(Events))
+	for _, e := range Events {
+		known[e] = true
+	}
+
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		if !known[e] {
+			return nil, errors.New("unknown event: " + e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -10,8 +11,10 @@ import (
 	"github.com/xyz-asif/gotodo/internal/pkg/response"
 )
 
-// NewAuthMiddleware creates a Gin middleware for JWT authentication
-func NewAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.HandlerFunc {
+// NewAuthMiddleware creates a Gin middleware for JWT authentication.
+// Personal access tokens are also accepted, but only on route groups that
+// declare the scopes they require; the token must grant all of them.
+func NewAuthMiddleware(repo *auth.Repository, cfg *config.Config, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -28,6 +31,39 @@ func NewAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.HandlerFun
 		}
 
 		tokenString := parts[1]
+		if auth.IsPersonalAccessToken(tokenString) {
+			pat, err := lookupPersonalAccessToken(c.Request.Context(), repo, tokenString)
+			if err != nil || pat == nil {
+				response.Unauthorized(c, "Invalid or expired token", "INVALID_TOKEN")
+				c.Abort()
+				return
+			}
+
+			if len(scopes) == 0 || !pat.HasScopes(scopes...) {
+				response.Forbidden(c, "Token does not have the required scope", "INSUFFICIENT_SCOPE")
+				c.Abort()
+				return
+			}
+
+			user, err := repo.GetUserByObjectID(c.Request.Context(), pat.UserID)
+			if err != nil {
+				response.Unauthorized(c, "User not found", "USER_NOT_FOUND")
+				c.Abort()
+				return
+			}
+
+			if user.IsSuspended() {
+				response.Forbidden(c, "Account suspended", "ACCOUNT_SUSPENDED")
+				c.Abort()
+				return
+			}
+
+			c.Set("user", user)
+			c.Set("personalAccessToken", pat)
+			c.Next()
+			return
+		}
+
 		claims, err := jwt.ValidateToken(tokenString, cfg.JWTSecret)
 		if err != nil {
 			response.Unauthorized(c, "Invalid or expired token", "INVALID_TOKEN")
@@ -44,6 +80,12 @@ func NewAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.HandlerFun
 			return
 		}
 
+		if user.IsSuspended() {
+			response.Forbidden(c, "Account suspended", "ACCOUNT_SUSPENDED")
+			c.Abort()
+			return
+		}
+
 		c.Set("user", user)
 		c.Next()
 	}
@@ -52,7 +94,8 @@ func NewAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.HandlerFun
 // OptionalAuthMiddleware attempts to authenticate but doesn't require it
 // If valid token present: sets "user" in context
 // If no token or invalid token: continues without setting user (no abort)
-func OptionalAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.HandlerFunc {
+// Personal access tokens without the declared scopes are treated as anonymous
+func OptionalAuthMiddleware(repo *auth.Repository, cfg *config.Config, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -67,6 +110,25 @@ func OptionalAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.Handl
 		}
 
 		tokenString := parts[1]
+		if auth.IsPersonalAccessToken(tokenString) {
+			pat, err := lookupPersonalAccessToken(c.Request.Context(), repo, tokenString)
+			if err != nil || pat == nil || len(scopes) == 0 || !pat.HasScopes(scopes...) {
+				c.Next()
+				return
+			}
+
+			user, err := repo.GetUserByObjectID(c.Request.Context(), pat.UserID)
+			if err != nil || user.IsSuspended() {
+				c.Next()
+				return
+			}
+
+			c.Set("user", user)
+			c.Set("personalAccessToken", pat)
+			c.Next()
+			return
+		}
+
 		claims, err := jwt.ValidateToken(tokenString, cfg.JWTSecret)
 		if err != nil {
 			// Invalid token - continue without auth (don't abort)
@@ -76,7 +138,7 @@ func OptionalAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.Handl
 		userID := claims.UserID
 
 		user, err := repo.GetUserByID(c.Request.Context(), userID)
-		if err != nil {
+		if err != nil || user.IsSuspended() {
 			c.Next()
 			return
 		}
@@ -85,3 +147,31 @@ func OptionalAuthMiddleware(repo *auth.Repository, cfg *config.Config) gin.Handl
 		c.Next()
 	}
 }
+
+// lookupPersonalAccessToken resolves an active personal access token and records its use
+func lookupPersonalAccessToken(ctx context.Context, repo *auth.Repository, tokenString string) (*auth.PersonalAccessToken, error) {
+	pat, err := repo.GetPersonalAccessTokenByHash(ctx, auth.HashPersonalAccessToken(tokenString))
+	if err != nil || pat == nil || !pat.IsActive() {
+		return nil, err
+	}
+
+	// Track last use asynchronously so it never slows down the request
+	go func() {
+		_ = repo.TouchPersonalAccessToken(context.Background(), pat.ID)
+	}()
+
+	return pat, nil
+}
+
+// TokenFromQuery lets clients that cannot set headers (browser EventSource and WebSocket)
+// pass their bearer token as ?access_token=. A header that is already present wins.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
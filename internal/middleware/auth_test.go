@@ -7,12 +7,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/config"
 )
 
 func TestAuthMiddleware_NoHeader(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	// r.Use(Auth())
+	r.Use(NewAuthMiddleware(nil, &config.Config{}))
 	r.GET("/protected", func(c *gin.Context) {
 		c.JSON(200, gin.H{"ok": true})
 	})
@@ -29,3 +30,60 @@ func TestAuthMiddleware_NoHeader(t *testing.T) {
 	require.Equal(t, float64(401), body["statusCode"])
 	require.Equal(t, "Authorization header required", body["message"])
 }
+
+func TestAuthMiddleware_InvalidFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(NewAuthMiddleware(nil, &config.Config{}, "anchors:read"))
+	r.GET("/protected", func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Token anc_pat_abc")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, 401, w.Code)
+	var body map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	require.NoError(t, err)
+	require.Equal(t, "Invalid authorization format", body["message"])
+}
+
+func TestOptionalAuthMiddleware_NoHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(OptionalAuthMiddleware(nil, &config.Config{}, "anchors:read"))
+	r.GET("/public", func(c *gin.Context) {
+		_, exists := c.Get("user")
+		c.JSON(200, gin.H{"authenticated": exists})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/public", nil)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, 200, w.Code)
+	require.JSONEq(t, `{"authenticated":false}`, w.Body.String())
+}
+
+func TestTokenFromQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(TokenFromQuery())
+	r.GET("/stream", func(c *gin.Context) {
+		c.String(200, c.GetHeader("Authorization"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/stream?access_token=abc", nil))
+	require.Equal(t, "Bearer abc", w.Body.String())
+
+	// An explicit header wins over the query parameter
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/stream?access_token=abc", nil)
+	req.Header.Set("Authorization", "Bearer xyz")
+	r.ServeHTTP(w, req)
+	require.Equal(t, "Bearer xyz", w.Body.String())
+}
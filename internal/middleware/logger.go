@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/url"
 	"strings"
 	"time"
 
@@ -69,7 +70,7 @@ func LoggerWithConfig(config LoggerConfig) gin.HandlerFunc {
 		ip := c.ClientIP()
 		userAgent := c.GetHeader("User-Agent")
 		contentType := c.GetHeader("Content-Type")
-		queryParams := c.Request.URL.RawQuery
+		queryParams := sanitizeQuery(c.Request.URL.RawQuery)
 
 		// Read and restore request body with size limits
 		var requestBody string
@@ -317,6 +318,31 @@ func sanitizeBody(body, contentType string) string {
 	return truncateString(body, 200)
 }
 
+// sanitizeQuery masks sensitive query parameters, such as an access_token passed by
+// clients that cannot set headers (EventSource, WebSocket)
+func sanitizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+
+	masked := false
+	for key := range values {
+		if isSensitiveField(strings.ToLower(key)) {
+			values[key] = []string{"********"}
+			masked = true
+		}
+	}
+	if !masked {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
 func hideSensitiveFields(data interface{}) interface{} {
 	switch v := data.(type) {
 	case map[string]interface{}:
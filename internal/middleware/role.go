@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+	"github.com/xyz-asif/gotodo/internal/pkg/response"
+)
+
+// RequireRole allows the request only if the authenticated user holds the given role
+// or a more privileged one. Must run after NewAuthMiddleware.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		val, exists := c.Get("user")
+		if !exists {
+			response.Unauthorized(c, "Authentication required", "AUTH_REQUIRED")
+			c.Abort()
+			return
+		}
+
+		user, ok := val.(*auth.User)
+		if !ok || !user.HasRole(role) {
+			response.Forbidden(c, "Insufficient permissions", "INSUFFICIENT_ROLE")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
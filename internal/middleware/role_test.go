@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/xyz-asif/gotodo/internal/features/auth"
+)
+
+func performWithRole(role string, required string) int {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user", &auth.User{Role: role})
+		c.Next()
+	})
+	r.Use(RequireRole(required))
+	r.GET("/admin", func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/admin", nil)
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequireRole(t *testing.T) {
+	require.Equal(t, 403, performWithRole("", auth.RoleModerator))
+	require.Equal(t, 403, performWithRole(auth.RoleUser, auth.RoleModerator))
+	require.Equal(t, 200, performWithRole(auth.RoleModerator, auth.RoleModerator))
+	require.Equal(t, 200, performWithRole(auth.RoleAdmin, auth.RoleModerator))
+	require.Equal(t, 403, performWithRole(auth.RoleModerator, auth.RoleAdmin))
+}
+
+func TestRequireRole_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequireRole(auth.RoleAdmin))
+	r.GET("/admin", func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/admin", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, 401, w.Code)
+}
@@ -0,0 +1,113 @@
+package jobqueue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// pollInterval is how long an idle worker waits before looking for work again
+const pollInterval = time.Second
+
+// Handler runs one job. A returned error retries the job with backoff until it runs
+// out of attempts, so handlers must be safe to run more than once.
+type Handler func(ctx context.Context, job *Job) error
+
+// Pool runs queued jobs on a fixed number of workers
+type Pool struct {
+	queue    *Queue
+	workers  int
+	handlers map[string]Handler
+}
+
+func NewPool(queue *Queue, workers int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Pool{
+		queue:    queue,
+		workers:  workers,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Handle registers the handler for a job kind. Jobs of kinds without a handler are
+// left in the queue.
+func (p *Pool) Handle(kind string, handler Handler) {
+	p.handlers[kind] = handler
+}
+
+// Run processes jobs until ctx is cancelled
+func (p *Pool) Run(ctx context.Context) {
+	kinds := make([]string, 0, len(p.handlers))
+	for kind := range p.handlers {
+		kinds = append(kinds, kind)
+	}
+	if len(kinds) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx, kinds)
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Pool) work(ctx context.Context, kinds []string) {
+	for ctx.Err() == nil {
+		job, err := p.queue.claim(ctx, kinds)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Job queue: failed to claim a job: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+
+		p.run(ctx, job)
+	}
+}
+
+func (p *Pool) run(ctx context.Context, job *Job) {
+	err := p.call(ctx, job)
+
+	// Record the outcome even when shutdown began mid-job
+	store := context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+		err = p.queue.complete(store, job)
+	case ctx.Err() != nil:
+		err = p.queue.release(store, job)
+	default:
+		log.Printf("Job queue: %s job %s failed (attempt %d/%d): %v", job.Kind, job.ID.Hex(), job.Attempts, job.MaxAttempts, err)
+		err = p.queue.fail(store, job, err)
+	}
+	if err != nil {
+		log.Printf("Job queue: failed to update %s job %s: %v", job.Kind, job.ID.Hex(), err)
+	}
+}
+
+// call runs the job's handler, turning a panic into an error
+func (p *Pool) call(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if job.Attempts > job.MaxAttempts {
+		// Claimed again after its last attempt was abandoned (e.g. the worker crashed)
+		return fmt.Errorf("abandoned after %d attempts", job.MaxAttempts)
+	}
+	return p.handlers[job.Kind](ctx, job)
+}
@@ -0,0 +1,210 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Job statuses
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed" // Out of attempts
+)
+
+const (
+	// DefaultMaxAttempts is how often a job is tried before it is marked failed
+	DefaultMaxAttempts = 5
+	// lease is how long a claimed job stays reserved; a job still running after that
+	// was left behind by a crashed worker and is claimed again
+	lease = 5 * time.Minute
+	// finishedRetention is how long finished jobs (and so their dedupe keys) are kept
+	finishedRetention = 7 * 24 * time.Hour
+	baseBackoff       = 10 * time.Second
+	maxBackoff        = time.Hour
+)
+
+// Job is a unit of background work stored in the jobs collection
+type Job struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Kind        string             `bson:"kind"`
+	Payload     bson.Raw           `bson:"payload"`
+	DedupeKey   string             `bson:"dedupeKey,omitempty"`
+	Status      string             `bson:"status"`
+	Attempts    int                `bson:"attempts"`
+	MaxAttempts int                `bson:"maxAttempts"`
+	RunAt       time.Time          `bson:"runAt"` // Not claimed before this (retry backoff)
+	LockedUntil *time.Time         `bson:"lockedUntil,omitempty"`
+	LastError   string             `bson:"lastError,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	FinishedAt  *time.Time         `bson:"finishedAt,omitempty"`
+}
+
+// Decode unmarshals the job's payload into v
+func (j *Job) Decode(v interface{}) error {
+	return bson.Unmarshal(j.Payload, v)
+}
+
+// Queue is a persistent job queue in MongoDB
+type Queue struct {
+	collection *mongo.Collection
+}
+
+func NewQueue(db *mongo.Database) *Queue {
+	collection := db.Collection("jobs")
+
+	collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			// Due jobs
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "kind", Value: 1},
+				{Key: "runAt", Value: 1},
+			},
+		},
+		{
+			// Abandoned jobs
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "lockedUntil", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "dedupeKey", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"dedupeKey": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys:    bson.D{{Key: "finishedAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(finishedRetention.Seconds())),
+		},
+	})
+
+	return &Queue{collection: collection}
+}
+
+// Enqueue adds a job that runs as soon as a worker is free. A non-empty dedupeKey makes
+// it idempotent: while a job with the same key exists, Enqueue does nothing and
+// returns false.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload interface{}, dedupeKey string) (bool, error) {
+	raw, err := bson.Marshal(payload)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	job := Job{
+		ID:          primitive.NewObjectID(),
+		Kind:        kind,
+		Payload:     raw,
+		DedupeKey:   dedupeKey,
+		Status:      StatusPending,
+		MaxAttempts: DefaultMaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+	}
+
+	if _, err := q.collection.InsertOne(ctx, job); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// claim reserves the next due job of the given kinds, nil if there is none
+func (q *Queue) claim(ctx context.Context, kinds []string) (*Job, error) {
+	now := time.Now()
+	filter := bson.M{
+		"kind": bson.M{"$in": kinds},
+		"$or": bson.A{
+			bson.M{"status": StatusPending, "runAt": bson.M{"$lte": now}},
+			bson.M{"status": StatusRunning, "lockedUntil": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": StatusRunning, "lockedUntil": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "runAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job Job
+	if err := q.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// complete marks a job done
+func (q *Queue) complete(ctx context.Context, job *Job) error {
+	_, err := q.collection.UpdateOne(ctx,
+		bson.M{"_id": job.ID, "status": StatusRunning},
+		bson.M{
+			"$set":   bson.M{"status": StatusDone, "finishedAt": time.Now()},
+			"$unset": bson.M{"lockedUntil": ""},
+		},
+	)
+	return err
+}
+
+// fail schedules a retry after Backoff, or marks the job failed once out of attempts
+func (q *Queue) fail(ctx context.Context, job *Job, jobErr error) error {
+	now := time.Now()
+	set := bson.M{"lastError": jobErr.Error()}
+	if job.Attempts >= job.MaxAttempts {
+		set["status"] = StatusFailed
+		set["finishedAt"] = now
+	} else {
+		set["status"] = StatusPending
+		set["runAt"] = now.Add(Backoff(job.Attempts))
+	}
+
+	_, err := q.collection.UpdateOne(ctx,
+		bson.M{"_id": job.ID, "status": StatusRunning},
+		bson.M{"$set": set, "$unset": bson.M{"lockedUntil": ""}},
+	)
+	return err
+}
+
+// release hands back a job interrupted by shutdown without counting the attempt
+func (q *Queue) release(ctx context.Context, job *Job) error {
+	_, err := q.collection.UpdateOne(ctx,
+		bson.M{"_id": job.ID, "status": StatusRunning},
+		bson.M{
+			"$set":   bson.M{"status": StatusPending, "runAt": time.Now()},
+			"$unset": bson.M{"lockedUntil": ""},
+			"$inc":   bson.M{"attempts": -1},
+		},
+	)
+	return err
+}
+
+// Backoff is the delay before retrying a job that failed its nth attempt: 10s, 20s,
+// 40s... up to an hour
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := baseBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,34 @@
+package jobqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, 10*time.Second, Backoff(0))
+	require.Equal(t, 10*time.Second, Backoff(1))
+	require.Equal(t, 20*time.Second, Backoff(2))
+	require.Equal(t, 80*time.Second, Backoff(4))
+	require.Equal(t, time.Hour, Backoff(20))
+}
+
+func TestJobDecode(t *testing.T) {
+	type payload struct {
+		AnchorID primitive.ObjectID `bson:"anchorId"`
+		Title    string             `bson:"title"`
+	}
+	in := payload{AnchorID: primitive.NewObjectID(), Title: "Reading list"}
+
+	raw, err := bson.Marshal(in)
+	require.NoError(t, err)
+	job := Job{Kind: "test", Payload: raw}
+
+	var out payload
+	require.NoError(t, job.Decode(&out))
+	require.Equal(t, in, out)
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer_WritesBothParts(t *testing.T) {
+	dir := t.TempDir()
+	m := NewFileMailer(dir, "Anchor <digest@example.com>")
+
+	err := m.Send(context.Background(), Message{
+		To:      "user@example.com",
+		Subject: "Your weekly digest",
+		Text:    "plain body",
+		HTML:    "<p>html body</p>",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/unsubscribe>"},
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	content := string(data)
+	require.Contains(t, content, "To: user@example.com\r\n")
+	require.Contains(t, content, "List-Unsubscribe: <https://example.com/unsubscribe>\r\n")
+	require.Contains(t, content, "multipart/alternative")
+	require.Contains(t, content, "plain body")
+	require.Contains(t, content, "<p>html body</p>")
+}
+
+func TestBuild_RejectsHeaderInjection(t *testing.T) {
+	_, err := Build("digest@example.com", Message{
+		To:      "user@example.com\r\nBcc: victim@example.com",
+		Subject: "hi",
+		Text:    "body",
+	})
+	require.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestEnvelopeAddress(t *testing.T) {
+	require.Equal(t, "digest@example.com", envelopeAddress("Anchor <digest@example.com>"))
+	require.Equal(t, "digest@example.com", envelopeAddress("digest@example.com"))
+	require.False(t, strings.Contains(envelopeAddress("A <b@c.d>"), "<"))
+}
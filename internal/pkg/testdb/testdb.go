@@ -0,0 +1,45 @@
+// Package testdb gives repository tests a throwaway MongoDB database
+package testdb
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnvURI names the variable holding the test server's connection string
+const EnvURI = "MONGODB_TEST_URI"
+
+// New connects to the server in MONGODB_TEST_URI and returns a fresh database that is
+// dropped when the test ends. The test is skipped when the variable is not set.
+func New(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv(EnvURI)
+	if uri == "" {
+		t.Skipf("%s not set", EnvURI)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("ping test database: %v", err)
+	}
+
+	db := client.Database("test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		_ = db.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+	})
+	return db
+}
@@ -0,0 +1,56 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 test secret "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode_RFCVectors(t *testing.T) {
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+	}
+
+	for unix, expected := range cases {
+		code, err := GenerateCode(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code)
+	}
+}
+
+func TestValidate_AllowsSkew(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	previous, err := GenerateCode(rfcSecret, now.Add(-Period*time.Second))
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, previous, now)
+	require.True(t, ok)
+	require.Equal(t, Step(now)-1, step)
+
+	stale, err := GenerateCode(rfcSecret, now.Add(-3*Period*time.Second))
+	require.NoError(t, err)
+	_, ok = Validate(rfcSecret, stale, now)
+	require.False(t, ok)
+}
+
+func TestValidate_RejectsMalformedCode(t *testing.T) {
+	_, ok := Validate(rfcSecret, "12345", time.Now())
+	require.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	uri := ProvisioningURI(secret, "Anchor", "jane@example.com")
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/Anchor:jane@example.com?"))
+	require.Contains(t, uri, "secret="+secret)
+	require.Contains(t, uri, "issuer=Anchor")
+}
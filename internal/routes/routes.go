@@ -2,23 +2,34 @@ package routes
 
 import (
 	"context"
+	"log"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/xyz-asif/gotodo/internal/config"
+	"github.com/xyz-asif/gotodo/internal/features/account_deletion"
+	"github.com/xyz-asif/gotodo/internal/features/admin"
 	"github.com/xyz-asif/gotodo/internal/features/anchor_follows"
 	"github.com/xyz-asif/gotodo/internal/features/anchors"
 	"github.com/xyz-asif/gotodo/internal/features/auth"
 	"github.com/xyz-asif/gotodo/internal/features/comments"
+	"github.com/xyz-asif/gotodo/internal/features/data_export"
+	"github.com/xyz-asif/gotodo/internal/features/digest"
 	"github.com/xyz-asif/gotodo/internal/features/feed"
 	"github.com/xyz-asif/gotodo/internal/features/follows"
 	"github.com/xyz-asif/gotodo/internal/features/interests"
 	"github.com/xyz-asif/gotodo/internal/features/likes"
+	"github.com/xyz-asif/gotodo/internal/features/lists"
 	"github.com/xyz-asif/gotodo/internal/features/media"
+	"github.com/xyz-asif/gotodo/internal/features/mutes"
 	"github.com/xyz-asif/gotodo/internal/features/notifications"
 	"github.com/xyz-asif/gotodo/internal/features/safety"
 	"github.com/xyz-asif/gotodo/internal/features/search"
+	"github.com/xyz-asif/gotodo/internal/features/tag_follows"
 	"github.com/xyz-asif/gotodo/internal/features/users"
+	"github.com/xyz-asif/gotodo/internal/features/webhooks"
 	"github.com/xyz-asif/gotodo/internal/pkg/cloudinary"
+	"github.com/xyz-asif/gotodo/internal/pkg/jobqueue"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -118,7 +129,7 @@ func SetupRoutes(router *gin.Engine, db *mongo.Database, cfg *config.Config) {
 
 	// Register feature routes
 	users.RegisterRoutes(api, db, cfg)
-	auth.RegisterRoutes(api, db, cfg, followService, anchorService)
+	auth.RegisterRoutes(api, db, cfg, followService, anchorService, safety.NewBlockPolicy(db))
 
 	// Set follower provider to break cycle
 	notifService := notifications.GetService(db)
@@ -136,5 +147,60 @@ func SetupRoutes(router *gin.Engine, db *mongo.Database, cfg *config.Config) {
 	feed.RegisterRoutes(api, db, cfg)
 	media.RegisterRoutes(api, db, cfg)
 	interests.RegisterRoutes(api, db, cfg)
-	safety.RegisterRoutes(api, db, cfg)
+	safety.RegisterRoutes(api, db, cfg, followsRepo)
+	mutes.RegisterRoutes(api, db, cfg)
+	tag_follows.RegisterRoutes(api, db, cfg)
+	lists.RegisterRoutes(api, db, cfg)
+	admin.RegisterRoutes(api, db, cfg)
+	data_export.RegisterRoutes(api, db, cfg)
+	digest.RegisterRoutes(api, db, cfg)
+	webhooks.RegisterRoutes(api, db, cfg)
+}
+
+// StartBackgroundJobs launches the periodic jobs; they stop when ctx is cancelled. The
+// returned WaitGroup is done once every job has returned.
+func StartBackgroundJobs(ctx context.Context, db *mongo.Database, cfg *config.Config) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	start := func(job func(context.Context)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job(ctx)
+		}()
+	}
+
+	cld, _ := cloudinary.NewService(cfg.CloudinaryCloudName, cfg.CloudinaryAPIKey, cfg.CloudinaryAPISecret, "anchor")
+	anchorService := &authAnchorServiceAdapter{repo: anchors.NewRepository(db), cld: cld}
+
+	// Copy deactivated and private accounts' state onto anchors written before it was tracked
+	start(func(ctx context.Context) {
+		if err := auth.NewRepository(db).BackfillContentVisibility(ctx); err != nil {
+			log.Printf("Content visibility backfill failed: %v", err)
+		}
+	})
+
+	// Permanently delete accounts whose restore window has passed
+	purger := account_deletion.NewPurger(db, cfg, anchorService, cld)
+	start(purger.Run)
+
+	// Remove expired data export archives
+	start(data_export.NewService(db, cfg).Run)
+
+	// Queued jobs: notification delivery, fan-out to anchor followers and webhook deliveries
+	notifService := notifications.GetService(db)
+	notifService.SetFollowerProvider(anchor_follows.NewRepository(db))
+	pool := jobqueue.NewPool(jobqueue.NewQueue(db), cfg.JobWorkers)
+	notifService.RegisterJobs(pool)
+	webhooks.NewService(db, cfg).RegisterJobs(pool)
+	start(pool.Run)
+
+	// Time-decayed hot scores for the hot and rising discover categories
+	start(feed.NewHotScorer(db, cfg).Run)
+
+	// Email digests of unread notifications, followed anchor updates and trending anchors
+	if cfg.DigestEnabled {
+		start(digest.NewService(db, cfg, digest.NewMailer(cfg)).Run)
+	}
+
+	return &wg
 }